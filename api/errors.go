@@ -0,0 +1,57 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/elkoshar/bookcabin/pkg/response"
+	"github.com/elkoshar/bookcabin/service"
+)
+
+// init fills the error catalogue with the stable code and HTTP status of every service error, clients
+// should branch on the code rather than on the message
+func init() {
+	// 400 Bad Request
+	response.Register(service.ErrInvalidItinerary, http.StatusBadRequest, "INVALID_ITINERARY", "Invalid itinerary")
+	response.Register(service.ErrInvalidOfferID, http.StatusBadRequest, "INVALID_OFFER_ID", "Invalid offer ID")
+	response.Register(service.ErrInvalidSeatSelection, http.StatusBadRequest, "INVALID_SEAT_SELECTION", "Invalid seat selection")
+	response.Register(service.ErrInvalidAncillarySelection, http.StatusBadRequest, "INVALID_ANCILLARY_SELECTION", "Invalid ancillary selection")
+	response.Register(service.ErrUnsupportedFormat, http.StatusBadRequest, "UNSUPPORTED_FORMAT", "Unsupported format")
+	response.Register(service.ErrUnsupportedCurrency, http.StatusBadRequest, "UNSUPPORTED_CURRENCY", "Unsupported currency")
+	response.Register(service.ErrInvalidPaymentEvent, http.StatusBadRequest, "INVALID_PAYMENT_EVENT", "Invalid payment notification")
+
+	// 401 Unauthorized and 402 Payment Required
+	response.Register(service.ErrInvalidSignature, http.StatusUnauthorized, "INVALID_SIGNATURE", "Invalid signature")
+	response.Register(service.ErrPaymentDeclined, http.StatusPaymentRequired, "PAYMENT_DECLINED", "Payment declined")
+
+	// 404 Not Found
+	response.Register(service.ErrOfferNotFound, http.StatusNotFound, "OFFER_NOT_FOUND", "Offer not found")
+	response.Register(service.ErrSeatMapUnavailable, http.StatusNotFound, "SEAT_MAP_UNAVAILABLE", "Seat map unavailable")
+	response.Register(service.ErrBookingNotFound, http.StatusNotFound, "BOOKING_NOT_FOUND", "Booking not found")
+	response.Register(service.ErrPaymentNotFound, http.StatusNotFound, "PAYMENT_NOT_FOUND", "Payment not found")
+	response.Register(service.ErrSearchNotFound, http.StatusNotFound, "SEARCH_NOT_FOUND", "Search not found")
+	response.Register(service.ErrSearchJobNotFound, http.StatusNotFound, "SEARCH_JOB_NOT_FOUND", "Search job not found")
+
+	// 409 Conflict
+	response.Register(service.ErrInsufficientSeats, http.StatusConflict, "INSUFFICIENT_SEATS", "Not enough seats")
+	response.Register(service.ErrOfferSoldOut, http.StatusConflict, "OFFER_SOLD_OUT", "Offer sold out")
+	response.Register(service.ErrPriceChanged, http.StatusConflict, "PRICE_CHANGED", "Price changed")
+	response.Register(service.ErrSeatUnavailable, http.StatusConflict, "SEAT_UNAVAILABLE", "Seat unavailable")
+	response.Register(service.ErrBookingNotCancellable, http.StatusConflict, "BOOKING_NOT_CANCELLABLE", "Booking not cancellable")
+	response.Register(service.ErrBookingNotHeld, http.StatusConflict, "BOOKING_NOT_HELD", "Booking no longer held")
+	response.Register(service.ErrHoldNotFound, http.StatusConflict, "HOLD_NOT_FOUND", "Hold not found")
+	response.Register(service.ErrInvalidTransition, http.StatusConflict, "INVALID_BOOKING_TRANSITION", "Invalid booking transition")
+	response.Register(service.ErrQuoteExpired, http.StatusConflict, "QUOTE_EXPIRED", "Quote expired")
+	response.Register(service.ErrPaymentPending, http.StatusConflict, "PAYMENT_PENDING", "Payment pending")
+	response.Register(service.ErrInvalidPaymentState, http.StatusConflict, "INVALID_PAYMENT_STATE", "Invalid payment state")
+	response.Register(service.ErrBookingNotTicketed, http.StatusConflict, "BOOKING_NOT_TICKETED", "Booking not ticketed")
+	response.Register(service.ErrSearchJobFinished, http.StatusConflict, "SEARCH_JOB_FINISHED", "Search job finished")
+	response.Register(ErrIdempotencyKeyInProgress, http.StatusConflict, "IDEMPOTENCY_KEY_IN_PROGRESS", "Request in progress")
+	response.Register(ErrIdempotencyKeyReused, http.StatusConflict, "IDEMPOTENCY_KEY_REUSED", "Idempotency key reused")
+
+	// 5xx, the providers behind a search failed or the server is overloaded
+	response.Register(service.ErrProvidersUnavailable, http.StatusBadGateway, "PROVIDERS_UNAVAILABLE", "Flight providers unavailable")
+	response.Register(service.ErrProvidersTimeout, http.StatusGatewayTimeout, "PROVIDERS_TIMEOUT", "Flight providers timed out")
+	response.Register(service.ErrSearchQueueFull, http.StatusServiceUnavailable, "SEARCH_QUEUE_FULL", "Search queue full")
+	response.Register(service.ErrRatesUnavailable, http.StatusServiceUnavailable, "RATES_UNAVAILABLE", "Exchange rates unavailable")
+	response.Register(service.ErrPaymentGatewayUnavailable, http.StatusServiceUnavailable, "PAYMENT_GATEWAY_UNAVAILABLE", "Payment gateway unavailable")
+}
@@ -0,0 +1,31 @@
+package api
+
+import (
+	"io"
+
+	"github.com/elkoshar/bookcabin/pkg/response"
+	"github.com/elkoshar/bookcabin/service"
+	"github.com/elkoshar/bookcabin/service/export"
+)
+
+// init registers the export formats of flight results. Handlers rendering a search response or a flight
+// mark it exportable, and can then be asked for them with the format query parameter or the Accept header.
+func init() {
+	response.RegisterFormat(response.Format{Name: "csv", ContentType: "text/csv; charset=utf-8", Extension: "csv", Encode: exportEncoder(export.CSV)})
+	response.RegisterFormat(response.Format{Name: "ndjson", ContentType: "application/x-ndjson", Extension: "ndjson", Encode: exportEncoder(export.NDJSON)})
+	response.RegisterFormat(response.Format{Name: "ics", ContentType: "text/calendar; charset=utf-8", Extension: "ics", Encode: exportEncoder(export.ICS)})
+}
+
+// exportEncoder adapts an export writer to the data of a response
+func exportEncoder(write func(io.Writer, []export.Leg) error) response.Encoder {
+	return func(w io.Writer, data interface{}) error {
+		switch data := data.(type) {
+		case service.SearchResponse:
+			return write(w, export.Legs(data))
+		case service.UnifiedFlight:
+			return write(w, []export.Leg{{Name: export.LegOutbound, Flights: []service.UnifiedFlight{data}}})
+		default:
+			return response.ErrNotEncodable
+		}
+	}
+}
@@ -0,0 +1,205 @@
+package grpc
+
+import (
+	"errors"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/elkoshar/bookcabin/api/grpc/pb"
+	"github.com/elkoshar/bookcabin/pkg/validator"
+	"github.com/elkoshar/bookcabin/service"
+)
+
+// toCriteria converts a search request and validates it with the same rules as the HTTP API
+func toCriteria(req *pb.SearchRequest) (service.SearchCriteria, error) {
+	criteria := service.SearchCriteria{
+		Origin:           req.GetOrigin(),
+		Destination:      req.GetDestination(),
+		DepartureDate:    req.GetDepartureDate(),
+		ReturnDate:       req.GetReturnDate(),
+		Passengers:       int(req.GetPassengers()),
+		CabinClass:       req.GetCabinClass(),
+		ExcludeDominated: req.GetExcludeDominated(),
+		TripType:         req.GetTripType(),
+		RefundableOnly:   req.GetRefundableOnly(),
+		NearbyRadiusKm:   req.GetNearbyRadiusKm(),
+		Currency:         req.GetCurrency(),
+	}
+	for _, s := range req.GetSegments() {
+		criteria.Segments = append(criteria.Segments, service.RouteSegment{Origin: s.GetOrigin(), Destination: s.GetDestination(), DepartureDate: s.GetDepartureDate()})
+	}
+
+	criteria.Normalize()
+	if _, err := validator.ValidateStruct(criteria); err != nil {
+		return service.SearchCriteria{}, invalidArgument(err)
+	}
+	return criteria, nil
+}
+
+// invalidArgument reports validation failures as field violations of an INVALID_ARGUMENT status
+func invalidArgument(err error) error {
+	st := status.New(codes.InvalidArgument, err.Error())
+
+	var fields validator.Errors
+	if !errors.As(err, &fields) {
+		return st.Err()
+	}
+
+	details := &errdetails.BadRequest{}
+	for _, f := range fields {
+		details.FieldViolations = append(details.FieldViolations, &errdetails.BadRequest_FieldViolation{
+			Field:       f.Field,
+			Description: f.Message,
+		})
+	}
+	if withDetails, err := st.WithDetails(details); err == nil {
+		st = withDetails
+	}
+	return st.Err()
+}
+
+func fromCriteria(c service.SearchCriteria) *pb.SearchRequest {
+	req := &pb.SearchRequest{
+		Origin:           c.Origin,
+		Destination:      c.Destination,
+		DepartureDate:    c.DepartureDate,
+		ReturnDate:       c.ReturnDate,
+		Passengers:       int32(c.Passengers),
+		CabinClass:       c.CabinClass,
+		ExcludeDominated: c.ExcludeDominated,
+		TripType:         c.TripType,
+		RefundableOnly:   c.RefundableOnly,
+		NearbyRadiusKm:   c.NearbyRadiusKm,
+		Currency:         c.Currency,
+	}
+	for _, s := range c.Segments {
+		req.Segments = append(req.Segments, &pb.RouteSegment{Origin: s.Origin, Destination: s.Destination, DepartureDate: s.DepartureDate})
+	}
+	return req
+}
+
+func toSearchResponse(resp service.SearchResponse) *pb.SearchResponse {
+	out := &pb.SearchResponse{
+		SearchId: resp.SearchID,
+		Criteria: fromCriteria(resp.Criteria),
+		Metadata: &pb.Metadata{
+			TotalResults:       int32(resp.Metadata.TotalResults),
+			ProvidersQueried:   int32(resp.Metadata.ProvidersQueried),
+			ProvidersSucceeded: int32(resp.Metadata.ProvidersSucceeded),
+			ProvidersFailed:    int32(resp.Metadata.ProvidersFailed),
+			DominatedRemoved:   int32(resp.Metadata.DominatedRemoved),
+			DuplicatesMerged:   int32(resp.Metadata.DuplicatesMerged),
+			SearchTimeMs:       resp.Metadata.SearchTimeMs,
+		},
+		Flights:       toFlights(resp.Flights),
+		ReturnFlights: toFlights(resp.ReturnFlights),
+	}
+	for _, leg := range resp.MultiCityFlights {
+		out.MultiCityFlights = append(out.MultiCityFlights, &pb.FlightList{Flights: toFlights(leg)})
+	}
+	return out
+}
+
+func toProviderResults(event service.SearchEvent) *pb.ProviderResults {
+	return &pb.ProviderResults{
+		Provider: event.Provider,
+		Flights:  toFlights(event.Flights),
+		Error:    event.Error,
+		Leg:      int32(event.Leg),
+	}
+}
+
+func toFlights(flights []service.UnifiedFlight) []*pb.Flight {
+	if len(flights) == 0 {
+		return nil
+	}
+	out := make([]*pb.Flight, len(flights))
+	for i, f := range flights {
+		out[i] = toFlight(f)
+	}
+	return out
+}
+
+func toFlight(f service.UnifiedFlight) *pb.Flight {
+	out := &pb.Flight{
+		Id:                    f.ID,
+		Provider:              f.Provider,
+		Airline:               &pb.Airline{Name: f.Airline.Name, Code: f.Airline.Code},
+		FlightNumber:          f.FlightNumber,
+		Departure:             toLocation(f.Departure),
+		Arrival:               toLocation(f.Arrival),
+		Duration:              toDuration(f.Duration),
+		Stops:                 int32(f.Stops),
+		Price:                 toPrice(f.Price),
+		AvailableSeats:        int32(f.AvailableSeats),
+		CabinClass:            f.CabinClass,
+		Amenities:             f.Amenities,
+		Aircraft:              f.Aircraft,
+		MealIncluded:          f.MealIncluded,
+		Labels:                f.Labels,
+		ParetoOptimal:         f.ParetoOptimal,
+		OperatingCarrier:      f.OperatingCarrier,
+		OperatingFlightNumber: f.OperatingFlightNumber,
+	}
+	for _, s := range f.Segments {
+		out.Segments = append(out.Segments, &pb.FlightSegment{
+			FlightNumber: s.FlightNumber,
+			Departure:    toLocation(s.Departure),
+			Arrival:      toLocation(s.Arrival),
+			Duration:     toDuration(s.Duration),
+		})
+	}
+	if b := f.Baggage; b != nil {
+		out.Baggage = &pb.Baggage{
+			CabinPieces:   int32(b.CabinPieces),
+			CabinKg:       int32(b.CabinKg),
+			CheckedPieces: int32(b.CheckedPieces),
+			CheckedKg:     int32(b.CheckedKg),
+			Note:          b.Note,
+		}
+	}
+	if r := f.FareRules; r != nil {
+		out.FareRules = &pb.FareRulesSummary{
+			Family:          r.Family,
+			Refundable:      r.Refundable,
+			Changeable:      r.Changeable,
+			ChangeFee:       toPrice(r.ChangeFee),
+			CancellationFee: toPrice(r.CancellationFee),
+		}
+	}
+	if m := f.AirportMatch; m != nil {
+		out.AirportMatch = &pb.AirportMatch{
+			RequestedOrigin:       m.RequestedOrigin,
+			RequestedDestination:  m.RequestedDestination,
+			Origin:                m.Origin,
+			Destination:           m.Destination,
+			OriginDistanceKm:      m.OriginDistanceKm,
+			DestinationDistanceKm: m.DestinationDistanceKm,
+		}
+	}
+	for _, a := range f.AlternativeOffers {
+		out.AlternativeOffers = append(out.AlternativeOffers, &pb.AlternativeOffer{Id: a.ID, Provider: a.Provider, FlightNumber: a.FlightNumber, Price: toPrice(a.Price)})
+	}
+	return out
+}
+
+func toLocation(l service.LocationInfo) *pb.Location {
+	return &pb.Location{Airport: l.Airport, City: l.City, Terminal: l.Terminal, Datetime: l.DateTime, Timestamp: l.Timestamp}
+}
+
+func toDuration(d service.DurationInfo) *pb.Duration {
+	return &pb.Duration{TotalMinutes: int32(d.TotalMinutes), Formatted: d.Formatted}
+}
+
+func toPrice(p service.PriceInfo) *pb.Price {
+	return &pb.Price{
+		Amount:           p.Amount,
+		Currency:         p.Currency,
+		Formatted:        p.Formatted,
+		OriginalAmount:   p.OriginalAmount,
+		OriginalCurrency: p.OriginalCurrency,
+		ExchangeRate:     p.ExchangeRate,
+	}
+}
@@ -0,0 +1,1685 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.10
+// 	protoc        (unknown)
+// source: flightsearch/v1/flight_search.proto
+
+package pb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// SearchRequest mirrors service.SearchCriteria.
+type SearchRequest struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Origin           string                 `protobuf:"bytes,1,opt,name=origin,proto3" json:"origin,omitempty"`
+	Destination      string                 `protobuf:"bytes,2,opt,name=destination,proto3" json:"destination,omitempty"`
+	DepartureDate    string                 `protobuf:"bytes,3,opt,name=departure_date,json=departureDate,proto3" json:"departure_date,omitempty"`
+	ReturnDate       string                 `protobuf:"bytes,4,opt,name=return_date,json=returnDate,proto3" json:"return_date,omitempty"`
+	Passengers       int32                  `protobuf:"varint,5,opt,name=passengers,proto3" json:"passengers,omitempty"`
+	CabinClass       string                 `protobuf:"bytes,6,opt,name=cabin_class,json=cabinClass,proto3" json:"cabin_class,omitempty"`
+	Segments         []*RouteSegment        `protobuf:"bytes,7,rep,name=segments,proto3" json:"segments,omitempty"`
+	ExcludeDominated bool                   `protobuf:"varint,8,opt,name=exclude_dominated,json=excludeDominated,proto3" json:"exclude_dominated,omitempty"`
+	TripType         string                 `protobuf:"bytes,9,opt,name=trip_type,json=tripType,proto3" json:"trip_type,omitempty"`
+	RefundableOnly   bool                   `protobuf:"varint,10,opt,name=refundable_only,json=refundableOnly,proto3" json:"refundable_only,omitempty"`
+	NearbyRadiusKm   float64                `protobuf:"fixed64,11,opt,name=nearby_radius_km,json=nearbyRadiusKm,proto3" json:"nearby_radius_km,omitempty"`
+	// ISO 4217 code every price is converted into, IDR when empty
+	Currency      string `protobuf:"bytes,12,opt,name=currency,proto3" json:"currency,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchRequest) Reset() {
+	*x = SearchRequest{}
+	mi := &file_flightsearch_v1_flight_search_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchRequest) ProtoMessage() {}
+
+func (x *SearchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_flightsearch_v1_flight_search_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchRequest.ProtoReflect.Descriptor instead.
+func (*SearchRequest) Descriptor() ([]byte, []int) {
+	return file_flightsearch_v1_flight_search_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *SearchRequest) GetOrigin() string {
+	if x != nil {
+		return x.Origin
+	}
+	return ""
+}
+
+func (x *SearchRequest) GetDestination() string {
+	if x != nil {
+		return x.Destination
+	}
+	return ""
+}
+
+func (x *SearchRequest) GetDepartureDate() string {
+	if x != nil {
+		return x.DepartureDate
+	}
+	return ""
+}
+
+func (x *SearchRequest) GetReturnDate() string {
+	if x != nil {
+		return x.ReturnDate
+	}
+	return ""
+}
+
+func (x *SearchRequest) GetPassengers() int32 {
+	if x != nil {
+		return x.Passengers
+	}
+	return 0
+}
+
+func (x *SearchRequest) GetCabinClass() string {
+	if x != nil {
+		return x.CabinClass
+	}
+	return ""
+}
+
+func (x *SearchRequest) GetSegments() []*RouteSegment {
+	if x != nil {
+		return x.Segments
+	}
+	return nil
+}
+
+func (x *SearchRequest) GetExcludeDominated() bool {
+	if x != nil {
+		return x.ExcludeDominated
+	}
+	return false
+}
+
+func (x *SearchRequest) GetTripType() string {
+	if x != nil {
+		return x.TripType
+	}
+	return ""
+}
+
+func (x *SearchRequest) GetRefundableOnly() bool {
+	if x != nil {
+		return x.RefundableOnly
+	}
+	return false
+}
+
+func (x *SearchRequest) GetNearbyRadiusKm() float64 {
+	if x != nil {
+		return x.NearbyRadiusKm
+	}
+	return 0
+}
+
+func (x *SearchRequest) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+type RouteSegment struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Origin        string                 `protobuf:"bytes,1,opt,name=origin,proto3" json:"origin,omitempty"`
+	Destination   string                 `protobuf:"bytes,2,opt,name=destination,proto3" json:"destination,omitempty"`
+	DepartureDate string                 `protobuf:"bytes,3,opt,name=departure_date,json=departureDate,proto3" json:"departure_date,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RouteSegment) Reset() {
+	*x = RouteSegment{}
+	mi := &file_flightsearch_v1_flight_search_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RouteSegment) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RouteSegment) ProtoMessage() {}
+
+func (x *RouteSegment) ProtoReflect() protoreflect.Message {
+	mi := &file_flightsearch_v1_flight_search_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RouteSegment.ProtoReflect.Descriptor instead.
+func (*RouteSegment) Descriptor() ([]byte, []int) {
+	return file_flightsearch_v1_flight_search_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *RouteSegment) GetOrigin() string {
+	if x != nil {
+		return x.Origin
+	}
+	return ""
+}
+
+func (x *RouteSegment) GetDestination() string {
+	if x != nil {
+		return x.Destination
+	}
+	return ""
+}
+
+func (x *RouteSegment) GetDepartureDate() string {
+	if x != nil {
+		return x.DepartureDate
+	}
+	return ""
+}
+
+// SearchResponse mirrors service.SearchResponse.
+type SearchResponse struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	SearchId         string                 `protobuf:"bytes,1,opt,name=search_id,json=searchId,proto3" json:"search_id,omitempty"`
+	Criteria         *SearchRequest         `protobuf:"bytes,2,opt,name=criteria,proto3" json:"criteria,omitempty"`
+	Metadata         *Metadata              `protobuf:"bytes,3,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	Flights          []*Flight              `protobuf:"bytes,4,rep,name=flights,proto3" json:"flights,omitempty"`
+	ReturnFlights    []*Flight              `protobuf:"bytes,5,rep,name=return_flights,json=returnFlights,proto3" json:"return_flights,omitempty"`
+	MultiCityFlights []*FlightList          `protobuf:"bytes,6,rep,name=multi_city_flights,json=multiCityFlights,proto3" json:"multi_city_flights,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *SearchResponse) Reset() {
+	*x = SearchResponse{}
+	mi := &file_flightsearch_v1_flight_search_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchResponse) ProtoMessage() {}
+
+func (x *SearchResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_flightsearch_v1_flight_search_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchResponse.ProtoReflect.Descriptor instead.
+func (*SearchResponse) Descriptor() ([]byte, []int) {
+	return file_flightsearch_v1_flight_search_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *SearchResponse) GetSearchId() string {
+	if x != nil {
+		return x.SearchId
+	}
+	return ""
+}
+
+func (x *SearchResponse) GetCriteria() *SearchRequest {
+	if x != nil {
+		return x.Criteria
+	}
+	return nil
+}
+
+func (x *SearchResponse) GetMetadata() *Metadata {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+func (x *SearchResponse) GetFlights() []*Flight {
+	if x != nil {
+		return x.Flights
+	}
+	return nil
+}
+
+func (x *SearchResponse) GetReturnFlights() []*Flight {
+	if x != nil {
+		return x.ReturnFlights
+	}
+	return nil
+}
+
+func (x *SearchResponse) GetMultiCityFlights() []*FlightList {
+	if x != nil {
+		return x.MultiCityFlights
+	}
+	return nil
+}
+
+type FlightList struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Flights       []*Flight              `protobuf:"bytes,1,rep,name=flights,proto3" json:"flights,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FlightList) Reset() {
+	*x = FlightList{}
+	mi := &file_flightsearch_v1_flight_search_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FlightList) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FlightList) ProtoMessage() {}
+
+func (x *FlightList) ProtoReflect() protoreflect.Message {
+	mi := &file_flightsearch_v1_flight_search_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FlightList.ProtoReflect.Descriptor instead.
+func (*FlightList) Descriptor() ([]byte, []int) {
+	return file_flightsearch_v1_flight_search_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *FlightList) GetFlights() []*Flight {
+	if x != nil {
+		return x.Flights
+	}
+	return nil
+}
+
+type Metadata struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	TotalResults       int32                  `protobuf:"varint,1,opt,name=total_results,json=totalResults,proto3" json:"total_results,omitempty"`
+	ProvidersQueried   int32                  `protobuf:"varint,2,opt,name=providers_queried,json=providersQueried,proto3" json:"providers_queried,omitempty"`
+	ProvidersSucceeded int32                  `protobuf:"varint,3,opt,name=providers_succeeded,json=providersSucceeded,proto3" json:"providers_succeeded,omitempty"`
+	ProvidersFailed    int32                  `protobuf:"varint,4,opt,name=providers_failed,json=providersFailed,proto3" json:"providers_failed,omitempty"`
+	DominatedRemoved   int32                  `protobuf:"varint,5,opt,name=dominated_removed,json=dominatedRemoved,proto3" json:"dominated_removed,omitempty"`
+	DuplicatesMerged   int32                  `protobuf:"varint,6,opt,name=duplicates_merged,json=duplicatesMerged,proto3" json:"duplicates_merged,omitempty"`
+	SearchTimeMs       int64                  `protobuf:"varint,7,opt,name=search_time_ms,json=searchTimeMs,proto3" json:"search_time_ms,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *Metadata) Reset() {
+	*x = Metadata{}
+	mi := &file_flightsearch_v1_flight_search_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Metadata) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Metadata) ProtoMessage() {}
+
+func (x *Metadata) ProtoReflect() protoreflect.Message {
+	mi := &file_flightsearch_v1_flight_search_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Metadata.ProtoReflect.Descriptor instead.
+func (*Metadata) Descriptor() ([]byte, []int) {
+	return file_flightsearch_v1_flight_search_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *Metadata) GetTotalResults() int32 {
+	if x != nil {
+		return x.TotalResults
+	}
+	return 0
+}
+
+func (x *Metadata) GetProvidersQueried() int32 {
+	if x != nil {
+		return x.ProvidersQueried
+	}
+	return 0
+}
+
+func (x *Metadata) GetProvidersSucceeded() int32 {
+	if x != nil {
+		return x.ProvidersSucceeded
+	}
+	return 0
+}
+
+func (x *Metadata) GetProvidersFailed() int32 {
+	if x != nil {
+		return x.ProvidersFailed
+	}
+	return 0
+}
+
+func (x *Metadata) GetDominatedRemoved() int32 {
+	if x != nil {
+		return x.DominatedRemoved
+	}
+	return 0
+}
+
+func (x *Metadata) GetDuplicatesMerged() int32 {
+	if x != nil {
+		return x.DuplicatesMerged
+	}
+	return 0
+}
+
+func (x *Metadata) GetSearchTimeMs() int64 {
+	if x != nil {
+		return x.SearchTimeMs
+	}
+	return 0
+}
+
+// StreamSearchResponse is one message of a streamed search.
+type StreamSearchResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Event:
+	//
+	//	*StreamSearchResponse_ProviderResults
+	//	*StreamSearchResponse_Summary
+	Event         isStreamSearchResponse_Event `protobuf_oneof:"event"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamSearchResponse) Reset() {
+	*x = StreamSearchResponse{}
+	mi := &file_flightsearch_v1_flight_search_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamSearchResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamSearchResponse) ProtoMessage() {}
+
+func (x *StreamSearchResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_flightsearch_v1_flight_search_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamSearchResponse.ProtoReflect.Descriptor instead.
+func (*StreamSearchResponse) Descriptor() ([]byte, []int) {
+	return file_flightsearch_v1_flight_search_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *StreamSearchResponse) GetEvent() isStreamSearchResponse_Event {
+	if x != nil {
+		return x.Event
+	}
+	return nil
+}
+
+func (x *StreamSearchResponse) GetProviderResults() *ProviderResults {
+	if x != nil {
+		if x, ok := x.Event.(*StreamSearchResponse_ProviderResults); ok {
+			return x.ProviderResults
+		}
+	}
+	return nil
+}
+
+func (x *StreamSearchResponse) GetSummary() *SearchResponse {
+	if x != nil {
+		if x, ok := x.Event.(*StreamSearchResponse_Summary); ok {
+			return x.Summary
+		}
+	}
+	return nil
+}
+
+type isStreamSearchResponse_Event interface {
+	isStreamSearchResponse_Event()
+}
+
+type StreamSearchResponse_ProviderResults struct {
+	ProviderResults *ProviderResults `protobuf:"bytes,1,opt,name=provider_results,json=providerResults,proto3,oneof"`
+}
+
+type StreamSearchResponse_Summary struct {
+	Summary *SearchResponse `protobuf:"bytes,2,opt,name=summary,proto3,oneof"`
+}
+
+func (*StreamSearchResponse_ProviderResults) isStreamSearchResponse_Event() {}
+
+func (*StreamSearchResponse_Summary) isStreamSearchResponse_Event() {}
+
+// ProviderResults carries the flights of one provider as soon as it answers, error is set when the provider failed.
+// leg is the index of the itinerary leg the provider was queried for, 0 for the outbound flight.
+type ProviderResults struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Provider      string                 `protobuf:"bytes,1,opt,name=provider,proto3" json:"provider,omitempty"`
+	Flights       []*Flight              `protobuf:"bytes,2,rep,name=flights,proto3" json:"flights,omitempty"`
+	Error         string                 `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+	Leg           int32                  `protobuf:"varint,4,opt,name=leg,proto3" json:"leg,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProviderResults) Reset() {
+	*x = ProviderResults{}
+	mi := &file_flightsearch_v1_flight_search_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProviderResults) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProviderResults) ProtoMessage() {}
+
+func (x *ProviderResults) ProtoReflect() protoreflect.Message {
+	mi := &file_flightsearch_v1_flight_search_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProviderResults.ProtoReflect.Descriptor instead.
+func (*ProviderResults) Descriptor() ([]byte, []int) {
+	return file_flightsearch_v1_flight_search_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ProviderResults) GetProvider() string {
+	if x != nil {
+		return x.Provider
+	}
+	return ""
+}
+
+func (x *ProviderResults) GetFlights() []*Flight {
+	if x != nil {
+		return x.Flights
+	}
+	return nil
+}
+
+func (x *ProviderResults) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *ProviderResults) GetLeg() int32 {
+	if x != nil {
+		return x.Leg
+	}
+	return 0
+}
+
+// Flight mirrors service.UnifiedFlight.
+type Flight struct {
+	state                 protoimpl.MessageState `protogen:"open.v1"`
+	Id                    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Provider              string                 `protobuf:"bytes,2,opt,name=provider,proto3" json:"provider,omitempty"`
+	Airline               *Airline               `protobuf:"bytes,3,opt,name=airline,proto3" json:"airline,omitempty"`
+	FlightNumber          string                 `protobuf:"bytes,4,opt,name=flight_number,json=flightNumber,proto3" json:"flight_number,omitempty"`
+	Departure             *Location              `protobuf:"bytes,5,opt,name=departure,proto3" json:"departure,omitempty"`
+	Arrival               *Location              `protobuf:"bytes,6,opt,name=arrival,proto3" json:"arrival,omitempty"`
+	Duration              *Duration              `protobuf:"bytes,7,opt,name=duration,proto3" json:"duration,omitempty"`
+	Stops                 int32                  `protobuf:"varint,8,opt,name=stops,proto3" json:"stops,omitempty"`
+	Segments              []*FlightSegment       `protobuf:"bytes,9,rep,name=segments,proto3" json:"segments,omitempty"`
+	Price                 *Price                 `protobuf:"bytes,10,opt,name=price,proto3" json:"price,omitempty"`
+	AvailableSeats        int32                  `protobuf:"varint,11,opt,name=available_seats,json=availableSeats,proto3" json:"available_seats,omitempty"`
+	CabinClass            string                 `protobuf:"bytes,12,opt,name=cabin_class,json=cabinClass,proto3" json:"cabin_class,omitempty"`
+	Amenities             []string               `protobuf:"bytes,13,rep,name=amenities,proto3" json:"amenities,omitempty"`
+	Aircraft              string                 `protobuf:"bytes,14,opt,name=aircraft,proto3" json:"aircraft,omitempty"`
+	Baggage               *Baggage               `protobuf:"bytes,15,opt,name=baggage,proto3" json:"baggage,omitempty"`
+	MealIncluded          bool                   `protobuf:"varint,16,opt,name=meal_included,json=mealIncluded,proto3" json:"meal_included,omitempty"`
+	FareRules             *FareRulesSummary      `protobuf:"bytes,17,opt,name=fare_rules,json=fareRules,proto3" json:"fare_rules,omitempty"`
+	AirportMatch          *AirportMatch          `protobuf:"bytes,18,opt,name=airport_match,json=airportMatch,proto3" json:"airport_match,omitempty"`
+	Labels                []string               `protobuf:"bytes,19,rep,name=labels,proto3" json:"labels,omitempty"`
+	ParetoOptimal         bool                   `protobuf:"varint,20,opt,name=pareto_optimal,json=paretoOptimal,proto3" json:"pareto_optimal,omitempty"`
+	OperatingCarrier      string                 `protobuf:"bytes,21,opt,name=operating_carrier,json=operatingCarrier,proto3" json:"operating_carrier,omitempty"`
+	OperatingFlightNumber string                 `protobuf:"bytes,22,opt,name=operating_flight_number,json=operatingFlightNumber,proto3" json:"operating_flight_number,omitempty"`
+	AlternativeOffers     []*AlternativeOffer    `protobuf:"bytes,23,rep,name=alternative_offers,json=alternativeOffers,proto3" json:"alternative_offers,omitempty"`
+	unknownFields         protoimpl.UnknownFields
+	sizeCache             protoimpl.SizeCache
+}
+
+func (x *Flight) Reset() {
+	*x = Flight{}
+	mi := &file_flightsearch_v1_flight_search_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Flight) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Flight) ProtoMessage() {}
+
+func (x *Flight) ProtoReflect() protoreflect.Message {
+	mi := &file_flightsearch_v1_flight_search_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Flight.ProtoReflect.Descriptor instead.
+func (*Flight) Descriptor() ([]byte, []int) {
+	return file_flightsearch_v1_flight_search_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *Flight) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Flight) GetProvider() string {
+	if x != nil {
+		return x.Provider
+	}
+	return ""
+}
+
+func (x *Flight) GetAirline() *Airline {
+	if x != nil {
+		return x.Airline
+	}
+	return nil
+}
+
+func (x *Flight) GetFlightNumber() string {
+	if x != nil {
+		return x.FlightNumber
+	}
+	return ""
+}
+
+func (x *Flight) GetDeparture() *Location {
+	if x != nil {
+		return x.Departure
+	}
+	return nil
+}
+
+func (x *Flight) GetArrival() *Location {
+	if x != nil {
+		return x.Arrival
+	}
+	return nil
+}
+
+func (x *Flight) GetDuration() *Duration {
+	if x != nil {
+		return x.Duration
+	}
+	return nil
+}
+
+func (x *Flight) GetStops() int32 {
+	if x != nil {
+		return x.Stops
+	}
+	return 0
+}
+
+func (x *Flight) GetSegments() []*FlightSegment {
+	if x != nil {
+		return x.Segments
+	}
+	return nil
+}
+
+func (x *Flight) GetPrice() *Price {
+	if x != nil {
+		return x.Price
+	}
+	return nil
+}
+
+func (x *Flight) GetAvailableSeats() int32 {
+	if x != nil {
+		return x.AvailableSeats
+	}
+	return 0
+}
+
+func (x *Flight) GetCabinClass() string {
+	if x != nil {
+		return x.CabinClass
+	}
+	return ""
+}
+
+func (x *Flight) GetAmenities() []string {
+	if x != nil {
+		return x.Amenities
+	}
+	return nil
+}
+
+func (x *Flight) GetAircraft() string {
+	if x != nil {
+		return x.Aircraft
+	}
+	return ""
+}
+
+func (x *Flight) GetBaggage() *Baggage {
+	if x != nil {
+		return x.Baggage
+	}
+	return nil
+}
+
+func (x *Flight) GetMealIncluded() bool {
+	if x != nil {
+		return x.MealIncluded
+	}
+	return false
+}
+
+func (x *Flight) GetFareRules() *FareRulesSummary {
+	if x != nil {
+		return x.FareRules
+	}
+	return nil
+}
+
+func (x *Flight) GetAirportMatch() *AirportMatch {
+	if x != nil {
+		return x.AirportMatch
+	}
+	return nil
+}
+
+func (x *Flight) GetLabels() []string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+func (x *Flight) GetParetoOptimal() bool {
+	if x != nil {
+		return x.ParetoOptimal
+	}
+	return false
+}
+
+func (x *Flight) GetOperatingCarrier() string {
+	if x != nil {
+		return x.OperatingCarrier
+	}
+	return ""
+}
+
+func (x *Flight) GetOperatingFlightNumber() string {
+	if x != nil {
+		return x.OperatingFlightNumber
+	}
+	return ""
+}
+
+func (x *Flight) GetAlternativeOffers() []*AlternativeOffer {
+	if x != nil {
+		return x.AlternativeOffers
+	}
+	return nil
+}
+
+type Airline struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Code          string                 `protobuf:"bytes,2,opt,name=code,proto3" json:"code,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Airline) Reset() {
+	*x = Airline{}
+	mi := &file_flightsearch_v1_flight_search_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Airline) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Airline) ProtoMessage() {}
+
+func (x *Airline) ProtoReflect() protoreflect.Message {
+	mi := &file_flightsearch_v1_flight_search_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Airline.ProtoReflect.Descriptor instead.
+func (*Airline) Descriptor() ([]byte, []int) {
+	return file_flightsearch_v1_flight_search_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *Airline) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Airline) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+type Location struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Airport       string                 `protobuf:"bytes,1,opt,name=airport,proto3" json:"airport,omitempty"`
+	City          string                 `protobuf:"bytes,2,opt,name=city,proto3" json:"city,omitempty"`
+	Terminal      string                 `protobuf:"bytes,3,opt,name=terminal,proto3" json:"terminal,omitempty"`
+	Datetime      string                 `protobuf:"bytes,4,opt,name=datetime,proto3" json:"datetime,omitempty"`
+	Timestamp     int64                  `protobuf:"varint,5,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Location) Reset() {
+	*x = Location{}
+	mi := &file_flightsearch_v1_flight_search_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Location) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Location) ProtoMessage() {}
+
+func (x *Location) ProtoReflect() protoreflect.Message {
+	mi := &file_flightsearch_v1_flight_search_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Location.ProtoReflect.Descriptor instead.
+func (*Location) Descriptor() ([]byte, []int) {
+	return file_flightsearch_v1_flight_search_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *Location) GetAirport() string {
+	if x != nil {
+		return x.Airport
+	}
+	return ""
+}
+
+func (x *Location) GetCity() string {
+	if x != nil {
+		return x.City
+	}
+	return ""
+}
+
+func (x *Location) GetTerminal() string {
+	if x != nil {
+		return x.Terminal
+	}
+	return ""
+}
+
+func (x *Location) GetDatetime() string {
+	if x != nil {
+		return x.Datetime
+	}
+	return ""
+}
+
+func (x *Location) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+type Duration struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TotalMinutes  int32                  `protobuf:"varint,1,opt,name=total_minutes,json=totalMinutes,proto3" json:"total_minutes,omitempty"`
+	Formatted     string                 `protobuf:"bytes,2,opt,name=formatted,proto3" json:"formatted,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Duration) Reset() {
+	*x = Duration{}
+	mi := &file_flightsearch_v1_flight_search_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Duration) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Duration) ProtoMessage() {}
+
+func (x *Duration) ProtoReflect() protoreflect.Message {
+	mi := &file_flightsearch_v1_flight_search_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Duration.ProtoReflect.Descriptor instead.
+func (*Duration) Descriptor() ([]byte, []int) {
+	return file_flightsearch_v1_flight_search_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *Duration) GetTotalMinutes() int32 {
+	if x != nil {
+		return x.TotalMinutes
+	}
+	return 0
+}
+
+func (x *Duration) GetFormatted() string {
+	if x != nil {
+		return x.Formatted
+	}
+	return ""
+}
+
+type FlightSegment struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	FlightNumber  string                 `protobuf:"bytes,1,opt,name=flight_number,json=flightNumber,proto3" json:"flight_number,omitempty"`
+	Departure     *Location              `protobuf:"bytes,2,opt,name=departure,proto3" json:"departure,omitempty"`
+	Arrival       *Location              `protobuf:"bytes,3,opt,name=arrival,proto3" json:"arrival,omitempty"`
+	Duration      *Duration              `protobuf:"bytes,4,opt,name=duration,proto3" json:"duration,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FlightSegment) Reset() {
+	*x = FlightSegment{}
+	mi := &file_flightsearch_v1_flight_search_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FlightSegment) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FlightSegment) ProtoMessage() {}
+
+func (x *FlightSegment) ProtoReflect() protoreflect.Message {
+	mi := &file_flightsearch_v1_flight_search_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FlightSegment.ProtoReflect.Descriptor instead.
+func (*FlightSegment) Descriptor() ([]byte, []int) {
+	return file_flightsearch_v1_flight_search_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *FlightSegment) GetFlightNumber() string {
+	if x != nil {
+		return x.FlightNumber
+	}
+	return ""
+}
+
+func (x *FlightSegment) GetDeparture() *Location {
+	if x != nil {
+		return x.Departure
+	}
+	return nil
+}
+
+func (x *FlightSegment) GetArrival() *Location {
+	if x != nil {
+		return x.Arrival
+	}
+	return nil
+}
+
+func (x *FlightSegment) GetDuration() *Duration {
+	if x != nil {
+		return x.Duration
+	}
+	return nil
+}
+
+type Price struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Amount    float64                `protobuf:"fixed64,1,opt,name=amount,proto3" json:"amount,omitempty"`
+	Currency  string                 `protobuf:"bytes,2,opt,name=currency,proto3" json:"currency,omitempty"`
+	Formatted string                 `protobuf:"bytes,3,opt,name=formatted,proto3" json:"formatted,omitempty"`
+	// what the provider sold the fare for, set when the price was converted
+	OriginalAmount   float64 `protobuf:"fixed64,4,opt,name=original_amount,json=originalAmount,proto3" json:"original_amount,omitempty"`
+	OriginalCurrency string  `protobuf:"bytes,5,opt,name=original_currency,json=originalCurrency,proto3" json:"original_currency,omitempty"`
+	ExchangeRate     float64 `protobuf:"fixed64,6,opt,name=exchange_rate,json=exchangeRate,proto3" json:"exchange_rate,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *Price) Reset() {
+	*x = Price{}
+	mi := &file_flightsearch_v1_flight_search_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Price) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Price) ProtoMessage() {}
+
+func (x *Price) ProtoReflect() protoreflect.Message {
+	mi := &file_flightsearch_v1_flight_search_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Price.ProtoReflect.Descriptor instead.
+func (*Price) Descriptor() ([]byte, []int) {
+	return file_flightsearch_v1_flight_search_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *Price) GetAmount() float64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *Price) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *Price) GetFormatted() string {
+	if x != nil {
+		return x.Formatted
+	}
+	return ""
+}
+
+func (x *Price) GetOriginalAmount() float64 {
+	if x != nil {
+		return x.OriginalAmount
+	}
+	return 0
+}
+
+func (x *Price) GetOriginalCurrency() string {
+	if x != nil {
+		return x.OriginalCurrency
+	}
+	return ""
+}
+
+func (x *Price) GetExchangeRate() float64 {
+	if x != nil {
+		return x.ExchangeRate
+	}
+	return 0
+}
+
+type Baggage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CabinPieces   int32                  `protobuf:"varint,1,opt,name=cabin_pieces,json=cabinPieces,proto3" json:"cabin_pieces,omitempty"`
+	CabinKg       int32                  `protobuf:"varint,2,opt,name=cabin_kg,json=cabinKg,proto3" json:"cabin_kg,omitempty"`
+	CheckedPieces int32                  `protobuf:"varint,3,opt,name=checked_pieces,json=checkedPieces,proto3" json:"checked_pieces,omitempty"`
+	CheckedKg     int32                  `protobuf:"varint,4,opt,name=checked_kg,json=checkedKg,proto3" json:"checked_kg,omitempty"`
+	Note          string                 `protobuf:"bytes,5,opt,name=note,proto3" json:"note,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Baggage) Reset() {
+	*x = Baggage{}
+	mi := &file_flightsearch_v1_flight_search_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Baggage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Baggage) ProtoMessage() {}
+
+func (x *Baggage) ProtoReflect() protoreflect.Message {
+	mi := &file_flightsearch_v1_flight_search_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Baggage.ProtoReflect.Descriptor instead.
+func (*Baggage) Descriptor() ([]byte, []int) {
+	return file_flightsearch_v1_flight_search_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *Baggage) GetCabinPieces() int32 {
+	if x != nil {
+		return x.CabinPieces
+	}
+	return 0
+}
+
+func (x *Baggage) GetCabinKg() int32 {
+	if x != nil {
+		return x.CabinKg
+	}
+	return 0
+}
+
+func (x *Baggage) GetCheckedPieces() int32 {
+	if x != nil {
+		return x.CheckedPieces
+	}
+	return 0
+}
+
+func (x *Baggage) GetCheckedKg() int32 {
+	if x != nil {
+		return x.CheckedKg
+	}
+	return 0
+}
+
+func (x *Baggage) GetNote() string {
+	if x != nil {
+		return x.Note
+	}
+	return ""
+}
+
+type FareRulesSummary struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Family          string                 `protobuf:"bytes,1,opt,name=family,proto3" json:"family,omitempty"`
+	Refundable      bool                   `protobuf:"varint,2,opt,name=refundable,proto3" json:"refundable,omitempty"`
+	Changeable      bool                   `protobuf:"varint,3,opt,name=changeable,proto3" json:"changeable,omitempty"`
+	ChangeFee       *Price                 `protobuf:"bytes,4,opt,name=change_fee,json=changeFee,proto3" json:"change_fee,omitempty"`
+	CancellationFee *Price                 `protobuf:"bytes,5,opt,name=cancellation_fee,json=cancellationFee,proto3" json:"cancellation_fee,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *FareRulesSummary) Reset() {
+	*x = FareRulesSummary{}
+	mi := &file_flightsearch_v1_flight_search_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FareRulesSummary) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FareRulesSummary) ProtoMessage() {}
+
+func (x *FareRulesSummary) ProtoReflect() protoreflect.Message {
+	mi := &file_flightsearch_v1_flight_search_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FareRulesSummary.ProtoReflect.Descriptor instead.
+func (*FareRulesSummary) Descriptor() ([]byte, []int) {
+	return file_flightsearch_v1_flight_search_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *FareRulesSummary) GetFamily() string {
+	if x != nil {
+		return x.Family
+	}
+	return ""
+}
+
+func (x *FareRulesSummary) GetRefundable() bool {
+	if x != nil {
+		return x.Refundable
+	}
+	return false
+}
+
+func (x *FareRulesSummary) GetChangeable() bool {
+	if x != nil {
+		return x.Changeable
+	}
+	return false
+}
+
+func (x *FareRulesSummary) GetChangeFee() *Price {
+	if x != nil {
+		return x.ChangeFee
+	}
+	return nil
+}
+
+func (x *FareRulesSummary) GetCancellationFee() *Price {
+	if x != nil {
+		return x.CancellationFee
+	}
+	return nil
+}
+
+type AirportMatch struct {
+	state                 protoimpl.MessageState `protogen:"open.v1"`
+	RequestedOrigin       string                 `protobuf:"bytes,1,opt,name=requested_origin,json=requestedOrigin,proto3" json:"requested_origin,omitempty"`
+	RequestedDestination  string                 `protobuf:"bytes,2,opt,name=requested_destination,json=requestedDestination,proto3" json:"requested_destination,omitempty"`
+	Origin                string                 `protobuf:"bytes,3,opt,name=origin,proto3" json:"origin,omitempty"`
+	Destination           string                 `protobuf:"bytes,4,opt,name=destination,proto3" json:"destination,omitempty"`
+	OriginDistanceKm      float64                `protobuf:"fixed64,5,opt,name=origin_distance_km,json=originDistanceKm,proto3" json:"origin_distance_km,omitempty"`
+	DestinationDistanceKm float64                `protobuf:"fixed64,6,opt,name=destination_distance_km,json=destinationDistanceKm,proto3" json:"destination_distance_km,omitempty"`
+	unknownFields         protoimpl.UnknownFields
+	sizeCache             protoimpl.SizeCache
+}
+
+func (x *AirportMatch) Reset() {
+	*x = AirportMatch{}
+	mi := &file_flightsearch_v1_flight_search_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AirportMatch) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AirportMatch) ProtoMessage() {}
+
+func (x *AirportMatch) ProtoReflect() protoreflect.Message {
+	mi := &file_flightsearch_v1_flight_search_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AirportMatch.ProtoReflect.Descriptor instead.
+func (*AirportMatch) Descriptor() ([]byte, []int) {
+	return file_flightsearch_v1_flight_search_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *AirportMatch) GetRequestedOrigin() string {
+	if x != nil {
+		return x.RequestedOrigin
+	}
+	return ""
+}
+
+func (x *AirportMatch) GetRequestedDestination() string {
+	if x != nil {
+		return x.RequestedDestination
+	}
+	return ""
+}
+
+func (x *AirportMatch) GetOrigin() string {
+	if x != nil {
+		return x.Origin
+	}
+	return ""
+}
+
+func (x *AirportMatch) GetDestination() string {
+	if x != nil {
+		return x.Destination
+	}
+	return ""
+}
+
+func (x *AirportMatch) GetOriginDistanceKm() float64 {
+	if x != nil {
+		return x.OriginDistanceKm
+	}
+	return 0
+}
+
+func (x *AirportMatch) GetDestinationDistanceKm() float64 {
+	if x != nil {
+		return x.DestinationDistanceKm
+	}
+	return 0
+}
+
+type AlternativeOffer struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Provider      string                 `protobuf:"bytes,2,opt,name=provider,proto3" json:"provider,omitempty"`
+	FlightNumber  string                 `protobuf:"bytes,3,opt,name=flight_number,json=flightNumber,proto3" json:"flight_number,omitempty"`
+	Price         *Price                 `protobuf:"bytes,4,opt,name=price,proto3" json:"price,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AlternativeOffer) Reset() {
+	*x = AlternativeOffer{}
+	mi := &file_flightsearch_v1_flight_search_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AlternativeOffer) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AlternativeOffer) ProtoMessage() {}
+
+func (x *AlternativeOffer) ProtoReflect() protoreflect.Message {
+	mi := &file_flightsearch_v1_flight_search_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AlternativeOffer.ProtoReflect.Descriptor instead.
+func (*AlternativeOffer) Descriptor() ([]byte, []int) {
+	return file_flightsearch_v1_flight_search_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *AlternativeOffer) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *AlternativeOffer) GetProvider() string {
+	if x != nil {
+		return x.Provider
+	}
+	return ""
+}
+
+func (x *AlternativeOffer) GetFlightNumber() string {
+	if x != nil {
+		return x.FlightNumber
+	}
+	return ""
+}
+
+func (x *AlternativeOffer) GetPrice() *Price {
+	if x != nil {
+		return x.Price
+	}
+	return nil
+}
+
+var File_flightsearch_v1_flight_search_proto protoreflect.FileDescriptor
+
+const file_flightsearch_v1_flight_search_proto_rawDesc = "" +
+	"\n" +
+	"#flightsearch/v1/flight_search.proto\x12\x19bookcabin.flightsearch.v1\"\xd0\x03\n" +
+	"\rSearchRequest\x12\x16\n" +
+	"\x06origin\x18\x01 \x01(\tR\x06origin\x12 \n" +
+	"\vdestination\x18\x02 \x01(\tR\vdestination\x12%\n" +
+	"\x0edeparture_date\x18\x03 \x01(\tR\rdepartureDate\x12\x1f\n" +
+	"\vreturn_date\x18\x04 \x01(\tR\n" +
+	"returnDate\x12\x1e\n" +
+	"\n" +
+	"passengers\x18\x05 \x01(\x05R\n" +
+	"passengers\x12\x1f\n" +
+	"\vcabin_class\x18\x06 \x01(\tR\n" +
+	"cabinClass\x12C\n" +
+	"\bsegments\x18\a \x03(\v2'.bookcabin.flightsearch.v1.RouteSegmentR\bsegments\x12+\n" +
+	"\x11exclude_dominated\x18\b \x01(\bR\x10excludeDominated\x12\x1b\n" +
+	"\ttrip_type\x18\t \x01(\tR\btripType\x12'\n" +
+	"\x0frefundable_only\x18\n" +
+	" \x01(\bR\x0erefundableOnly\x12(\n" +
+	"\x10nearby_radius_km\x18\v \x01(\x01R\x0enearbyRadiusKm\x12\x1a\n" +
+	"\bcurrency\x18\f \x01(\tR\bcurrency\"o\n" +
+	"\fRouteSegment\x12\x16\n" +
+	"\x06origin\x18\x01 \x01(\tR\x06origin\x12 \n" +
+	"\vdestination\x18\x02 \x01(\tR\vdestination\x12%\n" +
+	"\x0edeparture_date\x18\x03 \x01(\tR\rdepartureDate\"\x90\x03\n" +
+	"\x0eSearchResponse\x12\x1b\n" +
+	"\tsearch_id\x18\x01 \x01(\tR\bsearchId\x12D\n" +
+	"\bcriteria\x18\x02 \x01(\v2(.bookcabin.flightsearch.v1.SearchRequestR\bcriteria\x12?\n" +
+	"\bmetadata\x18\x03 \x01(\v2#.bookcabin.flightsearch.v1.MetadataR\bmetadata\x12;\n" +
+	"\aflights\x18\x04 \x03(\v2!.bookcabin.flightsearch.v1.FlightR\aflights\x12H\n" +
+	"\x0ereturn_flights\x18\x05 \x03(\v2!.bookcabin.flightsearch.v1.FlightR\rreturnFlights\x12S\n" +
+	"\x12multi_city_flights\x18\x06 \x03(\v2%.bookcabin.flightsearch.v1.FlightListR\x10multiCityFlights\"I\n" +
+	"\n" +
+	"FlightList\x12;\n" +
+	"\aflights\x18\x01 \x03(\v2!.bookcabin.flightsearch.v1.FlightR\aflights\"\xb8\x02\n" +
+	"\bMetadata\x12#\n" +
+	"\rtotal_results\x18\x01 \x01(\x05R\ftotalResults\x12+\n" +
+	"\x11providers_queried\x18\x02 \x01(\x05R\x10providersQueried\x12/\n" +
+	"\x13providers_succeeded\x18\x03 \x01(\x05R\x12providersSucceeded\x12)\n" +
+	"\x10providers_failed\x18\x04 \x01(\x05R\x0fprovidersFailed\x12+\n" +
+	"\x11dominated_removed\x18\x05 \x01(\x05R\x10dominatedRemoved\x12+\n" +
+	"\x11duplicates_merged\x18\x06 \x01(\x05R\x10duplicatesMerged\x12$\n" +
+	"\x0esearch_time_ms\x18\a \x01(\x03R\fsearchTimeMs\"\xbf\x01\n" +
+	"\x14StreamSearchResponse\x12W\n" +
+	"\x10provider_results\x18\x01 \x01(\v2*.bookcabin.flightsearch.v1.ProviderResultsH\x00R\x0fproviderResults\x12E\n" +
+	"\asummary\x18\x02 \x01(\v2).bookcabin.flightsearch.v1.SearchResponseH\x00R\asummaryB\a\n" +
+	"\x05event\"\x92\x01\n" +
+	"\x0fProviderResults\x12\x1a\n" +
+	"\bprovider\x18\x01 \x01(\tR\bprovider\x12;\n" +
+	"\aflights\x18\x02 \x03(\v2!.bookcabin.flightsearch.v1.FlightR\aflights\x12\x14\n" +
+	"\x05error\x18\x03 \x01(\tR\x05error\x12\x10\n" +
+	"\x03leg\x18\x04 \x01(\x05R\x03leg\"\xef\b\n" +
+	"\x06Flight\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1a\n" +
+	"\bprovider\x18\x02 \x01(\tR\bprovider\x12<\n" +
+	"\aairline\x18\x03 \x01(\v2\".bookcabin.flightsearch.v1.AirlineR\aairline\x12#\n" +
+	"\rflight_number\x18\x04 \x01(\tR\fflightNumber\x12A\n" +
+	"\tdeparture\x18\x05 \x01(\v2#.bookcabin.flightsearch.v1.LocationR\tdeparture\x12=\n" +
+	"\aarrival\x18\x06 \x01(\v2#.bookcabin.flightsearch.v1.LocationR\aarrival\x12?\n" +
+	"\bduration\x18\a \x01(\v2#.bookcabin.flightsearch.v1.DurationR\bduration\x12\x14\n" +
+	"\x05stops\x18\b \x01(\x05R\x05stops\x12D\n" +
+	"\bsegments\x18\t \x03(\v2(.bookcabin.flightsearch.v1.FlightSegmentR\bsegments\x126\n" +
+	"\x05price\x18\n" +
+	" \x01(\v2 .bookcabin.flightsearch.v1.PriceR\x05price\x12'\n" +
+	"\x0favailable_seats\x18\v \x01(\x05R\x0eavailableSeats\x12\x1f\n" +
+	"\vcabin_class\x18\f \x01(\tR\n" +
+	"cabinClass\x12\x1c\n" +
+	"\tamenities\x18\r \x03(\tR\tamenities\x12\x1a\n" +
+	"\baircraft\x18\x0e \x01(\tR\baircraft\x12<\n" +
+	"\abaggage\x18\x0f \x01(\v2\".bookcabin.flightsearch.v1.BaggageR\abaggage\x12#\n" +
+	"\rmeal_included\x18\x10 \x01(\bR\fmealIncluded\x12J\n" +
+	"\n" +
+	"fare_rules\x18\x11 \x01(\v2+.bookcabin.flightsearch.v1.FareRulesSummaryR\tfareRules\x12L\n" +
+	"\rairport_match\x18\x12 \x01(\v2'.bookcabin.flightsearch.v1.AirportMatchR\fairportMatch\x12\x16\n" +
+	"\x06labels\x18\x13 \x03(\tR\x06labels\x12%\n" +
+	"\x0epareto_optimal\x18\x14 \x01(\bR\rparetoOptimal\x12+\n" +
+	"\x11operating_carrier\x18\x15 \x01(\tR\x10operatingCarrier\x126\n" +
+	"\x17operating_flight_number\x18\x16 \x01(\tR\x15operatingFlightNumber\x12Z\n" +
+	"\x12alternative_offers\x18\x17 \x03(\v2+.bookcabin.flightsearch.v1.AlternativeOfferR\x11alternativeOffers\"1\n" +
+	"\aAirline\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x12\n" +
+	"\x04code\x18\x02 \x01(\tR\x04code\"\x8e\x01\n" +
+	"\bLocation\x12\x18\n" +
+	"\aairport\x18\x01 \x01(\tR\aairport\x12\x12\n" +
+	"\x04city\x18\x02 \x01(\tR\x04city\x12\x1a\n" +
+	"\bterminal\x18\x03 \x01(\tR\bterminal\x12\x1a\n" +
+	"\bdatetime\x18\x04 \x01(\tR\bdatetime\x12\x1c\n" +
+	"\ttimestamp\x18\x05 \x01(\x03R\ttimestamp\"M\n" +
+	"\bDuration\x12#\n" +
+	"\rtotal_minutes\x18\x01 \x01(\x05R\ftotalMinutes\x12\x1c\n" +
+	"\tformatted\x18\x02 \x01(\tR\tformatted\"\xf7\x01\n" +
+	"\rFlightSegment\x12#\n" +
+	"\rflight_number\x18\x01 \x01(\tR\fflightNumber\x12A\n" +
+	"\tdeparture\x18\x02 \x01(\v2#.bookcabin.flightsearch.v1.LocationR\tdeparture\x12=\n" +
+	"\aarrival\x18\x03 \x01(\v2#.bookcabin.flightsearch.v1.LocationR\aarrival\x12?\n" +
+	"\bduration\x18\x04 \x01(\v2#.bookcabin.flightsearch.v1.DurationR\bduration\"\xd4\x01\n" +
+	"\x05Price\x12\x16\n" +
+	"\x06amount\x18\x01 \x01(\x01R\x06amount\x12\x1a\n" +
+	"\bcurrency\x18\x02 \x01(\tR\bcurrency\x12\x1c\n" +
+	"\tformatted\x18\x03 \x01(\tR\tformatted\x12'\n" +
+	"\x0foriginal_amount\x18\x04 \x01(\x01R\x0eoriginalAmount\x12+\n" +
+	"\x11original_currency\x18\x05 \x01(\tR\x10originalCurrency\x12#\n" +
+	"\rexchange_rate\x18\x06 \x01(\x01R\fexchangeRate\"\xa1\x01\n" +
+	"\aBaggage\x12!\n" +
+	"\fcabin_pieces\x18\x01 \x01(\x05R\vcabinPieces\x12\x19\n" +
+	"\bcabin_kg\x18\x02 \x01(\x05R\acabinKg\x12%\n" +
+	"\x0echecked_pieces\x18\x03 \x01(\x05R\rcheckedPieces\x12\x1d\n" +
+	"\n" +
+	"checked_kg\x18\x04 \x01(\x05R\tcheckedKg\x12\x12\n" +
+	"\x04note\x18\x05 \x01(\tR\x04note\"\xf8\x01\n" +
+	"\x10FareRulesSummary\x12\x16\n" +
+	"\x06family\x18\x01 \x01(\tR\x06family\x12\x1e\n" +
+	"\n" +
+	"refundable\x18\x02 \x01(\bR\n" +
+	"refundable\x12\x1e\n" +
+	"\n" +
+	"changeable\x18\x03 \x01(\bR\n" +
+	"changeable\x12?\n" +
+	"\n" +
+	"change_fee\x18\x04 \x01(\v2 .bookcabin.flightsearch.v1.PriceR\tchangeFee\x12K\n" +
+	"\x10cancellation_fee\x18\x05 \x01(\v2 .bookcabin.flightsearch.v1.PriceR\x0fcancellationFee\"\x8e\x02\n" +
+	"\fAirportMatch\x12)\n" +
+	"\x10requested_origin\x18\x01 \x01(\tR\x0frequestedOrigin\x123\n" +
+	"\x15requested_destination\x18\x02 \x01(\tR\x14requestedDestination\x12\x16\n" +
+	"\x06origin\x18\x03 \x01(\tR\x06origin\x12 \n" +
+	"\vdestination\x18\x04 \x01(\tR\vdestination\x12,\n" +
+	"\x12origin_distance_km\x18\x05 \x01(\x01R\x10originDistanceKm\x126\n" +
+	"\x17destination_distance_km\x18\x06 \x01(\x01R\x15destinationDistanceKm\"\x9b\x01\n" +
+	"\x10AlternativeOffer\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1a\n" +
+	"\bprovider\x18\x02 \x01(\tR\bprovider\x12#\n" +
+	"\rflight_number\x18\x03 \x01(\tR\fflightNumber\x126\n" +
+	"\x05price\x18\x04 \x01(\v2 .bookcabin.flightsearch.v1.PriceR\x05price2\xda\x01\n" +
+	"\fFlightSearch\x12]\n" +
+	"\x06Search\x12(.bookcabin.flightsearch.v1.SearchRequest\x1a).bookcabin.flightsearch.v1.SearchResponse\x12k\n" +
+	"\fStreamSearch\x12(.bookcabin.flightsearch.v1.SearchRequest\x1a/.bookcabin.flightsearch.v1.StreamSearchResponse0\x01B.Z,github.com/elkoshar/bookcabin/api/grpc/pb;pbb\x06proto3"
+
+var (
+	file_flightsearch_v1_flight_search_proto_rawDescOnce sync.Once
+	file_flightsearch_v1_flight_search_proto_rawDescData []byte
+)
+
+func file_flightsearch_v1_flight_search_proto_rawDescGZIP() []byte {
+	file_flightsearch_v1_flight_search_proto_rawDescOnce.Do(func() {
+		file_flightsearch_v1_flight_search_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_flightsearch_v1_flight_search_proto_rawDesc), len(file_flightsearch_v1_flight_search_proto_rawDesc)))
+	})
+	return file_flightsearch_v1_flight_search_proto_rawDescData
+}
+
+var file_flightsearch_v1_flight_search_proto_msgTypes = make([]protoimpl.MessageInfo, 17)
+var file_flightsearch_v1_flight_search_proto_goTypes = []any{
+	(*SearchRequest)(nil),        // 0: bookcabin.flightsearch.v1.SearchRequest
+	(*RouteSegment)(nil),         // 1: bookcabin.flightsearch.v1.RouteSegment
+	(*SearchResponse)(nil),       // 2: bookcabin.flightsearch.v1.SearchResponse
+	(*FlightList)(nil),           // 3: bookcabin.flightsearch.v1.FlightList
+	(*Metadata)(nil),             // 4: bookcabin.flightsearch.v1.Metadata
+	(*StreamSearchResponse)(nil), // 5: bookcabin.flightsearch.v1.StreamSearchResponse
+	(*ProviderResults)(nil),      // 6: bookcabin.flightsearch.v1.ProviderResults
+	(*Flight)(nil),               // 7: bookcabin.flightsearch.v1.Flight
+	(*Airline)(nil),              // 8: bookcabin.flightsearch.v1.Airline
+	(*Location)(nil),             // 9: bookcabin.flightsearch.v1.Location
+	(*Duration)(nil),             // 10: bookcabin.flightsearch.v1.Duration
+	(*FlightSegment)(nil),        // 11: bookcabin.flightsearch.v1.FlightSegment
+	(*Price)(nil),                // 12: bookcabin.flightsearch.v1.Price
+	(*Baggage)(nil),              // 13: bookcabin.flightsearch.v1.Baggage
+	(*FareRulesSummary)(nil),     // 14: bookcabin.flightsearch.v1.FareRulesSummary
+	(*AirportMatch)(nil),         // 15: bookcabin.flightsearch.v1.AirportMatch
+	(*AlternativeOffer)(nil),     // 16: bookcabin.flightsearch.v1.AlternativeOffer
+}
+var file_flightsearch_v1_flight_search_proto_depIdxs = []int32{
+	1,  // 0: bookcabin.flightsearch.v1.SearchRequest.segments:type_name -> bookcabin.flightsearch.v1.RouteSegment
+	0,  // 1: bookcabin.flightsearch.v1.SearchResponse.criteria:type_name -> bookcabin.flightsearch.v1.SearchRequest
+	4,  // 2: bookcabin.flightsearch.v1.SearchResponse.metadata:type_name -> bookcabin.flightsearch.v1.Metadata
+	7,  // 3: bookcabin.flightsearch.v1.SearchResponse.flights:type_name -> bookcabin.flightsearch.v1.Flight
+	7,  // 4: bookcabin.flightsearch.v1.SearchResponse.return_flights:type_name -> bookcabin.flightsearch.v1.Flight
+	3,  // 5: bookcabin.flightsearch.v1.SearchResponse.multi_city_flights:type_name -> bookcabin.flightsearch.v1.FlightList
+	7,  // 6: bookcabin.flightsearch.v1.FlightList.flights:type_name -> bookcabin.flightsearch.v1.Flight
+	6,  // 7: bookcabin.flightsearch.v1.StreamSearchResponse.provider_results:type_name -> bookcabin.flightsearch.v1.ProviderResults
+	2,  // 8: bookcabin.flightsearch.v1.StreamSearchResponse.summary:type_name -> bookcabin.flightsearch.v1.SearchResponse
+	7,  // 9: bookcabin.flightsearch.v1.ProviderResults.flights:type_name -> bookcabin.flightsearch.v1.Flight
+	8,  // 10: bookcabin.flightsearch.v1.Flight.airline:type_name -> bookcabin.flightsearch.v1.Airline
+	9,  // 11: bookcabin.flightsearch.v1.Flight.departure:type_name -> bookcabin.flightsearch.v1.Location
+	9,  // 12: bookcabin.flightsearch.v1.Flight.arrival:type_name -> bookcabin.flightsearch.v1.Location
+	10, // 13: bookcabin.flightsearch.v1.Flight.duration:type_name -> bookcabin.flightsearch.v1.Duration
+	11, // 14: bookcabin.flightsearch.v1.Flight.segments:type_name -> bookcabin.flightsearch.v1.FlightSegment
+	12, // 15: bookcabin.flightsearch.v1.Flight.price:type_name -> bookcabin.flightsearch.v1.Price
+	13, // 16: bookcabin.flightsearch.v1.Flight.baggage:type_name -> bookcabin.flightsearch.v1.Baggage
+	14, // 17: bookcabin.flightsearch.v1.Flight.fare_rules:type_name -> bookcabin.flightsearch.v1.FareRulesSummary
+	15, // 18: bookcabin.flightsearch.v1.Flight.airport_match:type_name -> bookcabin.flightsearch.v1.AirportMatch
+	16, // 19: bookcabin.flightsearch.v1.Flight.alternative_offers:type_name -> bookcabin.flightsearch.v1.AlternativeOffer
+	9,  // 20: bookcabin.flightsearch.v1.FlightSegment.departure:type_name -> bookcabin.flightsearch.v1.Location
+	9,  // 21: bookcabin.flightsearch.v1.FlightSegment.arrival:type_name -> bookcabin.flightsearch.v1.Location
+	10, // 22: bookcabin.flightsearch.v1.FlightSegment.duration:type_name -> bookcabin.flightsearch.v1.Duration
+	12, // 23: bookcabin.flightsearch.v1.FareRulesSummary.change_fee:type_name -> bookcabin.flightsearch.v1.Price
+	12, // 24: bookcabin.flightsearch.v1.FareRulesSummary.cancellation_fee:type_name -> bookcabin.flightsearch.v1.Price
+	12, // 25: bookcabin.flightsearch.v1.AlternativeOffer.price:type_name -> bookcabin.flightsearch.v1.Price
+	0,  // 26: bookcabin.flightsearch.v1.FlightSearch.Search:input_type -> bookcabin.flightsearch.v1.SearchRequest
+	0,  // 27: bookcabin.flightsearch.v1.FlightSearch.StreamSearch:input_type -> bookcabin.flightsearch.v1.SearchRequest
+	2,  // 28: bookcabin.flightsearch.v1.FlightSearch.Search:output_type -> bookcabin.flightsearch.v1.SearchResponse
+	5,  // 29: bookcabin.flightsearch.v1.FlightSearch.StreamSearch:output_type -> bookcabin.flightsearch.v1.StreamSearchResponse
+	28, // [28:30] is the sub-list for method output_type
+	26, // [26:28] is the sub-list for method input_type
+	26, // [26:26] is the sub-list for extension type_name
+	26, // [26:26] is the sub-list for extension extendee
+	0,  // [0:26] is the sub-list for field type_name
+}
+
+func init() { file_flightsearch_v1_flight_search_proto_init() }
+func file_flightsearch_v1_flight_search_proto_init() {
+	if File_flightsearch_v1_flight_search_proto != nil {
+		return
+	}
+	file_flightsearch_v1_flight_search_proto_msgTypes[5].OneofWrappers = []any{
+		(*StreamSearchResponse_ProviderResults)(nil),
+		(*StreamSearchResponse_Summary)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_flightsearch_v1_flight_search_proto_rawDesc), len(file_flightsearch_v1_flight_search_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   17,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_flightsearch_v1_flight_search_proto_goTypes,
+		DependencyIndexes: file_flightsearch_v1_flight_search_proto_depIdxs,
+		MessageInfos:      file_flightsearch_v1_flight_search_proto_msgTypes,
+	}.Build()
+	File_flightsearch_v1_flight_search_proto = out.File
+	file_flightsearch_v1_flight_search_proto_goTypes = nil
+	file_flightsearch_v1_flight_search_proto_depIdxs = nil
+}
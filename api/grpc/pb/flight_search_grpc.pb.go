@@ -0,0 +1,171 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: flightsearch/v1/flight_search.proto
+
+package pb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	FlightSearch_Search_FullMethodName       = "/bookcabin.flightsearch.v1.FlightSearch/Search"
+	FlightSearch_StreamSearch_FullMethodName = "/bookcabin.flightsearch.v1.FlightSearch/StreamSearch"
+)
+
+// FlightSearchClient is the client API for FlightSearch service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// FlightSearch searches every provider through the same aggregator as the HTTP API.
+type FlightSearchClient interface {
+	// Search returns the ranked results once every provider answered or timed out.
+	Search(ctx context.Context, in *SearchRequest, opts ...grpc.CallOption) (*SearchResponse, error)
+	// StreamSearch sends each provider's flights as they arrive, followed by the ranked summary.
+	StreamSearch(ctx context.Context, in *SearchRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[StreamSearchResponse], error)
+}
+
+type flightSearchClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewFlightSearchClient(cc grpc.ClientConnInterface) FlightSearchClient {
+	return &flightSearchClient{cc}
+}
+
+func (c *flightSearchClient) Search(ctx context.Context, in *SearchRequest, opts ...grpc.CallOption) (*SearchResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SearchResponse)
+	err := c.cc.Invoke(ctx, FlightSearch_Search_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *flightSearchClient) StreamSearch(ctx context.Context, in *SearchRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[StreamSearchResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &FlightSearch_ServiceDesc.Streams[0], FlightSearch_StreamSearch_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[SearchRequest, StreamSearchResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type FlightSearch_StreamSearchClient = grpc.ServerStreamingClient[StreamSearchResponse]
+
+// FlightSearchServer is the server API for FlightSearch service.
+// All implementations must embed UnimplementedFlightSearchServer
+// for forward compatibility.
+//
+// FlightSearch searches every provider through the same aggregator as the HTTP API.
+type FlightSearchServer interface {
+	// Search returns the ranked results once every provider answered or timed out.
+	Search(context.Context, *SearchRequest) (*SearchResponse, error)
+	// StreamSearch sends each provider's flights as they arrive, followed by the ranked summary.
+	StreamSearch(*SearchRequest, grpc.ServerStreamingServer[StreamSearchResponse]) error
+	mustEmbedUnimplementedFlightSearchServer()
+}
+
+// UnimplementedFlightSearchServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedFlightSearchServer struct{}
+
+func (UnimplementedFlightSearchServer) Search(context.Context, *SearchRequest) (*SearchResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Search not implemented")
+}
+func (UnimplementedFlightSearchServer) StreamSearch(*SearchRequest, grpc.ServerStreamingServer[StreamSearchResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method StreamSearch not implemented")
+}
+func (UnimplementedFlightSearchServer) mustEmbedUnimplementedFlightSearchServer() {}
+func (UnimplementedFlightSearchServer) testEmbeddedByValue()                      {}
+
+// UnsafeFlightSearchServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to FlightSearchServer will
+// result in compilation errors.
+type UnsafeFlightSearchServer interface {
+	mustEmbedUnimplementedFlightSearchServer()
+}
+
+func RegisterFlightSearchServer(s grpc.ServiceRegistrar, srv FlightSearchServer) {
+	// If the following call pancis, it indicates UnimplementedFlightSearchServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&FlightSearch_ServiceDesc, srv)
+}
+
+func _FlightSearch_Search_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FlightSearchServer).Search(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FlightSearch_Search_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FlightSearchServer).Search(ctx, req.(*SearchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FlightSearch_StreamSearch_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SearchRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(FlightSearchServer).StreamSearch(m, &grpc.GenericServerStream[SearchRequest, StreamSearchResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type FlightSearch_StreamSearchServer = grpc.ServerStreamingServer[StreamSearchResponse]
+
+// FlightSearch_ServiceDesc is the grpc.ServiceDesc for FlightSearch service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var FlightSearch_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "bookcabin.flightsearch.v1.FlightSearch",
+	HandlerType: (*FlightSearchServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Search",
+			Handler:    _FlightSearch_Search_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamSearch",
+			Handler:       _FlightSearch_StreamSearch_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "flightsearch/v1/flight_search.proto",
+}
@@ -0,0 +1,111 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/elkoshar/bookcabin/api"
+	"github.com/elkoshar/bookcabin/api/grpc/pb"
+	"github.com/elkoshar/bookcabin/pkg/i18n"
+	"github.com/elkoshar/bookcabin/service"
+)
+
+// FlightSearch serves the FlightSearch gRPC service from the flight aggregator
+type FlightSearch struct {
+	pb.UnimplementedFlightSearchServer
+	aggregator api.FlightAggregator
+}
+
+func NewFlightSearch(aggregator api.FlightAggregator) *FlightSearch {
+	return &FlightSearch{aggregator: aggregator}
+}
+
+func (s *FlightSearch) Search(ctx context.Context, req *pb.SearchRequest) (*pb.SearchResponse, error) {
+	criteria, err := toCriteria(req)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.aggregator.SearchAll(ctx, criteria)
+	if err != nil {
+		return nil, searchError(ctx, err)
+	}
+
+	return toSearchResponse(resp.Localize(language(ctx))), nil
+}
+
+// StreamSearch sends the flights of each provider as soon as it answers, then the ranked summary
+func (s *FlightSearch) StreamSearch(req *pb.SearchRequest, stream pb.FlightSearch_StreamSearchServer) error {
+	ctx := stream.Context()
+
+	criteria, err := toCriteria(req)
+	if err != nil {
+		return err
+	}
+
+	events, err := s.aggregator.SearchStream(ctx, criteria)
+	if err != nil {
+		return searchError(ctx, err)
+	}
+
+	lang := language(ctx)
+	for event := range events {
+		event = event.Localize(lang)
+
+		var msg *pb.StreamSearchResponse
+		switch event.Type {
+		case service.SearchEventProviderResults, service.SearchEventProviderError:
+			msg = &pb.StreamSearchResponse{Event: &pb.StreamSearchResponse_ProviderResults{ProviderResults: toProviderResults(event)}}
+		case service.SearchEventSummary:
+			msg = &pb.StreamSearchResponse{Event: &pb.StreamSearchResponse_Summary{Summary: toSearchResponse(*event.Summary)}}
+		case service.SearchEventError:
+			err := event.Err
+			if err == nil {
+				err = errors.New(event.Error)
+			}
+			return searchError(ctx, err)
+		default:
+			continue
+		}
+
+		if err := stream.Send(msg); err != nil {
+			return err
+		}
+	}
+
+	if err := ctx.Err(); err != nil {
+		return searchError(ctx, err)
+	}
+	return nil
+}
+
+// language negotiates the response language from the accept-language metadata of the call
+func language(ctx context.Context) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	return i18n.Negotiate(strings.Join(md.Get("accept-language"), ","))
+}
+
+// searchError maps aggregator errors to gRPC status codes
+func searchError(ctx context.Context, err error) error {
+	slog.WarnContext(ctx, fmt.Sprintf("[gRPC] Search failed: %v", err))
+
+	switch {
+	case errors.Is(err, service.ErrInvalidItinerary), errors.Is(err, service.ErrUnsupportedCurrency):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, service.ErrProvidersUnavailable), errors.Is(err, service.ErrRatesUnavailable):
+		return status.Error(codes.Unavailable, err.Error())
+	case errors.Is(err, context.DeadlineExceeded), errors.Is(err, service.ErrProvidersTimeout):
+		return status.Error(codes.DeadlineExceeded, err.Error())
+	case errors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, err.Error())
+	default:
+		return status.Error(codes.Internal, err.Error())
+	}
+}
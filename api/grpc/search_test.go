@@ -0,0 +1,318 @@
+package grpc_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/elkoshar/bookcabin/api"
+	grpcapi "github.com/elkoshar/bookcabin/api/grpc"
+	"github.com/elkoshar/bookcabin/api/grpc/pb"
+	"github.com/elkoshar/bookcabin/service"
+)
+
+// MockFlightAggregator implements api.FlightAggregator for testing
+type MockFlightAggregator struct {
+	mock.Mock
+}
+
+var _ api.FlightAggregator = (*MockFlightAggregator)(nil)
+
+func (m *MockFlightAggregator) SearchAll(ctx context.Context, criteria service.SearchCriteria) (service.SearchResponse, error) {
+	args := m.Called(ctx, criteria)
+	return args.Get(0).(service.SearchResponse), args.Error(1)
+}
+
+func (m *MockFlightAggregator) SearchStream(ctx context.Context, criteria service.SearchCriteria) (<-chan service.SearchEvent, error) {
+	args := m.Called(ctx, criteria)
+	events, _ := args.Get(0).(<-chan service.SearchEvent)
+	return events, args.Error(1)
+}
+
+func (m *MockFlightAggregator) GetFlight(ctx context.Context, id string) (service.UnifiedFlight, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(service.UnifiedFlight), args.Error(1)
+}
+
+func (m *MockFlightAggregator) Reprice(ctx context.Context, req service.RepriceRequest) (service.RepriceResult, error) {
+	args := m.Called(ctx, req)
+	return args.Get(0).(service.RepriceResult), args.Error(1)
+}
+
+func (m *MockFlightAggregator) SeatMap(ctx context.Context, offerID string) (service.SeatMap, error) {
+	args := m.Called(ctx, offerID)
+	return args.Get(0).(service.SeatMap), args.Error(1)
+}
+
+func (m *MockFlightAggregator) Ancillaries(ctx context.Context, offerID string) (service.AncillaryCatalogue, error) {
+	args := m.Called(ctx, offerID)
+	return args.Get(0).(service.AncillaryCatalogue), args.Error(1)
+}
+
+func (m *MockFlightAggregator) FareRules(ctx context.Context, offerID string) (service.FareRules, error) {
+	args := m.Called(ctx, offerID)
+	return args.Get(0).(service.FareRules), args.Error(1)
+}
+
+func newClient(t *testing.T, aggregator api.FlightAggregator) pb.FlightSearchClient {
+	lis := bufconn.Listen(1024 * 1024)
+	srv := grpc.NewServer()
+	pb.RegisterFlightSearchServer(srv, grpcapi.NewFlightSearch(aggregator))
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	return pb.NewFlightSearchClient(conn)
+}
+
+func searchResponse() service.SearchResponse {
+	return service.SearchResponse{
+		SearchID: "search-1",
+		Criteria: service.SearchCriteria{Origin: "CGK", Destination: "DPS", DepartureDate: "2030-12-15", Passengers: 1, CabinClass: "economy"},
+		Metadata: service.Metadata{TotalResults: 3, ProvidersQueried: 2, ProvidersSucceeded: 2},
+		Flights: []service.UnifiedFlight{
+			{
+				ID:           "GA400",
+				Provider:     "Garuda Indonesia",
+				Airline:      service.AirlineInfo{Name: "Garuda Indonesia", Code: "GA"},
+				FlightNumber: "GA400",
+				Departure:    service.LocationInfo{Airport: "CGK", City: "Jakarta", Terminal: "3"},
+				Arrival:      service.LocationInfo{Airport: "DPS", City: "Denpasar"},
+				Price:        service.PriceInfo{Amount: 1560000, Currency: "IDR"},
+				Baggage:      &service.BaggageInfo{CheckedKg: 20},
+			},
+			{ID: "JT650", Provider: "Lion Air", FlightNumber: "JT650", Price: service.PriceInfo{Amount: 950000, Currency: "IDR"}},
+			{ID: "GA410", Provider: "Garuda Indonesia", FlightNumber: "GA410", Price: service.PriceInfo{Amount: 1700000, Currency: "IDR"}},
+		},
+	}
+}
+
+func TestSearch_Success(t *testing.T) {
+	mockService := &MockFlightAggregator{}
+	mockService.On("SearchAll", mock.Anything, service.SearchCriteria{
+		Origin: "CGK", Destination: "DPS", DepartureDate: "2030-12-15", Passengers: 1, CabinClass: "economy",
+	}).Return(searchResponse(), nil)
+
+	client := newClient(t, mockService)
+	resp, err := client.Search(context.Background(), &pb.SearchRequest{
+		Origin: "CGK", Destination: "DPS", DepartureDate: "2030-12-15", Passengers: 1, CabinClass: "economy",
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, "search-1", resp.GetSearchId())
+	assert.Equal(t, int32(3), resp.GetMetadata().GetTotalResults())
+	require.Len(t, resp.GetFlights(), 3)
+	assert.Equal(t, "GA", resp.GetFlights()[0].GetAirline().GetCode())
+	assert.Equal(t, "3", resp.GetFlights()[0].GetDeparture().GetTerminal())
+	assert.Equal(t, int32(20), resp.GetFlights()[0].GetBaggage().GetCheckedKg())
+	assert.Nil(t, resp.GetFlights()[1].GetBaggage())
+	mockService.AssertExpectations(t)
+}
+
+func TestSearch_LowercaseCodes(t *testing.T) {
+	mockService := &MockFlightAggregator{}
+	mockService.On("SearchAll", mock.Anything, service.SearchCriteria{
+		Origin: "CGK", Destination: "DPS", DepartureDate: "2030-12-15", Passengers: 1, CabinClass: "economy", Currency: "USD",
+	}).Return(searchResponse(), nil)
+
+	client := newClient(t, mockService)
+	_, err := client.Search(context.Background(), &pb.SearchRequest{
+		Origin: "cgk", Destination: "dps", DepartureDate: "2030-12-15", Passengers: 1, CabinClass: "economy", Currency: "usd",
+	})
+
+	require.NoError(t, err)
+	mockService.AssertExpectations(t)
+}
+
+func TestSearch_InvalidArgument(t *testing.T) {
+	tests := []struct {
+		name string
+		req  *pb.SearchRequest
+	}{
+		{name: "no passengers", req: &pb.SearchRequest{Origin: "CGK", Destination: "DPS", DepartureDate: "2030-12-15"}},
+		{name: "no route", req: &pb.SearchRequest{Passengers: 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockFlightAggregator{}
+			client := newClient(t, mockService)
+
+			_, err := client.Search(context.Background(), tt.req)
+
+			assert.Equal(t, codes.InvalidArgument, status.Code(err))
+			require.Len(t, status.Convert(err).Details(), 1)
+			violations := status.Convert(err).Details()[0].(*errdetails.BadRequest).GetFieldViolations()
+			assert.NotEmpty(t, violations)
+			mockService.AssertNotCalled(t, "SearchAll", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestSearch_ServiceError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		code codes.Code
+	}{
+		{name: "timeout", err: context.DeadlineExceeded, code: codes.DeadlineExceeded},
+		{name: "providers timed out", err: service.ErrProvidersTimeout, code: codes.DeadlineExceeded},
+		{name: "providers unavailable", err: service.ErrProvidersUnavailable, code: codes.Unavailable},
+		{name: "invalid itinerary", err: fmt.Errorf("%w: round_trip requires a return date", service.ErrInvalidItinerary), code: codes.InvalidArgument},
+		{name: "rates unavailable", err: service.ErrRatesUnavailable, code: codes.Unavailable},
+		{name: "unsupported currency", err: fmt.Errorf("%w: EUR", service.ErrUnsupportedCurrency), code: codes.InvalidArgument},
+		{name: "internal", err: errors.New("storage failed"), code: codes.Internal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockFlightAggregator{}
+			mockService.On("SearchAll", mock.Anything, mock.Anything).Return(service.SearchResponse{}, tt.err)
+			client := newClient(t, mockService)
+
+			_, err := client.Search(context.Background(), &pb.SearchRequest{
+				Origin: "CGK", Destination: "DPS", DepartureDate: "2030-12-15", Passengers: 1, CabinClass: "economy",
+			})
+
+			assert.Equal(t, tt.code, status.Code(err))
+		})
+	}
+}
+
+func searchEvents(events ...service.SearchEvent) <-chan service.SearchEvent {
+	ch := make(chan service.SearchEvent, len(events))
+	for _, e := range events {
+		ch <- e
+	}
+	close(ch)
+	return ch
+}
+
+func TestStreamSearch_Success(t *testing.T) {
+	resp := searchResponse()
+	mockService := &MockFlightAggregator{}
+	mockService.On("SearchStream", mock.Anything, mock.Anything).Return(searchEvents(
+		service.SearchEvent{Type: service.SearchEventProviderResults, Provider: "Lion Air", Flights: resp.Flights[1:2]},
+		service.SearchEvent{Type: service.SearchEventProviderError, Provider: "Batik Air", Error: "timeout"},
+		service.SearchEvent{Type: service.SearchEventProviderResults, Provider: "Garuda Indonesia", Flights: []service.UnifiedFlight{resp.Flights[0], resp.Flights[2]}},
+		service.SearchEvent{Type: service.SearchEventSummary, Summary: &resp},
+	), nil)
+	client := newClient(t, mockService)
+
+	stream, err := client.StreamSearch(context.Background(), &pb.SearchRequest{
+		Origin: "CGK", Destination: "DPS", DepartureDate: "2030-12-15", Passengers: 1, CabinClass: "economy",
+	})
+	require.NoError(t, err)
+
+	var events []*pb.StreamSearchResponse
+	for {
+		event, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		events = append(events, event)
+	}
+
+	require.Len(t, events, 4)
+	assert.Equal(t, "Lion Air", events[0].GetProviderResults().GetProvider())
+	assert.Len(t, events[0].GetProviderResults().GetFlights(), 1)
+	assert.Equal(t, "timeout", events[1].GetProviderResults().GetError())
+	assert.Len(t, events[2].GetProviderResults().GetFlights(), 2)
+	assert.Equal(t, "search-1", events[3].GetSummary().GetSearchId())
+	assert.Len(t, events[3].GetSummary().GetFlights(), 3)
+}
+
+func TestStreamSearch_SearchError(t *testing.T) {
+	tests := []struct {
+		name  string
+		event service.SearchEvent
+		code  codes.Code
+	}{
+		{name: "untyped", event: service.SearchEvent{Type: service.SearchEventError, Error: "storage unavailable"}, code: codes.Internal},
+		{name: "providers timed out", event: service.SearchEvent{Type: service.SearchEventError, Error: service.ErrProvidersTimeout.Error(), Err: service.ErrProvidersTimeout}, code: codes.DeadlineExceeded},
+		{name: "rates unavailable", event: service.SearchEvent{Type: service.SearchEventError, Error: service.ErrRatesUnavailable.Error(), Err: service.ErrRatesUnavailable}, code: codes.Unavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockFlightAggregator{}
+			mockService.On("SearchStream", mock.Anything, mock.Anything).Return(searchEvents(tt.event), nil)
+			client := newClient(t, mockService)
+
+			stream, err := client.StreamSearch(context.Background(), &pb.SearchRequest{
+				Origin: "CGK", Destination: "DPS", DepartureDate: "2030-12-15", Passengers: 1, CabinClass: "economy",
+			})
+			require.NoError(t, err)
+
+			_, err = stream.Recv()
+			assert.Equal(t, tt.code, status.Code(err))
+		})
+	}
+}
+
+func TestStreamSearch_SetupError(t *testing.T) {
+	mockService := &MockFlightAggregator{}
+	mockService.On("SearchStream", mock.Anything, mock.Anything).Return(nil, service.ErrRatesUnavailable)
+	client := newClient(t, mockService)
+
+	stream, err := client.StreamSearch(context.Background(), &pb.SearchRequest{
+		Origin: "CGK", Destination: "DPS", DepartureDate: "2030-12-15", Passengers: 1, CabinClass: "economy",
+	})
+	require.NoError(t, err)
+
+	_, err = stream.Recv()
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+}
+
+func TestStreamSearch_InvalidArgument(t *testing.T) {
+	tests := []struct {
+		name  string
+		req   *pb.SearchRequest
+		setup func(m *MockFlightAggregator)
+	}{
+		{name: "missing fields", req: &pb.SearchRequest{}},
+		{
+			name: "invalid itinerary",
+			req:  &pb.SearchRequest{Origin: "CGK", Destination: "DPS", DepartureDate: "2030-12-15", Passengers: 1, CabinClass: "economy", TripType: "round_trip"},
+			setup: func(m *MockFlightAggregator) {
+				m.On("SearchStream", mock.Anything, mock.Anything).Return(nil, fmt.Errorf("%w: round_trip requires a return date", service.ErrInvalidItinerary))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockFlightAggregator{}
+			if tt.setup != nil {
+				tt.setup(mockService)
+			}
+			client := newClient(t, mockService)
+
+			stream, err := client.StreamSearch(context.Background(), tt.req)
+			require.NoError(t, err)
+
+			_, err = stream.Recv()
+			assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		})
+	}
+}
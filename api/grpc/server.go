@@ -0,0 +1,66 @@
+package grpc
+
+import (
+	"context"
+	"net"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+
+	"github.com/elkoshar/bookcabin/api"
+	"github.com/elkoshar/bookcabin/api/grpc/pb"
+	config "github.com/elkoshar/bookcabin/configs"
+)
+
+type Server struct {
+	server     *grpc.Server
+	Cfg        *config.Config
+	Aggregator api.FlightAggregator
+}
+
+// NewServer builds the gRPC server and registers its services, so it can be shut down even when
+// Serve never ran or failed to listen
+func NewServer(cfg *config.Config, aggregator api.FlightAggregator) *Server {
+	s := &Server{
+		server:     grpc.NewServer(),
+		Cfg:        cfg,
+		Aggregator: aggregator,
+	}
+	pb.RegisterFlightSearchServer(s.server, NewFlightSearch(aggregator))
+
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus(pb.FlightSearch_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_SERVING)
+	healthpb.RegisterHealthServer(s.server, healthServer)
+	reflection.Register(s.server)
+
+	return s
+}
+
+func (s *Server) Serve(port string) error {
+
+	lis, err := net.Listen("tcp", ":"+port)
+	if err != nil {
+		return err
+	}
+
+	return s.server.Serve(lis)
+}
+
+// Shutdown stops accepting calls and waits for running calls to finish until ctx is done
+func (s *Server) Shutdown(ctx context.Context) error {
+	stopped := make(chan struct{})
+	go func() {
+		s.server.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+		return nil
+	case <-ctx.Done():
+		s.server.Stop()
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,31 @@
+package grpc_test
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	grpcapi "github.com/elkoshar/bookcabin/api/grpc"
+	config "github.com/elkoshar/bookcabin/configs"
+)
+
+func TestServer_ShutdownBeforeServe(t *testing.T) {
+	srv := grpcapi.NewServer(&config.Config{}, &MockFlightAggregator{})
+	assert.NoError(t, srv.Shutdown(context.Background()))
+}
+
+func TestServer_ServePortInUse(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer lis.Close()
+
+	_, port, err := net.SplitHostPort(lis.Addr().String())
+	require.NoError(t, err)
+
+	srv := grpcapi.NewServer(&config.Config{}, &MockFlightAggregator{})
+	assert.Error(t, srv.Serve(port))
+	assert.NoError(t, srv.Shutdown(context.Background()))
+}
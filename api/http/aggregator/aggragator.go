@@ -4,25 +4,33 @@ import (
 	"fmt"
 	"log/slog"
 	"net/http"
+	"time"
 
 	"github.com/elkoshar/bookcabin/api"
 	"github.com/elkoshar/bookcabin/pkg/helpers"
+	"github.com/elkoshar/bookcabin/pkg/i18n"
 	"github.com/elkoshar/bookcabin/pkg/response"
 	"github.com/elkoshar/bookcabin/service"
+	"github.com/go-chi/chi/v5"
 )
 
 const (
 	ErrParseUrlParamMsg = "Parse Url Param Failed. %v"
 	ErrCreateDataMsg    = "Create Data Failed. %+v"
+	ErrGetDataMsg       = "Get Data Failed. %+v"
 	ErrParseValidateMsg = "Failed to Parse and Validate. err=%v"
 )
 
 var (
 	flightAggregator api.FlightAggregator
+
+	// searchMaxAge is how long shared caches may serve a GET search response
+	searchMaxAge time.Duration
 )
 
-func Init(service api.FlightAggregator) {
+func Init(service api.FlightAggregator, cacheMaxAge time.Duration) {
 	flightAggregator = service
+	searchMaxAge = cacheMaxAge
 }
 
 // SearchFlight : HTTP Handler for searching flights
@@ -31,7 +39,11 @@ func Init(service api.FlightAggregator) {
 // @Tags Flight
 // @Accept json
 // @Produce json
+// @Produce text/csv
+// @Produce application/x-ndjson
+// @Produce text/calendar
 // @Param Accept-Language header string true "accept language" default(id)
+// @Param format query string false "json, csv, ndjson or ics, overrides the Accept header"
 // @Param body body service.SearchCriteria true "Request Body"
 // @Success 200 {object} response.Response{data=service.SearchResponse} "Success Response"
 // @Router /flight/search [POST]
@@ -56,11 +68,207 @@ func Search(w http.ResponseWriter, r *http.Request) {
 	result, err = flightAggregator.SearchAll(r.Context(), req)
 	if err != nil {
 		slog.WarnContext(r.Context(), fmt.Sprintf(ErrCreateDataMsg, err))
-		resp.SetError(err, http.StatusInternalServerError)
+		resp.SetError(err)
+		return
+	}
+
+	resp.Data = result.Localize(i18n.FromContext(r.Context()))
+	resp.Exportable = true
+	resp.Filename = "flights-" + result.SearchID
+	resp.Code = http.StatusOK
+
+}
+
+// SearchQuery : HTTP Handler for searching flights with query parameters
+// @Summary Search Flight (cacheable)
+// @Description SearchQuery is the GET equivalent of the search endpoint. Responses carry Cache-Control, ETag and Last-Modified, and a request whose If-None-Match matches the current results gets 304 Not Modified
+// @Tags Flight
+// @Produce json
+// @Produce text/csv
+// @Produce application/x-ndjson
+// @Produce text/calendar
+// @Param Accept-Language header string true "accept language" default(id)
+// @Param format query string false "json, csv, ndjson or ics, overrides the Accept header"
+// @Param If-None-Match header string false "ETag of a previous response"
+// @Param origin query string false "Origin airport or metro code"
+// @Param destination query string false "Destination airport or metro code"
+// @Param departure_date query string false "Departure date (YYYY-MM-DD)"
+// @Param return_date query string false "Return date (YYYY-MM-DD)"
+// @Param passengers query int false "Number of passengers"
+// @Param cabin_class query string false "Cabin class"
+// @Param trip_type query string false "Trip type"
+// @Param segment query []string false "Multi-city leg as ORIGIN,DESTINATION,DATE" collectionFormat(multi)
+// @Param exclude_dominated query bool false "Drop flights that are both pricier and slower than another"
+// @Param refundable_only query bool false "Keep only refundable fares"
+// @Param nearby_radius_km query number false "Also search airports within this distance"
+// @Success 200 {object} response.Response{data=service.SearchResponse} "Success Response"
+// @Success 304 "Not Modified"
+// @Router /flight/search [GET]
+func SearchQuery(w http.ResponseWriter, r *http.Request) {
+
+	resp := response.Response{}
+
+	criteria, err := parseSearchQuery(r.URL.Query())
+	if err != nil {
+		slog.WarnContext(r.Context(), fmt.Sprintf(ErrParseValidateMsg, err))
+		resp.SetError(err, http.StatusBadRequest)
+		resp.Render(w, r)
+		return
+	}
+
+	result, err := flightAggregator.SearchAll(r.Context(), criteria)
+	if err != nil {
+		slog.WarnContext(r.Context(), fmt.Sprintf(ErrCreateDataMsg, err))
+		resp.SetError(err)
+		resp.Render(w, r)
+		return
+	}
+	result = result.Localize(i18n.FromContext(r.Context()))
+
+	renderCached(w, r, result, result, true)
+}
+
+// GetFlight : HTTP Handler for getting the current details of a flight offer
+// @Summary Get Flight
+// @Description GetFlight re-queries the provider of a previously returned offer so its price and seats can be revalidated
+// @Tags Flight
+// @Produce json
+// @Produce text/csv
+// @Produce application/x-ndjson
+// @Produce text/calendar
+// @Param Accept-Language header string true "accept language" default(id)
+// @Param format query string false "json, csv, ndjson or ics, overrides the Accept header"
+// @Param id path string true "Offer ID"
+// @Success 200 {object} response.Response{data=service.UnifiedFlight} "Success Response"
+// @Router /flight/{id} [GET]
+func GetFlight(w http.ResponseWriter, r *http.Request) {
+
+	resp := response.Response{}
+	defer resp.Render(w, r)
+
+	result, err := flightAggregator.GetFlight(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		slog.WarnContext(r.Context(), fmt.Sprintf(ErrGetDataMsg, err))
+		resp.SetError(err)
+		return
+	}
+
+	resp.Data = result.Localize(i18n.FromContext(r.Context()))
+	resp.Exportable = true
+	resp.Filename = "flight-" + result.FlightNumber
+	resp.Code = http.StatusOK
+}
+
+// SeatMap : HTTP Handler for getting the seat map of a flight offer
+// @Summary Get Seat Map
+// @Description SeatMap returns the cabin layout of an offer with seat characteristics, prices and availability
+// @Tags Flight
+// @Produce json
+// @Param Accept-Language header string true "accept language" default(id)
+// @Param id path string true "Offer ID"
+// @Success 200 {object} response.Response{data=service.SeatMap} "Success Response"
+// @Router /flight/{id}/seatmap [GET]
+func SeatMap(w http.ResponseWriter, r *http.Request) {
+
+	resp := response.Response{}
+	defer resp.Render(w, r)
+
+	result, err := flightAggregator.SeatMap(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		slog.WarnContext(r.Context(), fmt.Sprintf(ErrGetDataMsg, err))
+		resp.SetError(err)
 		return
 	}
 
-	resp.Data = result
+	resp.Data = result.Localize(i18n.FromContext(r.Context()))
 	resp.Code = http.StatusOK
+}
 
+// Ancillaries : HTTP Handler for getting the ancillary catalogue of a flight offer
+// @Summary Get Ancillaries
+// @Description Ancillaries lists the extra baggage, meals, priority boarding and insurance that can be added to an offer
+// @Tags Flight
+// @Produce json
+// @Param Accept-Language header string true "accept language" default(id)
+// @Param id path string true "Offer ID"
+// @Success 200 {object} response.Response{data=service.AncillaryCatalogue} "Success Response"
+// @Router /flight/{id}/ancillaries [GET]
+func Ancillaries(w http.ResponseWriter, r *http.Request) {
+
+	resp := response.Response{}
+	defer resp.Render(w, r)
+
+	result, err := flightAggregator.Ancillaries(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		slog.WarnContext(r.Context(), fmt.Sprintf(ErrGetDataMsg, err))
+		resp.SetError(err)
+		return
+	}
+
+	resp.Data = result.Localize(i18n.FromContext(r.Context()))
+	resp.Code = http.StatusOK
+}
+
+// FareRules : HTTP Handler for getting the fare rules of a flight offer
+// @Summary Get Fare Rules
+// @Description FareRules returns the refund, change, no-show and validity conditions of an offer's fare family
+// @Tags Flight
+// @Produce json
+// @Param Accept-Language header string true "accept language" default(id)
+// @Param id path string true "Offer ID"
+// @Success 200 {object} response.Response{data=service.FareRules} "Success Response"
+// @Router /flight/{id}/fare-rules [GET]
+func FareRules(w http.ResponseWriter, r *http.Request) {
+
+	resp := response.Response{}
+	defer resp.Render(w, r)
+
+	result, err := flightAggregator.FareRules(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		slog.WarnContext(r.Context(), fmt.Sprintf(ErrGetDataMsg, err))
+		resp.SetError(err)
+		return
+	}
+
+	resp.Data = result.Localize(i18n.FromContext(r.Context()))
+	resp.Code = http.StatusOK
+}
+
+// Revalidate : HTTP Handler for revalidating the price and availability of a flight offer
+// @Summary Revalidate Flight
+// @Description Revalidate re-queries only the provider of an offer and reports whether it is unchanged, changed in price or sold out
+// @Tags Flight
+// @Accept json
+// @Produce json
+// @Param Accept-Language header string true "accept language" default(id)
+// @Param body body service.RepriceRequest true "Request Body"
+// @Success 200 {object} response.Response{data=service.RepriceResult} "Success Response"
+// @Router /flight/revalidate [POST]
+func Revalidate(w http.ResponseWriter, r *http.Request) {
+
+	resp := response.Response{}
+	defer resp.Render(w, r)
+
+	var (
+		err    error
+		req    service.RepriceRequest
+		result service.RepriceResult
+	)
+
+	err = helpers.ParseBodyAndValidate(r, &req)
+	if err != nil {
+		slog.WarnContext(r.Context(), fmt.Sprintf(ErrParseValidateMsg, err))
+		resp.SetError(err, http.StatusBadRequest)
+		return
+	}
+
+	result, err = flightAggregator.Reprice(r.Context(), req)
+	if err != nil {
+		slog.WarnContext(r.Context(), fmt.Sprintf(ErrGetDataMsg, err))
+		resp.SetError(err)
+		return
+	}
+
+	resp.Data = result.Localize(i18n.FromContext(r.Context()))
+	resp.Code = http.StatusOK
 }
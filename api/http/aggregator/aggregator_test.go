@@ -5,13 +5,20 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/elkoshar/bookcabin/api"
 	"github.com/elkoshar/bookcabin/api/http/aggregator"
+	"github.com/elkoshar/bookcabin/pkg/i18n"
+	"github.com/elkoshar/bookcabin/pkg/response"
 	"github.com/elkoshar/bookcabin/service"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -28,26 +35,57 @@ func (m *MockFlightAggregator) SearchAll(ctx context.Context, criteria service.S
 	return args.Get(0).(service.SearchResponse), args.Error(1)
 }
 
+func (m *MockFlightAggregator) SearchStream(ctx context.Context, criteria service.SearchCriteria) (<-chan service.SearchEvent, error) {
+	args := m.Called(ctx, criteria)
+	events, _ := args.Get(0).(<-chan service.SearchEvent)
+	return events, args.Error(1)
+}
+
+func (m *MockFlightAggregator) GetFlight(ctx context.Context, id string) (service.UnifiedFlight, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(service.UnifiedFlight), args.Error(1)
+}
+
+func (m *MockFlightAggregator) Reprice(ctx context.Context, req service.RepriceRequest) (service.RepriceResult, error) {
+	args := m.Called(ctx, req)
+	return args.Get(0).(service.RepriceResult), args.Error(1)
+}
+
+func (m *MockFlightAggregator) SeatMap(ctx context.Context, offerID string) (service.SeatMap, error) {
+	args := m.Called(ctx, offerID)
+	return args.Get(0).(service.SeatMap), args.Error(1)
+}
+
+func (m *MockFlightAggregator) Ancillaries(ctx context.Context, offerID string) (service.AncillaryCatalogue, error) {
+	args := m.Called(ctx, offerID)
+	return args.Get(0).(service.AncillaryCatalogue), args.Error(1)
+}
+
+func (m *MockFlightAggregator) FareRules(ctx context.Context, offerID string) (service.FareRules, error) {
+	args := m.Called(ctx, offerID)
+	return args.Get(0).(service.FareRules), args.Error(1)
+}
+
 func TestInit(t *testing.T) {
 	mockService := &MockFlightAggregator{}
 
 	// Test that Init doesn't panic and accepts the service
 	assert.NotPanics(t, func() {
-		aggregator.Init(mockService)
+		aggregator.Init(mockService, time.Minute)
 	})
 }
 
 func TestSearch_Success(t *testing.T) {
 	// Setup mock service
 	mockService := &MockFlightAggregator{}
-	aggregator.Init(mockService)
+	aggregator.Init(mockService, time.Minute)
 
 	// Mock response
 	expectedResponse := service.SearchResponse{
 		Criteria: service.SearchCriteria{
 			Origin:        "CGK",
 			Destination:   "DPS",
-			DepartureDate: "2025-12-15",
+			DepartureDate: "2030-12-15",
 		},
 		Flights: []service.UnifiedFlight{
 			{
@@ -75,7 +113,7 @@ func TestSearch_Success(t *testing.T) {
 	requestBody := service.SearchCriteria{
 		Origin:        "CGK",
 		Destination:   "DPS",
-		DepartureDate: "2025-12-15",
+		DepartureDate: "2030-12-15",
 		Passengers:    1,
 		CabinClass:    "economy",
 	}
@@ -120,9 +158,28 @@ func TestSearch_Success(t *testing.T) {
 	mockService.AssertExpectations(t)
 }
 
+func TestSearch_LowercaseCodes(t *testing.T) {
+	mockService := &MockFlightAggregator{}
+	aggregator.Init(mockService, time.Minute)
+
+	mockService.On("SearchAll", mock.Anything, service.SearchCriteria{
+		Origin: "CGK", Destination: "DPS", DepartureDate: "2030-12-15", Passengers: 1, CabinClass: "economy", Currency: "USD",
+	}).Return(service.SearchResponse{SearchID: "search-1"}, nil)
+
+	body := `{"Origin":"cgk","Destination":" dps","DepartureDate":"2030-12-15","Passengers":1,"CabinClass":"economy","currency":"usd"}`
+	req := httptest.NewRequest(http.MethodPost, "/flight/search", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	aggregator.Search(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockService.AssertExpectations(t)
+}
+
 func TestSearch_InvalidJSON(t *testing.T) {
 	mockService := &MockFlightAggregator{}
-	aggregator.Init(mockService)
+	aggregator.Init(mockService, time.Minute)
 
 	// Create request with invalid JSON
 	req := httptest.NewRequest(http.MethodPost, "/flight/search", bytes.NewBuffer([]byte("invalid json")))
@@ -153,12 +210,12 @@ func TestSearch_InvalidJSON(t *testing.T) {
 
 func TestSearch_ValidationError(t *testing.T) {
 	mockService := &MockFlightAggregator{}
-	aggregator.Init(mockService)
+	aggregator.Init(mockService, time.Minute)
 
 	// Create request with invalid data (missing required fields)
 	requestBody := service.SearchCriteria{
 		// Missing Origin and Destination
-		DepartureDate: "2025-12-15",
+		DepartureDate: "2030-12-15",
 		Passengers:    1,
 		CabinClass:    "economy",
 	}
@@ -204,7 +261,7 @@ func TestSearch_ValidationError(t *testing.T) {
 func TestSearch_ServiceError(t *testing.T) {
 	// Setup mock service
 	mockService := &MockFlightAggregator{}
-	aggregator.Init(mockService)
+	aggregator.Init(mockService, time.Minute)
 
 	expectedError := errors.New("service unavailable")
 
@@ -215,7 +272,7 @@ func TestSearch_ServiceError(t *testing.T) {
 	requestBody := service.SearchCriteria{
 		Origin:        "CGK",
 		Destination:   "DPS",
-		DepartureDate: "2025-12-15",
+		DepartureDate: "2030-12-15",
 		Passengers:    1,
 		CabinClass:    "economy",
 	}
@@ -251,9 +308,118 @@ func TestSearch_ServiceError(t *testing.T) {
 	mockService.AssertExpectations(t)
 }
 
+func TestSearch_ErrorCodes(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		status    int
+		errorCode string
+	}{
+		{name: "invalid itinerary", err: fmt.Errorf("%w: round_trip requires a return date", service.ErrInvalidItinerary), status: http.StatusBadRequest, errorCode: "INVALID_ITINERARY"},
+		{name: "providers unavailable", err: service.ErrProvidersUnavailable, status: http.StatusBadGateway, errorCode: "PROVIDERS_UNAVAILABLE"},
+		{name: "providers timed out", err: service.ErrProvidersTimeout, status: http.StatusGatewayTimeout, errorCode: "PROVIDERS_TIMEOUT"},
+		{name: "uncatalogued", err: errors.New("storage failed"), status: http.StatusInternalServerError, errorCode: "INTERNAL_SERVER_ERROR"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockFlightAggregator{}
+			aggregator.Init(mockService, time.Minute)
+			mockService.On("SearchAll", mock.Anything, mock.Anything).Return(service.SearchResponse{}, tt.err)
+
+			body := `{"Origin":"CGK","Destination":"DPS","DepartureDate":"2030-12-15","Passengers":1,"CabinClass":"economy"}`
+			req := httptest.NewRequest(http.MethodPost, "/flight/search", strings.NewReader(body))
+			req = req.WithContext(context.WithValue(req.Context(), middleware.RequestIDKey, "req-1"))
+			w := httptest.NewRecorder()
+
+			aggregator.Search(w, req)
+
+			assert.Equal(t, tt.status, w.Code)
+
+			var resp struct {
+				Error struct {
+					ErrorCode string `json:"error_code"`
+					RequestID string `json:"request_id"`
+				} `json:"error"`
+			}
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+			assert.Equal(t, tt.errorCode, resp.Error.ErrorCode)
+			assert.Equal(t, "req-1", resp.Error.RequestID)
+		})
+	}
+}
+
+func TestSearch_ProblemJSON(t *testing.T) {
+	mockService := &MockFlightAggregator{}
+	aggregator.Init(mockService, time.Minute)
+
+	req := httptest.NewRequest(http.MethodPost, "/flight/search", strings.NewReader(`{"Origin":"CGK","Passengers":10,"CabinClass":"economy"}`))
+	req.Header.Set("Accept", "application/problem+json")
+	w := httptest.NewRecorder()
+
+	aggregator.Search(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, "application/problem+json", w.Header().Get("Content-Type"))
+
+	var problem response.Problem
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+	assert.Equal(t, "VALIDATION_FAILED", problem.Code)
+	assert.Equal(t, http.StatusBadRequest, problem.Status)
+	assert.Equal(t, "/flight/search", problem.Instance)
+	assert.NotEmpty(t, problem.Detail)
+
+	fields := map[string]string{}
+	for _, f := range problem.Errors {
+		fields[f.Field] = f.Rule
+	}
+	assert.Equal(t, "required_without", fields["Destination"])
+	assert.Equal(t, "max", fields["Passengers"])
+	mockService.AssertNotCalled(t, "SearchAll", mock.Anything, mock.Anything)
+}
+
+func TestSearch_Localized(t *testing.T) {
+	tests := []struct {
+		lang     string
+		duration string
+		price    string
+	}{
+		{lang: i18n.Indonesian, duration: "1j 40m", price: "Rp 650.000"},
+		{lang: i18n.English, duration: "1h 40m", price: "IDR 650,000"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.lang, func(t *testing.T) {
+			mockService := &MockFlightAggregator{}
+			aggregator.Init(mockService, time.Minute)
+			mockService.On("SearchAll", mock.Anything, mock.Anything).Return(service.SearchResponse{
+				Flights: []service.UnifiedFlight{{
+					ID:       "QZ520",
+					Duration: service.DurationInfo{TotalMinutes: 100, Formatted: "1h 40m"},
+					Price:    service.PriceInfo{Amount: 650000, Currency: "IDR"},
+				}},
+			}, nil)
+
+			body := `{"Origin":"CGK","Destination":"DPS","DepartureDate":"2030-12-15","Passengers":1,"CabinClass":"economy"}`
+			req := httptest.NewRequest(http.MethodPost, "/flight/search", strings.NewReader(body))
+			req = req.WithContext(i18n.WithLanguage(req.Context(), tt.lang))
+			w := httptest.NewRecorder()
+
+			aggregator.Search(w, req)
+
+			var resp struct {
+				Data service.SearchResponse `json:"data"`
+			}
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+			assert.Equal(t, tt.duration, resp.Data.Flights[0].Duration.Formatted)
+			assert.Equal(t, tt.price, resp.Data.Flights[0].Price.Formatted)
+		})
+	}
+}
+
 func TestSearch_EmptyBody(t *testing.T) {
 	mockService := &MockFlightAggregator{}
-	aggregator.Init(mockService)
+	aggregator.Init(mockService, time.Minute)
 
 	// Create request with empty body
 	req := httptest.NewRequest(http.MethodPost, "/flight/search", bytes.NewBuffer([]byte("")))
@@ -282,15 +448,15 @@ func TestSearch_EmptyBody(t *testing.T) {
 func TestSearch_RoundTripSearch(t *testing.T) {
 	// Setup mock service
 	mockService := &MockFlightAggregator{}
-	aggregator.Init(mockService)
+	aggregator.Init(mockService, time.Minute)
 
 	// Mock response for round trip
 	expectedResponse := service.SearchResponse{
 		Criteria: service.SearchCriteria{
 			Origin:        "CGK",
 			Destination:   "DPS",
-			DepartureDate: "2025-12-15",
-			ReturnDate:    "2025-12-20",
+			DepartureDate: "2030-12-15",
+			ReturnDate:    "2030-12-20",
 		},
 		Flights: []service.UnifiedFlight{
 			{
@@ -324,8 +490,8 @@ func TestSearch_RoundTripSearch(t *testing.T) {
 	requestBody := service.SearchCriteria{
 		Origin:        "CGK",
 		Destination:   "DPS",
-		DepartureDate: "2025-12-15",
-		ReturnDate:    "2025-12-20",
+		DepartureDate: "2030-12-15",
+		ReturnDate:    "2030-12-20",
 		Passengers:    1,
 		CabinClass:    "economy",
 	}
@@ -364,3 +530,589 @@ func TestSearch_RoundTripSearch(t *testing.T) {
 
 	mockService.AssertExpectations(t)
 }
+
+func TestGetFlight(t *testing.T) {
+	tests := []struct {
+		name       string
+		id         string
+		flight     service.UnifiedFlight
+		err        error
+		wantStatus int
+	}{
+		{name: "success", id: "offer-1", flight: service.UnifiedFlight{ID: "offer-1", FlightNumber: "GA400"}, wantStatus: http.StatusOK},
+		{name: "invalid id", id: "bad", err: service.ErrInvalidOfferID, wantStatus: http.StatusBadRequest},
+		{name: "not found", id: "gone", err: service.ErrOfferNotFound, wantStatus: http.StatusNotFound},
+		{name: "rates unavailable", id: "offer-3", err: service.ErrRatesUnavailable, wantStatus: http.StatusServiceUnavailable},
+		{name: "provider error", id: "offer-2", err: errors.New("provider down"), wantStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockFlightAggregator{}
+			aggregator.Init(mockService, time.Minute)
+			mockService.On("GetFlight", mock.Anything, tt.id).Return(tt.flight, tt.err)
+
+			r := chi.NewRouter()
+			r.Get("/flight/{id}", aggregator.GetFlight)
+
+			req := httptest.NewRequest(http.MethodGet, "/flight/"+tt.id, nil)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+
+			var response map[string]interface{}
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			if tt.err == nil {
+				data := response["data"].(map[string]interface{})
+				assert.Equal(t, "GA400", data["flight_number"])
+			}
+
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestRevalidate(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		result     service.RepriceResult
+		err        error
+		wantStatus int
+	}{
+		{
+			name:       "price changed",
+			body:       `{"id":"offer-1","passengers":2}`,
+			result:     service.RepriceResult{ID: "offer-1", Status: service.RepriceStatusPriceChanged, OldAmount: 1000000, NewAmount: 1100000},
+			wantStatus: http.StatusOK,
+		},
+		{name: "missing id", body: `{"passengers":2}`, wantStatus: http.StatusBadRequest},
+		{name: "invalid id", body: `{"id":"bad"}`, err: service.ErrInvalidOfferID, wantStatus: http.StatusBadRequest},
+		{name: "sold out", body: `{"id":"offer-1"}`, err: service.ErrOfferSoldOut, wantStatus: http.StatusConflict},
+		{name: "provider error", body: `{"id":"offer-1"}`, err: errors.New("provider down"), wantStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockFlightAggregator{}
+			aggregator.Init(mockService, time.Minute)
+			mockService.On("Reprice", mock.Anything, mock.Anything).Return(tt.result, tt.err).Maybe()
+
+			req := httptest.NewRequest(http.MethodPost, "/flight/revalidate", bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			aggregator.Revalidate(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+
+			var response map[string]interface{}
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			if tt.wantStatus == http.StatusOK {
+				data := response["data"].(map[string]interface{})
+				assert.Equal(t, service.RepriceStatusPriceChanged, data["status"])
+				assert.Equal(t, float64(1000000), data["old_amount"])
+				assert.Equal(t, float64(1100000), data["new_amount"])
+			}
+		})
+	}
+}
+
+func TestSeatMap(t *testing.T) {
+	tests := []struct {
+		name       string
+		id         string
+		seatMap    service.SeatMap
+		err        error
+		wantStatus int
+	}{
+		{name: "success", id: "offer-1", seatMap: service.SeatMap{OfferID: "offer-1", Aircraft: "Airbus A320"}, wantStatus: http.StatusOK},
+		{name: "invalid id", id: "bad", err: service.ErrInvalidOfferID, wantStatus: http.StatusBadRequest},
+		{name: "not supported", id: "offer-2", err: service.ErrSeatMapUnavailable, wantStatus: http.StatusNotFound},
+		{name: "offer not found", id: "gone", err: service.ErrOfferNotFound, wantStatus: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockFlightAggregator{}
+			aggregator.Init(mockService, time.Minute)
+			mockService.On("SeatMap", mock.Anything, tt.id).Return(tt.seatMap, tt.err)
+
+			r := chi.NewRouter()
+			r.Get("/flight/{id}/seatmap", aggregator.SeatMap)
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/flight/"+tt.id+"/seatmap", nil))
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAncillaries(t *testing.T) {
+	tests := []struct {
+		name       string
+		id         string
+		catalogue  service.AncillaryCatalogue
+		err        error
+		wantStatus int
+	}{
+		{name: "success", id: "offer-1", catalogue: service.AncillaryCatalogue{OfferID: "offer-1", Ancillaries: []service.Ancillary{{Code: "BAG20"}}}, wantStatus: http.StatusOK},
+		{name: "invalid id", id: "bad", err: service.ErrInvalidOfferID, wantStatus: http.StatusBadRequest},
+		{name: "not found", id: "gone", err: service.ErrOfferNotFound, wantStatus: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockFlightAggregator{}
+			aggregator.Init(mockService, time.Minute)
+			mockService.On("Ancillaries", mock.Anything, tt.id).Return(tt.catalogue, tt.err)
+
+			r := chi.NewRouter()
+			r.Get("/flight/{id}/ancillaries", aggregator.Ancillaries)
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/flight/"+tt.id+"/ancillaries", nil))
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestFareRules(t *testing.T) {
+	tests := []struct {
+		name       string
+		id         string
+		rules      service.FareRules
+		err        error
+		wantStatus int
+	}{
+		{name: "success", id: "offer-1", rules: service.FareRules{OfferID: "offer-1", FareRulesSummary: service.FareRulesSummary{Family: service.FareFamilyLite}}, wantStatus: http.StatusOK},
+		{name: "invalid id", id: "bad", err: service.ErrInvalidOfferID, wantStatus: http.StatusBadRequest},
+		{name: "not found", id: "gone", err: service.ErrOfferNotFound, wantStatus: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockFlightAggregator{}
+			aggregator.Init(mockService, time.Minute)
+			mockService.On("FareRules", mock.Anything, tt.id).Return(tt.rules, tt.err)
+
+			r := chi.NewRouter()
+			r.Get("/flight/{id}/fare-rules", aggregator.FareRules)
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/flight/"+tt.id+"/fare-rules", nil))
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			if tt.err == nil {
+				var response map[string]interface{}
+				assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+				data := response["data"].(map[string]interface{})
+				assert.Equal(t, service.FareFamilyLite, data["family"])
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestSearchStream(t *testing.T) {
+	mockService := &MockFlightAggregator{}
+	aggregator.Init(mockService, time.Minute)
+
+	summary := service.SearchResponse{SearchID: "search-1", Flights: []service.UnifiedFlight{{ID: "GA400"}}}
+	events := make(chan service.SearchEvent, 3)
+	events <- service.SearchEvent{Type: service.SearchEventProviderResults, Provider: "Garuda Indonesia", Flights: []service.UnifiedFlight{{ID: "GA400"}}}
+	events <- service.SearchEvent{Type: service.SearchEventProviderError, Provider: "Lion Air", Error: "timeout"}
+	events <- service.SearchEvent{Type: service.SearchEventSummary, Summary: &summary}
+	close(events)
+
+	mockService.On("SearchStream", mock.Anything, service.SearchCriteria{
+		Origin:        "CGK",
+		Destination:   "DPS",
+		DepartureDate: "2030-12-15",
+		Passengers:    2,
+		CabinClass:    "economy",
+	}).Return((<-chan service.SearchEvent)(events), nil)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/flight/search/stream?origin=cgk&destination=DPS&departure_date=2030-12-15&passengers=2&cabin_class=economy", nil)
+	aggregator.SearchStream(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/event-stream", w.Header().Get("Content-Type"))
+
+	body := w.Body.String()
+	assert.Contains(t, body, "event: provider_results\ndata: {\"type\":\"provider_results\"")
+	assert.Contains(t, body, "event: provider_error\n")
+	assert.Contains(t, body, "event: summary\n")
+	assert.True(t, strings.HasSuffix(body, "\n\n"))
+	assert.Less(t, strings.Index(body, "event: provider_results"), strings.Index(body, "event: summary"))
+	mockService.AssertExpectations(t)
+}
+
+func TestSearchStream_BadRequest(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		err   error
+	}{
+		{name: "missing route", query: "origin=CGK"},
+		{name: "invalid passengers", query: "origin=CGK&destination=DPS&departure_date=2030-12-15&passengers=two"},
+		{name: "invalid segment", query: "segment=CGK,DPS"},
+		{name: "invalid criteria", query: "origin=CGK&destination=CGK&departure_date=2030-12-15"},
+		{name: "invalid itinerary", query: "origin=CGK&destination=DPS&departure_date=2030-12-15&passengers=1&cabin_class=economy&trip_type=round_trip", err: fmt.Errorf("%w: return date is required for round trip", service.ErrInvalidItinerary)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockFlightAggregator{}
+			aggregator.Init(mockService, time.Minute)
+			if tt.err != nil {
+				mockService.On("SearchStream", mock.Anything, mock.Anything).Return(nil, tt.err)
+			}
+
+			w := httptest.NewRecorder()
+			aggregator.SearchStream(w, httptest.NewRequest(http.MethodGet, "/flight/search/stream?"+tt.query, nil))
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestSearchStream_SetupError(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		status int
+	}{
+		{name: "rates unavailable", err: service.ErrRatesUnavailable, status: http.StatusServiceUnavailable},
+		{name: "unsupported currency", err: service.ErrUnsupportedCurrency, status: http.StatusBadRequest},
+		{name: "unexpected", err: errors.New("boom"), status: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockFlightAggregator{}
+			aggregator.Init(mockService, time.Minute)
+			mockService.On("SearchStream", mock.Anything, mock.Anything).Return(nil, tt.err)
+
+			w := httptest.NewRecorder()
+			aggregator.SearchStream(w, httptest.NewRequest(http.MethodGet, "/flight/search/stream?origin=CGK&destination=DPS&departure_date=2030-12-15&passengers=1&cabin_class=economy&currency=USD", nil))
+
+			assert.Equal(t, tt.status, w.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestSearchQuery(t *testing.T) {
+	mockService := &MockFlightAggregator{}
+	aggregator.Init(mockService, 2*time.Minute)
+
+	criteria := service.SearchCriteria{Origin: "CGK", Destination: "DPS", DepartureDate: "2030-12-15", Passengers: 1, CabinClass: "economy"}
+	generatedAt := time.Date(2025, 12, 1, 8, 0, 0, 0, time.UTC)
+	first := service.SearchResponse{SearchID: "search-1", Criteria: criteria, Flights: []service.UnifiedFlight{{ID: "GA400"}}, Metadata: service.Metadata{SearchTimeMs: 120}, GeneratedAt: generatedAt}
+	second := first
+	second.SearchID = "search-2"
+	second.Metadata.SearchTimeMs = 80
+	second.GeneratedAt = generatedAt.Add(time.Hour)
+	changed := first
+	changed.Flights = []service.UnifiedFlight{{ID: "JT650"}}
+
+	mockService.On("SearchAll", mock.Anything, criteria).Return(first, nil).Once()
+	mockService.On("SearchAll", mock.Anything, criteria).Return(second, nil).Once()
+	mockService.On("SearchAll", mock.Anything, criteria).Return(changed, nil).Once()
+
+	target := "/flight/search?origin=CGK&destination=DPS&departure_date=2030-12-15&passengers=1&cabin_class=economy"
+
+	w := httptest.NewRecorder()
+	aggregator.SearchQuery(w, httptest.NewRequest(http.MethodGet, target, nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "public, max-age=120", w.Header().Get("Cache-Control"))
+	assert.Equal(t, "Mon, 01 Dec 2025 08:00:00 GMT", w.Header().Get("Last-Modified"))
+	etag := w.Header().Get("ETag")
+	assert.True(t, strings.HasPrefix(etag, `W/"`))
+
+	// a new run with the same flights keeps the tag
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	req.Header.Set("If-None-Match", `"other", `+etag)
+	w = httptest.NewRecorder()
+	aggregator.SearchQuery(w, req)
+
+	assert.Equal(t, http.StatusNotModified, w.Code)
+	assert.Empty(t, w.Body.String())
+	assert.Equal(t, etag, w.Header().Get("ETag"))
+	// the results have not changed since the first run
+	assert.Equal(t, "Mon, 01 Dec 2025 08:00:00 GMT", w.Header().Get("Last-Modified"))
+
+	// different flights get a different tag
+	req = httptest.NewRequest(http.MethodGet, target, nil)
+	req.Header.Set("If-None-Match", etag)
+	w = httptest.NewRecorder()
+	aggregator.SearchQuery(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotEqual(t, etag, w.Header().Get("ETag"))
+	mockService.AssertExpectations(t)
+}
+
+func TestSearchQuery_Segments(t *testing.T) {
+	mockService := &MockFlightAggregator{}
+	aggregator.Init(mockService, time.Minute)
+
+	mockService.On("SearchAll", mock.Anything, service.SearchCriteria{
+		Passengers: 2,
+		CabinClass: "economy",
+		Segments: []service.RouteSegment{
+			{Origin: "CGK", Destination: "DPS", DepartureDate: "2030-12-15"},
+			{Origin: "DPS", Destination: "SUB", DepartureDate: "2030-12-17"},
+		},
+		RefundableOnly: true,
+	}).Return(service.SearchResponse{}, nil)
+
+	w := httptest.NewRecorder()
+	aggregator.SearchQuery(w, httptest.NewRequest(http.MethodGet,
+		"/flight/search?segment=cgk,dps,2030-12-15&segment=DPS,SUB,2030-12-17&passengers=2&cabin_class=economy&refundable_only=true", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestSearchQuery_BadRequest(t *testing.T) {
+	mockService := &MockFlightAggregator{}
+	aggregator.Init(mockService, time.Minute)
+
+	w := httptest.NewRecorder()
+	aggregator.SearchQuery(w, httptest.NewRequest(http.MethodGet, "/flight/search?origin=CGK&destination=DPS&departure_date=2030-12-15&refundable_only=maybe", nil))
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Empty(t, w.Header().Get("ETag"))
+	mockService.AssertNotCalled(t, "SearchAll", mock.Anything, mock.Anything)
+}
+
+func TestSearchV2(t *testing.T) {
+	mockService := &MockFlightAggregator{}
+	aggregator.Init(mockService, time.Minute)
+
+	criteria := service.SearchCriteria{Origin: "CGK", Destination: "DPS", DepartureDate: "2030-12-15", Passengers: 1, CabinClass: "economy"}
+	mockService.On("SearchAll", mock.Anything, criteria).Return(service.SearchResponse{
+		SearchID: "search-1",
+		Criteria: criteria,
+		Flights: []service.UnifiedFlight{{
+			ID:        "QZ7510",
+			Departure: service.LocationInfo{Airport: "CGK", DateTime: "2025-12-15T04:45:00+0700", Timestamp: 1765748700},
+			Score:     5.2,
+		}},
+	}, nil)
+
+	body := `{"origin":"CGK","destination":"DPS","departure_date":"2030-12-15","passengers":1,"cabin_class":"economy"}`
+	req := httptest.NewRequest(http.MethodPost, "/v2/flight/search", strings.NewReader(body))
+	w := httptest.NewRecorder()
+	aggregator.SearchV2(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var resp struct {
+		Data map[string]interface{} `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, []interface{}{}, resp.Data["return_flights"])
+	assert.Equal(t, "CGK", resp.Data["search_criteria"].(map[string]interface{})["origin"])
+
+	flight := resp.Data["flights"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, 5.2, flight["score"])
+	assert.Equal(t, "2025-12-15T04:45:00+07:00", flight["departure"].(map[string]interface{})["datetime"])
+	mockService.AssertExpectations(t)
+}
+
+func TestSearchV2_BadRequest(t *testing.T) {
+	mockService := &MockFlightAggregator{}
+	aggregator.Init(mockService, time.Minute)
+
+	tests := []struct {
+		name string
+		body string
+	}{
+		{name: "invalid json", body: `{"origin":`},
+		{name: "v1 field names", body: `{"Origin":"CGK","Destination":"DPS","DepartureDate":"2030-12-15","Passengers":1,"CabinClass":"economy"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			aggregator.SearchV2(w, httptest.NewRequest(http.MethodPost, "/v2/flight/search", strings.NewReader(tt.body)))
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+		})
+	}
+	mockService.AssertNotCalled(t, "SearchAll", mock.Anything, mock.Anything)
+}
+
+func TestSearchQueryV2(t *testing.T) {
+	mockService := &MockFlightAggregator{}
+	aggregator.Init(mockService, time.Minute)
+
+	criteria := service.SearchCriteria{Origin: "CGK", Destination: "DPS", DepartureDate: "2030-12-15", Passengers: 1, CabinClass: "economy"}
+	mockService.On("SearchAll", mock.Anything, criteria).Return(service.SearchResponse{Criteria: criteria}, nil)
+
+	w := httptest.NewRecorder()
+	aggregator.SearchQueryV2(w, httptest.NewRequest(http.MethodGet,
+		"/v2/flight/search?origin=CGK&destination=DPS&departure_date=2030-12-15&passengers=1&cabin_class=economy", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotEmpty(t, w.Header().Get("ETag"))
+
+	var resp struct {
+		Data map[string]interface{} `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, []interface{}{}, resp.Data["flights"])
+	assert.Equal(t, []interface{}{}, resp.Data["return_flights"])
+	mockService.AssertExpectations(t)
+}
+
+func TestGetFlightV2(t *testing.T) {
+	mockService := &MockFlightAggregator{}
+	aggregator.Init(mockService, time.Minute)
+	mockService.On("GetFlight", mock.Anything, "offer-1").Return(service.UnifiedFlight{ID: "offer-1", FlightNumber: "GA400"}, nil)
+	mockService.On("GetFlight", mock.Anything, "gone").Return(service.UnifiedFlight{}, service.ErrOfferNotFound)
+
+	r := chi.NewRouter()
+	r.Get("/v2/flight/{id}", aggregator.GetFlightV2)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v2/flight/offer-1", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var resp struct {
+		Data map[string]interface{} `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "GA400", resp.Data["flight_number"])
+	assert.Equal(t, []interface{}{}, resp.Data["segments"])
+	assert.Equal(t, []interface{}{}, resp.Data["amenities"])
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v2/flight/gone", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestSearch_Export(t *testing.T) {
+	criteria := service.SearchCriteria{Origin: "CGK", Destination: "DPS", DepartureDate: "2030-12-15", Passengers: 1, CabinClass: "economy"}
+	result := service.SearchResponse{
+		SearchID: "search-1",
+		Criteria: criteria,
+		Flights: []service.UnifiedFlight{
+			{ID: "GA400", FlightNumber: "GA400", Departure: service.LocationInfo{Airport: "CGK", Timestamp: 1765753200}, Arrival: service.LocationInfo{Airport: "DPS", Timestamp: 1765759800}},
+			{ID: "JT650", FlightNumber: "JT650", Departure: service.LocationInfo{Airport: "CGK", Timestamp: 1765760400}, Arrival: service.LocationInfo{Airport: "DPS", Timestamp: 1765767000}},
+		},
+	}
+	body := `{"Origin":"CGK","Destination":"DPS","DepartureDate":"2030-12-15","Passengers":1,"CabinClass":"economy"}`
+
+	tests := []struct {
+		name       string
+		target     string
+		accept     string
+		wantStatus int
+		wantType   string
+		wantFile   string
+		wantLines  int
+	}{
+		{name: "csv by query", target: "/flight/search?format=csv", wantStatus: http.StatusOK, wantType: "text/csv; charset=utf-8", wantFile: "flights-search-1.csv", wantLines: 3},
+		{name: "ndjson by accept", target: "/flight/search", accept: "application/x-ndjson", wantStatus: http.StatusOK, wantType: "application/x-ndjson", wantFile: "flights-search-1.ndjson", wantLines: 2},
+		{name: "ics by accept", target: "/flight/search", accept: "text/calendar", wantStatus: http.StatusOK, wantType: "text/calendar; charset=utf-8", wantFile: "flights-search-1.ics"},
+		{name: "unsupported format", target: "/flight/search?format=xlsx", wantStatus: http.StatusNotAcceptable, wantType: "application/json"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockFlightAggregator{}
+			aggregator.Init(mockService, time.Minute)
+			mockService.On("SearchAll", mock.Anything, criteria).Return(result, nil)
+
+			req := httptest.NewRequest(http.MethodPost, tt.target, strings.NewReader(body))
+			req.Header.Set("Accept", tt.accept)
+			w := httptest.NewRecorder()
+			aggregator.Search(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			assert.Contains(t, w.Header().Get("Content-Type"), tt.wantType)
+			if tt.wantFile != "" {
+				assert.Equal(t, `attachment; filename="`+tt.wantFile+`"`, w.Header().Get("Content-Disposition"))
+			}
+			if tt.wantLines > 0 {
+				assert.Equal(t, tt.wantLines, strings.Count(w.Body.String(), "\n"))
+			}
+		})
+	}
+}
+
+func TestExport_OtherEndpointsStayJSON(t *testing.T) {
+	mockService := &MockFlightAggregator{}
+	aggregator.Init(mockService, time.Minute)
+
+	criteria := service.SearchCriteria{Origin: "CGK", Destination: "DPS", DepartureDate: "2030-12-15", Passengers: 1, CabinClass: "economy"}
+	mockService.On("SearchAll", mock.Anything, criteria).Return(service.SearchResponse{Criteria: criteria, Flights: []service.UnifiedFlight{{ID: "GA400"}}}, nil)
+	mockService.On("SeatMap", mock.Anything, "offer-1").Return(service.SeatMap{OfferID: "offer-1"}, nil)
+
+	r := chi.NewRouter()
+	r.Get("/v2/flight/search", aggregator.SearchQueryV2)
+	r.Get("/flight/{id}/seatmap", aggregator.SeatMap)
+
+	tests := []struct {
+		name   string
+		target string
+		accept string
+	}{
+		{name: "v2 search with format", target: "/v2/flight/search?origin=CGK&destination=DPS&departure_date=2030-12-15&passengers=1&cabin_class=economy&format=csv"},
+		{name: "v2 search with accept", target: "/v2/flight/search?origin=CGK&destination=DPS&departure_date=2030-12-15&passengers=1&cabin_class=economy", accept: "text/csv"},
+		{name: "seat map with format", target: "/flight/offer-1/seatmap?format=ics"},
+		{name: "seat map with unknown format", target: "/flight/offer-1/seatmap?format=xlsx"},
+		{name: "seat map with accept", target: "/flight/offer-1/seatmap", accept: "text/calendar"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.target, nil)
+			req.Header.Set("Accept", tt.accept)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Contains(t, w.Header().Get("Content-Type"), "application/json")
+			assert.Empty(t, w.Header().Get("Content-Disposition"))
+			assert.NotContains(t, w.Header().Values("Vary"), "Accept")
+		})
+	}
+}
+
+func TestSearchQuery_ExportETag(t *testing.T) {
+	mockService := &MockFlightAggregator{}
+	aggregator.Init(mockService, time.Minute)
+
+	criteria := service.SearchCriteria{Origin: "CGK", Destination: "DPS", DepartureDate: "2030-12-15", Passengers: 1, CabinClass: "economy"}
+	mockService.On("SearchAll", mock.Anything, criteria).Return(service.SearchResponse{Criteria: criteria, Flights: []service.UnifiedFlight{{ID: "GA400"}}}, nil)
+
+	target := "/flight/search?origin=CGK&destination=DPS&departure_date=2030-12-15&passengers=1&cabin_class=economy"
+
+	w := httptest.NewRecorder()
+	aggregator.SearchQuery(w, httptest.NewRequest(http.MethodGet, target, nil))
+	jsonTag := w.Header().Get("ETag")
+
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	req.Header.Set("Accept", "text/csv")
+	req.Header.Set("If-None-Match", jsonTag)
+	w = httptest.NewRecorder()
+	aggregator.SearchQuery(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Header().Get("Content-Type"), "text/csv")
+	assert.NotEqual(t, jsonTag, w.Header().Get("ETag"))
+	assert.Contains(t, w.Header().Values("Vary"), "Accept")
+}
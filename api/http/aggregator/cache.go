@@ -0,0 +1,116 @@
+package aggregator
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/elkoshar/bookcabin/pkg/response"
+	"github.com/elkoshar/bookcabin/service"
+)
+
+// maxResultTimes bounds the tags resultTimes remembers, it starts over once it is full
+const maxResultTimes = 10000
+
+// resultTimes remembers when the results behind each tag were first gathered. Every GET runs a new
+// search, so without it a repeated search that finds the same flights would get the same ETag but a
+// newer Last-Modified.
+var resultTimes = struct {
+	mu    sync.Mutex
+	first map[string]time.Time
+}{first: make(map[string]time.Time)}
+
+// lastModified returns the time the results tagged etag were first gathered, generatedAt when they are new
+func lastModified(etag string, generatedAt time.Time) time.Time {
+	if generatedAt.IsZero() {
+		generatedAt = time.Now()
+	}
+
+	resultTimes.mu.Lock()
+	defer resultTimes.mu.Unlock()
+
+	if first, ok := resultTimes.first[etag]; ok {
+		return first
+	}
+	if len(resultTimes.first) >= maxResultTimes {
+		clear(resultTimes.first)
+	}
+	resultTimes.first[etag] = generatedAt
+	return generatedAt
+}
+
+// renderCached renders data, the body for the search result in the schema of the route, with caching
+// headers. A request whose If-None-Match matches the result gets 304 Not Modified instead. Only an
+// exportable body is rendered in the format the request asks for, any other is JSON.
+func renderCached(w http.ResponseWriter, r *http.Request, result service.SearchResponse, data interface{}, exportable bool) {
+	resp := response.Response{Exportable: exportable, Filename: "flights-" + result.SearchID}
+
+	// each format is a representation of its own, so it gets its own tag
+	format := response.FormatJSON
+	if exportable {
+		negotiated, _ := response.NegotiateFormat(r)
+		format = negotiated.Name
+	}
+	etag, err := searchETag(result, format)
+	if err != nil {
+		slog.WarnContext(r.Context(), fmt.Sprintf(ErrCreateDataMsg, err))
+		resp.SetError(err, http.StatusInternalServerError)
+		resp.Render(w, r)
+		return
+	}
+
+	w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", int(searchMaxAge.Seconds())))
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Last-Modified", lastModified(etag, result.GeneratedAt).UTC().Format(http.TimeFormat))
+	if exportable {
+		w.Header().Add("Vary", "Accept")
+	}
+
+	if etagMatches(r.Header.Get("If-None-Match"), etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	resp.Data = data
+	resp.Code = http.StatusOK
+	resp.Render(w, r)
+}
+
+// searchETag fingerprints the results of a search rendered in a format. The search ID and timing differ
+// on every run, so they are left out and the tag is weak: equal tags mean equal flights, not equal bytes.
+func searchETag(resp service.SearchResponse, format string) (string, error) {
+	resp.SearchID = ""
+	resp.Metadata.SearchTimeMs = 0
+
+	data, err := json.Marshal(resp)
+	if err != nil {
+		return "", err
+	}
+	if format != response.FormatJSON {
+		data = append(data, format...)
+	}
+
+	sum := sha256.Sum256(data)
+	return `W/"` + hex.EncodeToString(sum[:16]) + `"`, nil
+}
+
+// etagMatches reports whether an If-None-Match header lists the tag, using weak comparison
+func etagMatches(ifNoneMatch, etag string) bool {
+	if ifNoneMatch == "" {
+		return false
+	}
+
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,84 @@
+package aggregator
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/elkoshar/bookcabin/pkg/validator"
+	"github.com/elkoshar/bookcabin/service"
+)
+
+// parseSearchQuery reads and validates search criteria from query parameters. Each multi-city leg
+// is passed as a segment parameter in the form ORIGIN,DESTINATION,DATE.
+func parseSearchQuery(query url.Values) (service.SearchCriteria, error) {
+	criteria := service.SearchCriteria{
+		Origin:        query.Get("origin"),
+		Destination:   query.Get("destination"),
+		DepartureDate: query.Get("departure_date"),
+		ReturnDate:    query.Get("return_date"),
+		CabinClass:    query.Get("cabin_class"),
+		TripType:      query.Get("trip_type"),
+		Currency:      query.Get("currency"),
+	}
+
+	var err error
+	if criteria.Passengers, err = queryInt(query, "passengers"); err != nil {
+		return service.SearchCriteria{}, err
+	}
+	if criteria.ExcludeDominated, err = queryBool(query, "exclude_dominated"); err != nil {
+		return service.SearchCriteria{}, err
+	}
+	if criteria.RefundableOnly, err = queryBool(query, "refundable_only"); err != nil {
+		return service.SearchCriteria{}, err
+	}
+	if v := query.Get("nearby_radius_km"); v != "" {
+		if criteria.NearbyRadiusKm, err = strconv.ParseFloat(v, 64); err != nil {
+			return service.SearchCriteria{}, fmt.Errorf("invalid nearby_radius_km: %q", v)
+		}
+	}
+
+	for _, v := range query["segment"] {
+		parts := strings.Split(v, ",")
+		if len(parts) != 3 {
+			return service.SearchCriteria{}, fmt.Errorf("invalid segment %q, expected ORIGIN,DESTINATION,DATE", v)
+		}
+		criteria.Segments = append(criteria.Segments, service.RouteSegment{
+			Origin:        parts[0],
+			Destination:   parts[1],
+			DepartureDate: strings.TrimSpace(parts[2]),
+		})
+	}
+
+	criteria.Normalize()
+	if _, err := validator.ValidateStruct(criteria); err != nil {
+		return service.SearchCriteria{}, err
+	}
+
+	return criteria, nil
+}
+
+func queryInt(query url.Values, key string) (int, error) {
+	v := query.Get(key)
+	if v == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s: %q", key, v)
+	}
+	return n, nil
+}
+
+func queryBool(query url.Values, key string) (bool, error) {
+	v := query.Get(key)
+	if v == "" {
+		return false, nil
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		return false, fmt.Errorf("invalid %s: %q", key, v)
+	}
+	return b, nil
+}
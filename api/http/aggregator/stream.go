@@ -0,0 +1,82 @@
+package aggregator
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/elkoshar/bookcabin/pkg/i18n"
+	"github.com/elkoshar/bookcabin/pkg/response"
+)
+
+var errStreamingUnsupported = errors.New("streaming is not supported by this connection")
+
+// SearchStream : HTTP Handler for streaming flight search results as Server-Sent Events
+// @Summary Stream Flight Search
+// @Description SearchStream pushes the flights of each provider as a provider_results event as soon as it answers, a provider_error event when it fails, and ends with a summary event carrying the final ranking and metadata
+// @Tags Flight
+// @Produce text/event-stream
+// @Param origin query string false "Origin airport or metro code"
+// @Param destination query string false "Destination airport or metro code"
+// @Param departure_date query string false "Departure date (YYYY-MM-DD)"
+// @Param return_date query string false "Return date (YYYY-MM-DD)"
+// @Param passengers query int false "Number of passengers"
+// @Param cabin_class query string false "Cabin class"
+// @Param trip_type query string false "Trip type"
+// @Param segment query []string false "Multi-city leg as ORIGIN,DESTINATION,DATE" collectionFormat(multi)
+// @Param exclude_dominated query bool false "Drop flights that are both pricier and slower than another"
+// @Param refundable_only query bool false "Keep only refundable fares"
+// @Param nearby_radius_km query number false "Also search airports within this distance"
+// @Success 200 {object} service.SearchEvent "Stream of search events"
+// @Router /flight/search/stream [GET]
+func SearchStream(w http.ResponseWriter, r *http.Request) {
+
+	resp := response.Response{}
+
+	criteria, err := parseSearchQuery(r.URL.Query())
+	if err != nil {
+		slog.WarnContext(r.Context(), fmt.Sprintf(ErrParseValidateMsg, err))
+		resp.SetError(err, http.StatusBadRequest)
+		resp.Render(w, r)
+		return
+	}
+
+	events, err := flightAggregator.SearchStream(r.Context(), criteria)
+	if err != nil {
+		slog.WarnContext(r.Context(), fmt.Sprintf(ErrCreateDataMsg, err))
+		resp.SetError(err)
+		resp.Render(w, r)
+		return
+	}
+
+	rc := http.NewResponseController(w)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	if err := rc.Flush(); err != nil {
+		slog.WarnContext(r.Context(), fmt.Sprintf(ErrGetDataMsg, errStreamingUnsupported))
+		return
+	}
+
+	lang := i18n.FromContext(r.Context())
+	for event := range events {
+		data, err := json.Marshal(event.Localize(lang))
+		if err != nil {
+			slog.WarnContext(r.Context(), fmt.Sprintf(ErrGetDataMsg, err))
+			continue
+		}
+
+		if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Type, data); err != nil {
+			return
+		}
+		if err := rc.Flush(); err != nil {
+			return
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package aggregator
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	v2 "github.com/elkoshar/bookcabin/api/http/v2"
+	"github.com/elkoshar/bookcabin/pkg/i18n"
+	"github.com/elkoshar/bookcabin/pkg/response"
+	"github.com/elkoshar/bookcabin/pkg/validator"
+	"github.com/go-chi/chi/v5"
+)
+
+// SearchV2 : HTTP Handler for searching flights with the v2 schema
+// @Summary Search Flight (v2)
+// @Description SearchV2 takes and returns snake_case fields, renders empty lists as arrays, exposes the ranking score and normalizes every datetime to RFC 3339
+// @Tags Flight v2
+// @Accept json
+// @Produce json
+// @Param Accept-Language header string true "accept language" default(id)
+// @Param body body v2.SearchRequest true "Request Body"
+// @Success 200 {object} response.Response{data=v2.SearchResponse} "Success Response"
+// @Router /v2/flight/search [POST]
+func SearchV2(w http.ResponseWriter, r *http.Request) {
+
+	resp := response.Response{}
+	defer resp.Render(w, r)
+
+	var req v2.SearchRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		slog.WarnContext(r.Context(), fmt.Sprintf(ErrParseValidateMsg, err))
+		resp.SetError(err, http.StatusBadRequest)
+		return
+	}
+
+	criteria := req.Criteria()
+	criteria.Normalize()
+	if _, err := validator.ValidateStruct(criteria); err != nil {
+		slog.WarnContext(r.Context(), fmt.Sprintf(ErrParseValidateMsg, err))
+		resp.SetError(err, http.StatusBadRequest)
+		return
+	}
+
+	result, err := flightAggregator.SearchAll(r.Context(), criteria)
+	if err != nil {
+		slog.WarnContext(r.Context(), fmt.Sprintf(ErrCreateDataMsg, err))
+		resp.SetError(err)
+		return
+	}
+
+	resp.Data = v2.FromSearchResponse(result.Localize(i18n.FromContext(r.Context())))
+	resp.Code = http.StatusOK
+}
+
+// SearchQueryV2 : HTTP Handler for searching flights with query parameters and the v2 schema
+// @Summary Search Flight (v2, cacheable)
+// @Description SearchQueryV2 takes the query parameters of the v1 GET search and returns the v2 schema, with the same caching headers
+// @Tags Flight v2
+// @Produce json
+// @Param Accept-Language header string true "accept language" default(id)
+// @Param If-None-Match header string false "ETag of a previous response"
+// @Param origin query string false "Origin airport or metro code"
+// @Param destination query string false "Destination airport or metro code"
+// @Param departure_date query string false "Departure date (YYYY-MM-DD)"
+// @Param passengers query int false "Number of passengers"
+// @Param cabin_class query string false "Cabin class"
+// @Success 200 {object} response.Response{data=v2.SearchResponse} "Success Response"
+// @Success 304 "Not Modified"
+// @Router /v2/flight/search [GET]
+func SearchQueryV2(w http.ResponseWriter, r *http.Request) {
+
+	resp := response.Response{}
+
+	criteria, err := parseSearchQuery(r.URL.Query())
+	if err != nil {
+		slog.WarnContext(r.Context(), fmt.Sprintf(ErrParseValidateMsg, err))
+		resp.SetError(err, http.StatusBadRequest)
+		resp.Render(w, r)
+		return
+	}
+
+	result, err := flightAggregator.SearchAll(r.Context(), criteria)
+	if err != nil {
+		slog.WarnContext(r.Context(), fmt.Sprintf(ErrCreateDataMsg, err))
+		resp.SetError(err)
+		resp.Render(w, r)
+		return
+	}
+	result = result.Localize(i18n.FromContext(r.Context()))
+
+	renderCached(w, r, result, v2.FromSearchResponse(result), false)
+}
+
+// GetFlightV2 : HTTP Handler for getting the current details of a flight offer with the v2 schema
+// @Summary Get Flight (v2)
+// @Description GetFlightV2 re-queries the provider of a previously returned offer and returns it in the v2 schema
+// @Tags Flight v2
+// @Produce json
+// @Param Accept-Language header string true "accept language" default(id)
+// @Param id path string true "Offer ID"
+// @Success 200 {object} response.Response{data=v2.Flight} "Success Response"
+// @Router /v2/flight/{id} [GET]
+func GetFlightV2(w http.ResponseWriter, r *http.Request) {
+
+	resp := response.Response{}
+	defer resp.Render(w, r)
+
+	result, err := flightAggregator.GetFlight(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		slog.WarnContext(r.Context(), fmt.Sprintf(ErrGetDataMsg, err))
+		resp.SetError(err)
+		return
+	}
+
+	resp.Data = v2.FromFlight(result.Localize(i18n.FromContext(r.Context())))
+	resp.Code = http.StatusOK
+}
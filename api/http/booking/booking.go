@@ -0,0 +1,317 @@
+package booking
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/elkoshar/bookcabin/api"
+	"github.com/elkoshar/bookcabin/pkg/helpers"
+	"github.com/elkoshar/bookcabin/pkg/i18n"
+	"github.com/elkoshar/bookcabin/pkg/response"
+	"github.com/elkoshar/bookcabin/service"
+	"github.com/go-chi/chi/v5"
+)
+
+const (
+	ErrCreateDataMsg    = "Create Data Failed. %+v"
+	ErrGetDataMsg       = "Get Data Failed. %+v"
+	ErrCancelDataMsg    = "Cancel Data Failed. %+v"
+	ErrUpdateDataMsg    = "Update Data Failed. %+v"
+	ErrParseValidateMsg = "Failed to Parse and Validate. err=%v"
+)
+
+var (
+	flightBooking api.FlightBooking
+)
+
+func Init(service api.FlightBooking) {
+	flightBooking = service
+}
+
+// Create : HTTP Handler for creating a booking
+// @Summary Create Booking
+// @Description Create holds seats on an offer for the given passengers and returns a booking reference
+// @Tags Booking
+// @Accept json
+// @Produce json
+// @Param Accept-Language header string true "accept language" default(id)
+// @Param body body service.BookingRequest true "Request Body"
+// @Success 201 {object} response.Response{data=service.Booking} "Success Response"
+// @Router /bookings [POST]
+func Create(w http.ResponseWriter, r *http.Request) {
+
+	resp := response.Response{}
+	defer resp.Render(w, r)
+
+	var (
+		err    error
+		req    service.BookingRequest
+		result service.Booking
+	)
+
+	err = helpers.ParseBodyAndValidate(r, &req)
+	if err != nil {
+		slog.WarnContext(r.Context(), fmt.Sprintf(ErrParseValidateMsg, err))
+		resp.SetError(err, http.StatusBadRequest)
+		return
+	}
+
+	result, err = flightBooking.CreateBooking(r.Context(), req)
+	if err != nil {
+		slog.WarnContext(r.Context(), fmt.Sprintf(ErrCreateDataMsg, err))
+		resp.SetError(err)
+		return
+	}
+
+	resp.Data = result.Localize(i18n.FromContext(r.Context()))
+	resp.Code = http.StatusCreated
+}
+
+// Get : HTTP Handler for retrieving a booking
+// @Summary Get Booking
+// @Description Get returns a booking by its reference
+// @Tags Booking
+// @Produce json
+// @Param Accept-Language header string true "accept language" default(id)
+// @Param ref path string true "Booking Reference"
+// @Success 200 {object} response.Response{data=service.Booking} "Success Response"
+// @Router /bookings/{ref} [GET]
+func Get(w http.ResponseWriter, r *http.Request) {
+
+	resp := response.Response{}
+	defer resp.Render(w, r)
+
+	result, err := flightBooking.GetBooking(r.Context(), chi.URLParam(r, "ref"))
+	if err != nil {
+		slog.WarnContext(r.Context(), fmt.Sprintf(ErrGetDataMsg, err))
+		resp.SetError(err)
+		return
+	}
+
+	resp.Data = result.Localize(i18n.FromContext(r.Context()))
+	resp.Code = http.StatusOK
+}
+
+// Cancel : HTTP Handler for cancelling a held booking
+// @Summary Cancel Booking
+// @Description Cancel releases the seats held by a booking
+// @Tags Booking
+// @Produce json
+// @Param Accept-Language header string true "accept language" default(id)
+// @Param ref path string true "Booking Reference"
+// @Success 200 {object} response.Response{data=service.Booking} "Success Response"
+// @Router /bookings/{ref} [DELETE]
+func Cancel(w http.ResponseWriter, r *http.Request) {
+
+	resp := response.Response{}
+	defer resp.Render(w, r)
+
+	result, err := flightBooking.CancelBooking(r.Context(), chi.URLParam(r, "ref"))
+	if err != nil {
+		slog.WarnContext(r.Context(), fmt.Sprintf(ErrCancelDataMsg, err))
+		resp.SetError(err)
+		return
+	}
+
+	resp.Data = result.Localize(i18n.FromContext(r.Context()))
+	resp.Code = http.StatusOK
+}
+
+// SelectSeats : HTTP Handler for reserving seats on a held booking
+// @Summary Select Seats
+// @Description SelectSeats reserves seats for passengers of a held booking and adds the seat prices to its total
+// @Tags Booking
+// @Accept json
+// @Produce json
+// @Param Accept-Language header string true "accept language" default(id)
+// @Param ref path string true "Booking Reference"
+// @Param body body service.SeatSelectionRequest true "Request Body"
+// @Success 200 {object} response.Response{data=service.Booking} "Success Response"
+// @Router /bookings/{ref}/seats [POST]
+func SelectSeats(w http.ResponseWriter, r *http.Request) {
+
+	resp := response.Response{}
+	defer resp.Render(w, r)
+
+	var req service.SeatSelectionRequest
+	err := helpers.ParseBodyAndValidate(r, &req)
+	if err != nil {
+		slog.WarnContext(r.Context(), fmt.Sprintf(ErrParseValidateMsg, err))
+		resp.SetError(err, http.StatusBadRequest)
+		return
+	}
+
+	result, err := flightBooking.SelectSeats(r.Context(), chi.URLParam(r, "ref"), req)
+	if err != nil {
+		slog.WarnContext(r.Context(), fmt.Sprintf(ErrUpdateDataMsg, err))
+		resp.SetError(err)
+		return
+	}
+
+	resp.Data = result.Localize(i18n.FromContext(r.Context()))
+	resp.Code = http.StatusOK
+}
+
+// SelectAncillaries : HTTP Handler for attaching ancillaries to a held booking
+// @Summary Select Ancillaries
+// @Description SelectAncillaries replaces the ancillaries of a held booking and adds their prices to its total
+// @Tags Booking
+// @Accept json
+// @Produce json
+// @Param Accept-Language header string true "accept language" default(id)
+// @Param ref path string true "Booking Reference"
+// @Param body body service.AncillarySelectionRequest true "Request Body"
+// @Success 200 {object} response.Response{data=service.Booking} "Success Response"
+// @Router /bookings/{ref}/ancillaries [POST]
+func SelectAncillaries(w http.ResponseWriter, r *http.Request) {
+
+	resp := response.Response{}
+	defer resp.Render(w, r)
+
+	var req service.AncillarySelectionRequest
+	err := helpers.ParseBodyAndValidate(r, &req)
+	if err != nil {
+		slog.WarnContext(r.Context(), fmt.Sprintf(ErrParseValidateMsg, err))
+		resp.SetError(err, http.StatusBadRequest)
+		return
+	}
+
+	result, err := flightBooking.SelectAncillaries(r.Context(), chi.URLParam(r, "ref"), req)
+	if err != nil {
+		slog.WarnContext(r.Context(), fmt.Sprintf(ErrUpdateDataMsg, err))
+		resp.SetError(err)
+		return
+	}
+
+	resp.Data = result.Localize(i18n.FromContext(r.Context()))
+	resp.Code = http.StatusOK
+}
+
+// RequestCancellation : HTTP Handler for quoting and confirming the cancellation of a booking
+// @Summary Cancel Booking With Refund
+// @Description RequestCancellation returns a refund quote computed from the fare rules and the time to departure. Sending the quote_id back with confirm=true cancels the booking for that refund.
+// @Tags Booking
+// @Accept json
+// @Produce json
+// @Param Accept-Language header string true "accept language" default(id)
+// @Param ref path string true "Booking Reference"
+// @Param body body service.CancellationRequest true "Request Body"
+// @Success 200 {object} response.Response{data=service.CancellationQuote} "Quote Response"
+// @Success 200 {object} response.Response{data=service.Booking} "Confirm Response"
+// @Router /bookings/{ref}/cancel [POST]
+func RequestCancellation(w http.ResponseWriter, r *http.Request) {
+
+	resp := response.Response{}
+	defer resp.Render(w, r)
+
+	var req service.CancellationRequest
+	err := helpers.ParseBodyAndValidate(r, &req)
+	if err != nil {
+		slog.WarnContext(r.Context(), fmt.Sprintf(ErrParseValidateMsg, err))
+		resp.SetError(err, http.StatusBadRequest)
+		return
+	}
+
+	ref := chi.URLParam(r, "ref")
+	if !req.Confirm {
+		result, err := flightBooking.QuoteCancellation(r.Context(), ref)
+		if err != nil {
+			slog.WarnContext(r.Context(), fmt.Sprintf(ErrGetDataMsg, err))
+			resp.SetError(err)
+			return
+		}
+
+		resp.Data = result.Localize(i18n.FromContext(r.Context()))
+		resp.Code = http.StatusOK
+		return
+	}
+
+	result, err := flightBooking.ConfirmCancellation(r.Context(), ref, req.QuoteID)
+	if err != nil {
+		slog.WarnContext(r.Context(), fmt.Sprintf(ErrCancelDataMsg, err))
+		resp.SetError(err)
+		return
+	}
+
+	resp.Data = result.Localize(i18n.FromContext(r.Context()))
+	resp.Code = http.StatusOK
+}
+
+// Pay : HTTP Handler for paying a held booking
+// @Summary Pay Booking
+// @Description Pay authorizes the booking total on a card, captures it and tickets the booking. Cards that need a 3DS challenge return 202 with the payment's challenge_url, the booking is ticketed once the gateway's webhook reports the approval.
+// @Tags Booking
+// @Accept json
+// @Produce json
+// @Param Accept-Language header string true "accept language" default(id)
+// @Param ref path string true "Booking Reference"
+// @Param body body service.PaymentRequest true "Request Body"
+// @Success 200 {object} response.Response{data=service.Booking} "Success Response"
+// @Success 202 {object} response.Response{data=service.Booking} "Challenge Required"
+// @Router /bookings/{ref}/payment [POST]
+func Pay(w http.ResponseWriter, r *http.Request) {
+
+	resp := response.Response{}
+	defer resp.Render(w, r)
+
+	var req service.PaymentRequest
+	err := helpers.ParseBodyAndValidate(r, &req)
+	if err != nil {
+		slog.WarnContext(r.Context(), fmt.Sprintf(ErrParseValidateMsg, err))
+		resp.SetError(err, http.StatusBadRequest)
+		return
+	}
+
+	result, err := flightBooking.Pay(r.Context(), chi.URLParam(r, "ref"), req)
+	if err != nil {
+		slog.WarnContext(r.Context(), fmt.Sprintf(ErrUpdateDataMsg, err))
+		resp.SetError(err)
+		return
+	}
+
+	resp.Data = result.Localize(i18n.FromContext(r.Context()))
+	resp.Code = http.StatusOK
+	if result.Status == service.BookingStatusHeld {
+		resp.Code = http.StatusAccepted
+	}
+}
+
+// Ticket : HTTP Handler for downloading the e-ticket of a booking
+// @Summary Get E-ticket
+// @Description Ticket returns the itinerary receipt of a ticketed booking as PDF, or as HTML with format=html or an Accept header preferring text/html
+// @Tags Booking
+// @Produce application/pdf
+// @Produce text/html
+// @Param Accept-Language header string true "accept language" default(id)
+// @Param ref path string true "Booking Reference"
+// @Param format query string false "pdf or html"
+// @Success 200 {file} file "Itinerary Receipt"
+// @Router /bookings/{ref}/ticket [GET]
+func Ticket(w http.ResponseWriter, r *http.Request) {
+
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = service.TicketFormatPDF
+		if strings.Contains(r.Header.Get("Accept"), "text/html") {
+			format = service.TicketFormatHTML
+		}
+	}
+
+	doc, err := flightBooking.TicketDocument(r.Context(), chi.URLParam(r, "ref"), format)
+	if err != nil {
+		slog.WarnContext(r.Context(), fmt.Sprintf(ErrGetDataMsg, err))
+		resp := response.Response{}
+		resp.SetError(err)
+		resp.Render(w, r)
+		return
+	}
+
+	w.Header().Set("Content-Type", doc.ContentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("inline; filename=%q", doc.Filename))
+	w.Header().Set("Content-Length", strconv.Itoa(len(doc.Body)))
+	w.WriteHeader(http.StatusOK)
+	w.Write(doc.Body)
+}
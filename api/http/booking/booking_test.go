@@ -0,0 +1,360 @@
+package booking_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/elkoshar/bookcabin/api"
+	"github.com/elkoshar/bookcabin/api/http/booking"
+	"github.com/elkoshar/bookcabin/service"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockFlightBooking implements api.FlightBooking for testing
+type MockFlightBooking struct {
+	mock.Mock
+}
+
+var _ api.FlightBooking = (*MockFlightBooking)(nil)
+
+func (m *MockFlightBooking) CreateBooking(ctx context.Context, req service.BookingRequest) (service.Booking, error) {
+	args := m.Called(ctx, req)
+	return args.Get(0).(service.Booking), args.Error(1)
+}
+
+func (m *MockFlightBooking) GetBooking(ctx context.Context, reference string) (service.Booking, error) {
+	args := m.Called(ctx, reference)
+	return args.Get(0).(service.Booking), args.Error(1)
+}
+
+func (m *MockFlightBooking) CancelBooking(ctx context.Context, reference string) (service.Booking, error) {
+	args := m.Called(ctx, reference)
+	return args.Get(0).(service.Booking), args.Error(1)
+}
+
+func (m *MockFlightBooking) SelectSeats(ctx context.Context, reference string, req service.SeatSelectionRequest) (service.Booking, error) {
+	args := m.Called(ctx, reference, req)
+	return args.Get(0).(service.Booking), args.Error(1)
+}
+
+func (m *MockFlightBooking) SelectAncillaries(ctx context.Context, reference string, req service.AncillarySelectionRequest) (service.Booking, error) {
+	args := m.Called(ctx, reference, req)
+	return args.Get(0).(service.Booking), args.Error(1)
+}
+
+func (m *MockFlightBooking) QuoteCancellation(ctx context.Context, reference string) (service.CancellationQuote, error) {
+	args := m.Called(ctx, reference)
+	return args.Get(0).(service.CancellationQuote), args.Error(1)
+}
+
+func (m *MockFlightBooking) ConfirmCancellation(ctx context.Context, reference, quoteID string) (service.Booking, error) {
+	args := m.Called(ctx, reference, quoteID)
+	return args.Get(0).(service.Booking), args.Error(1)
+}
+
+func (m *MockFlightBooking) Pay(ctx context.Context, reference string, req service.PaymentRequest) (service.Booking, error) {
+	args := m.Called(ctx, reference, req)
+	return args.Get(0).(service.Booking), args.Error(1)
+}
+
+func (m *MockFlightBooking) HandlePaymentEvent(ctx context.Context, event service.PaymentEvent) error {
+	args := m.Called(ctx, event)
+	return args.Error(0)
+}
+
+func (m *MockFlightBooking) TicketDocument(ctx context.Context, reference, format string) (service.Document, error) {
+	args := m.Called(ctx, reference, format)
+	return args.Get(0).(service.Document), args.Error(1)
+}
+
+func newRouter() http.Handler {
+	r := chi.NewRouter()
+	r.Post("/bookings", booking.Create)
+	r.Get("/bookings/{ref}", booking.Get)
+	r.Delete("/bookings/{ref}", booking.Cancel)
+	r.Post("/bookings/{ref}/seats", booking.SelectSeats)
+	r.Post("/bookings/{ref}/ancillaries", booking.SelectAncillaries)
+	r.Post("/bookings/{ref}/cancel", booking.RequestCancellation)
+	r.Post("/bookings/{ref}/payment", booking.Pay)
+	r.Get("/bookings/{ref}/ticket", booking.Ticket)
+	return r
+}
+
+func validRequest() service.BookingRequest {
+	return service.BookingRequest{
+		OfferID: "offer-1",
+		Passengers: []service.Passenger{
+			{Title: "MR", FirstName: "Budi", LastName: "Santoso", DateOfBirth: "1990-01-01"},
+		},
+		Contact: service.ContactInfo{Email: "budi@example.com", Phone: "+628123456789"},
+	}
+}
+
+func TestCreate(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       interface{}
+		result     service.Booking
+		err        error
+		wantStatus int
+	}{
+		{name: "success", body: validRequest(), result: service.Booking{Reference: "ABC234", Status: service.BookingStatusHeld}, wantStatus: http.StatusCreated},
+		{name: "validation error", body: service.BookingRequest{OfferID: "offer-1"}, wantStatus: http.StatusBadRequest},
+		{name: "sold out", body: validRequest(), err: service.ErrInsufficientSeats, wantStatus: http.StatusConflict},
+		{name: "offer not found", body: validRequest(), err: service.ErrOfferNotFound, wantStatus: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockFlightBooking{}
+			booking.Init(mockService)
+			mockService.On("CreateBooking", mock.Anything, mock.Anything).Return(tt.result, tt.err).Maybe()
+
+			jsonBody, err := json.Marshal(tt.body)
+			assert.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodPost, "/bookings", bytes.NewBuffer(jsonBody))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			newRouter().ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+
+			var response map[string]interface{}
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			if tt.wantStatus == http.StatusCreated {
+				data := response["data"].(map[string]interface{})
+				assert.Equal(t, "ABC234", data["reference"])
+			}
+		})
+	}
+}
+
+func TestGetAndCancel(t *testing.T) {
+	mockService := &MockFlightBooking{}
+	booking.Init(mockService)
+
+	mockService.On("GetBooking", mock.Anything, "ABC234").Return(service.Booking{Reference: "ABC234", Status: service.BookingStatusHeld}, nil)
+	mockService.On("GetBooking", mock.Anything, "NOPE00").Return(service.Booking{}, service.ErrBookingNotFound)
+	mockService.On("CancelBooking", mock.Anything, "ABC234").Return(service.Booking{Reference: "ABC234", Status: service.BookingStatusCancelled}, nil)
+	mockService.On("CancelBooking", mock.Anything, "DONE00").Return(service.Booking{}, service.ErrBookingNotCancellable)
+
+	tests := []struct {
+		method     string
+		path       string
+		wantStatus int
+	}{
+		{method: http.MethodGet, path: "/bookings/ABC234", wantStatus: http.StatusOK},
+		{method: http.MethodGet, path: "/bookings/NOPE00", wantStatus: http.StatusNotFound},
+		{method: http.MethodDelete, path: "/bookings/ABC234", wantStatus: http.StatusOK},
+		{method: http.MethodDelete, path: "/bookings/DONE00", wantStatus: http.StatusConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			newRouter().ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, nil))
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+		})
+	}
+
+	mockService.AssertExpectations(t)
+}
+
+func TestSelectSeats(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		result     service.Booking
+		err        error
+		wantStatus int
+	}{
+		{
+			name:       "success",
+			body:       `{"seats":[{"passenger_index":0,"seat_number":"12A"}]}`,
+			result:     service.Booking{Reference: "ABC234", Seats: []service.SeatAssignment{{SeatNumber: "12A"}}},
+			wantStatus: http.StatusOK,
+		},
+		{name: "missing seats", body: `{"seats":[]}`, wantStatus: http.StatusBadRequest},
+		{name: "seat taken", body: `{"seats":[{"passenger_index":0,"seat_number":"12A"}]}`, err: service.ErrSeatUnavailable, wantStatus: http.StatusConflict},
+		{name: "no seat map", body: `{"seats":[{"passenger_index":0,"seat_number":"12A"}]}`, err: service.ErrSeatMapUnavailable, wantStatus: http.StatusNotFound},
+		{name: "unknown passenger", body: `{"seats":[{"passenger_index":3,"seat_number":"12A"}]}`, err: service.ErrInvalidSeatSelection, wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockFlightBooking{}
+			booking.Init(mockService)
+			mockService.On("SelectSeats", mock.Anything, "ABC234", mock.Anything).Return(tt.result, tt.err).Maybe()
+
+			req := httptest.NewRequest(http.MethodPost, "/bookings/ABC234/seats", bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			newRouter().ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+		})
+	}
+}
+
+func TestSelectAncillaries(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		err        error
+		wantStatus int
+	}{
+		{name: "success", body: `{"ancillaries":[{"passenger_index":0,"code":"BAG20"}]}`, wantStatus: http.StatusOK},
+		{name: "missing code", body: `{"ancillaries":[{"passenger_index":0}]}`, wantStatus: http.StatusBadRequest},
+		{name: "not offered", body: `{"ancillaries":[{"passenger_index":0,"code":"CAVIAR"}]}`, err: service.ErrInvalidAncillarySelection, wantStatus: http.StatusBadRequest},
+		{name: "not held", body: `{"ancillaries":[{"passenger_index":0,"code":"BAG20"}]}`, err: service.ErrBookingNotHeld, wantStatus: http.StatusConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockFlightBooking{}
+			booking.Init(mockService)
+			mockService.On("SelectAncillaries", mock.Anything, "ABC234", mock.Anything).Return(service.Booking{Reference: "ABC234"}, tt.err).Maybe()
+
+			req := httptest.NewRequest(http.MethodPost, "/bookings/ABC234/ancillaries", bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			newRouter().ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+		})
+	}
+}
+
+func TestRequestCancellation(t *testing.T) {
+	mockService := &MockFlightBooking{}
+	booking.Init(mockService)
+
+	mockService.On("QuoteCancellation", mock.Anything, "ABC234").Return(service.CancellationQuote{QuoteID: "q1", Reference: "ABC234", RefundAmount: service.PriceInfo{Amount: 750000}}, nil)
+	mockService.On("QuoteCancellation", mock.Anything, "DONE00").Return(service.CancellationQuote{}, service.ErrBookingNotCancellable)
+	mockService.On("ConfirmCancellation", mock.Anything, "ABC234", "q1").Return(service.Booking{Reference: "ABC234", Status: service.BookingStatusRefunded}, nil)
+	mockService.On("ConfirmCancellation", mock.Anything, "ABC234", "stale").Return(service.Booking{}, service.ErrQuoteExpired)
+
+	tests := []struct {
+		name       string
+		ref        string
+		body       string
+		wantStatus int
+		wantField  string
+		wantValue  interface{}
+	}{
+		{name: "quote", ref: "ABC234", body: `{}`, wantStatus: http.StatusOK, wantField: "quote_id", wantValue: "q1"},
+		{name: "quote not cancellable", ref: "DONE00", body: `{}`, wantStatus: http.StatusConflict},
+		{name: "confirm", ref: "ABC234", body: `{"confirm":true,"quote_id":"q1"}`, wantStatus: http.StatusOK, wantField: "status", wantValue: service.BookingStatusRefunded},
+		{name: "confirm stale quote", ref: "ABC234", body: `{"confirm":true,"quote_id":"stale"}`, wantStatus: http.StatusConflict},
+		{name: "confirm without quote", ref: "ABC234", body: `{"confirm":true}`, wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/bookings/"+tt.ref+"/cancel", bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			newRouter().ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			if tt.wantField != "" {
+				var response map[string]interface{}
+				assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+				data := response["data"].(map[string]interface{})
+				assert.Equal(t, tt.wantValue, data[tt.wantField])
+			}
+		})
+	}
+
+	mockService.AssertExpectations(t)
+}
+
+func TestPay(t *testing.T) {
+	card := service.PaymentRequest{CardNumber: "4111111111111111", HolderName: "Budi Santoso", ExpiryMonth: 12, ExpiryYear: 2030, CVV: "123"}
+
+	tests := []struct {
+		name       string
+		ref        string
+		body       interface{}
+		result     service.Booking
+		err        error
+		wantStatus int
+	}{
+		{name: "captured", ref: "ABC234", body: card, result: service.Booking{Reference: "ABC234", Status: service.BookingStatusTicketed}, wantStatus: http.StatusOK},
+		{name: "challenge", ref: "ABC234", body: card, result: service.Booking{Reference: "ABC234", Status: service.BookingStatusHeld}, wantStatus: http.StatusAccepted},
+		{name: "declined", ref: "ABC234", body: card, err: service.ErrPaymentDeclined, wantStatus: http.StatusPaymentRequired},
+		{name: "not held", ref: "DONE00", body: card, err: service.ErrBookingNotHeld, wantStatus: http.StatusConflict},
+		{name: "invalid card", ref: "ABC234", body: service.PaymentRequest{CardNumber: "abc"}, wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockFlightBooking{}
+			booking.Init(mockService)
+			mockService.On("Pay", mock.Anything, tt.ref, mock.Anything).Return(tt.result, tt.err).Maybe()
+
+			jsonBody, err := json.Marshal(tt.body)
+			assert.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodPost, "/bookings/"+tt.ref+"/payment", bytes.NewBuffer(jsonBody))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			newRouter().ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+		})
+	}
+}
+
+func TestTicket(t *testing.T) {
+	mockService := &MockFlightBooking{}
+	booking.Init(mockService)
+
+	pdf := service.Document{ContentType: "application/pdf", Filename: "eticket-ABC234.pdf", Body: []byte("%PDF-1.4")}
+	html := service.Document{ContentType: "text/html; charset=utf-8", Filename: "eticket-ABC234.html", Body: []byte("<html></html>")}
+	mockService.On("TicketDocument", mock.Anything, "ABC234", service.TicketFormatPDF).Return(pdf, nil)
+	mockService.On("TicketDocument", mock.Anything, "ABC234", service.TicketFormatHTML).Return(html, nil)
+	mockService.On("TicketDocument", mock.Anything, "HELD00", service.TicketFormatPDF).Return(service.Document{}, service.ErrBookingNotTicketed)
+	mockService.On("TicketDocument", mock.Anything, "ABC234", "docx").Return(service.Document{}, service.ErrUnsupportedFormat)
+
+	tests := []struct {
+		name            string
+		path            string
+		accept          string
+		wantStatus      int
+		wantContentType string
+	}{
+		{name: "pdf by default", path: "/bookings/ABC234/ticket", wantStatus: http.StatusOK, wantContentType: "application/pdf"},
+		{name: "html by query", path: "/bookings/ABC234/ticket?format=html", wantStatus: http.StatusOK, wantContentType: "text/html; charset=utf-8"},
+		{name: "html by accept", path: "/bookings/ABC234/ticket", accept: "text/html,application/xhtml+xml", wantStatus: http.StatusOK, wantContentType: "text/html; charset=utf-8"},
+		{name: "not ticketed", path: "/bookings/HELD00/ticket", wantStatus: http.StatusConflict},
+		{name: "unsupported format", path: "/bookings/ABC234/ticket?format=docx", wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.accept != "" {
+				req.Header.Set("Accept", tt.accept)
+			}
+			w := httptest.NewRecorder()
+			newRouter().ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			if tt.wantContentType != "" {
+				assert.Equal(t, tt.wantContentType, w.Header().Get("Content-Type"))
+				assert.Contains(t, w.Header().Get("Content-Disposition"), "eticket-ABC234")
+			}
+		})
+	}
+
+	mockService.AssertExpectations(t)
+}
@@ -0,0 +1,126 @@
+package payment
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+
+	"github.com/elkoshar/bookcabin/api"
+	"github.com/elkoshar/bookcabin/pkg/helpers"
+	"github.com/elkoshar/bookcabin/pkg/response"
+	"github.com/elkoshar/bookcabin/service"
+	svcpayment "github.com/elkoshar/bookcabin/service/payment"
+	"github.com/go-chi/chi/v5"
+)
+
+const (
+	ErrWebhookMsg       = "Payment Webhook Failed. %+v"
+	ErrChallengeMsg     = "Payment Challenge Failed. %+v"
+	ErrParseValidateMsg = "Failed to Parse and Validate. err=%v"
+
+	// maxWebhookBody limits the notification body read before its signature is checked
+	maxWebhookBody = 64 << 10
+)
+
+var (
+	flightBooking api.FlightBooking
+	webhookSecret string
+	challenger    api.PaymentChallenger
+)
+
+// Init sets the booking service notifications are applied to and the secret they are signed
+// with. challenger may be nil when the gateway has no local 3DS challenge.
+func Init(service api.FlightBooking, secret string, paymentChallenger api.PaymentChallenger) {
+	flightBooking = service
+	webhookSecret = secret
+	challenger = paymentChallenger
+}
+
+// Webhook : HTTP Handler for asynchronous payment notifications
+// @Summary Payment Webhook
+// @Description Webhook receives payment notifications from the gateway. The raw body must be signed with HMAC-SHA256 using the webhook secret and sent as "sha256=<hex>" in the X-Payment-Signature header.
+// @Tags Payment
+// @Accept json
+// @Produce json
+// @Param X-Payment-Signature header string true "Body signature"
+// @Param body body service.PaymentEvent true "Request Body"
+// @Success 200 {object} response.Response "Success Response"
+// @Router /payments/webhook [POST]
+func Webhook(w http.ResponseWriter, r *http.Request) {
+
+	resp := response.Response{}
+	defer resp.Render(w, r)
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxWebhookBody))
+	if err != nil {
+		slog.WarnContext(r.Context(), fmt.Sprintf(ErrWebhookMsg, err))
+		resp.SetError(err, http.StatusBadRequest)
+		return
+	}
+
+	err = deliver(r, body, r.Header.Get(svcpayment.SignatureHeader))
+	if err != nil {
+		slog.WarnContext(r.Context(), fmt.Sprintf(ErrWebhookMsg, err))
+		resp.SetError(err)
+		return
+	}
+
+	resp.Code = http.StatusOK
+}
+
+// Challenge : HTTP Handler completing a 3DS challenge on the local fake gateway
+// @Summary Complete Payment Challenge
+// @Description Challenge approves or rejects a pending 3DS challenge and delivers the gateway's signed notification, only available with the fake gateway
+// @Tags Payment
+// @Accept json
+// @Produce json
+// @Param id path string true "Payment ID"
+// @Param body body service.PaymentChallengeRequest true "Request Body"
+// @Success 200 {object} response.Response "Success Response"
+// @Router /payments/{id}/challenge [POST]
+func Challenge(w http.ResponseWriter, r *http.Request) {
+
+	resp := response.Response{}
+	defer resp.Render(w, r)
+
+	if challenger == nil {
+		resp.SetError(service.ErrPaymentNotFound, http.StatusNotFound)
+		return
+	}
+
+	var req service.PaymentChallengeRequest
+	err := helpers.ParseBodyAndValidate(r, &req)
+	if err != nil {
+		slog.WarnContext(r.Context(), fmt.Sprintf(ErrParseValidateMsg, err))
+		resp.SetError(err, http.StatusBadRequest)
+		return
+	}
+
+	body, signature, err := challenger.ResolveChallenge(r.Context(), chi.URLParam(r, "id"), req.Approved)
+	if err == nil {
+		err = deliver(r, body, signature)
+	}
+	if err != nil {
+		slog.WarnContext(r.Context(), fmt.Sprintf(ErrChallengeMsg, err))
+		resp.SetError(err)
+		return
+	}
+
+	resp.Code = http.StatusOK
+}
+
+// deliver verifies a signed notification and applies it to its booking
+func deliver(r *http.Request, body []byte, signature string) error {
+	if err := svcpayment.Verify(webhookSecret, body, signature); err != nil {
+		return err
+	}
+
+	var event service.PaymentEvent
+	if err := json.Unmarshal(body, &event); err != nil {
+		return fmt.Errorf("%w: %v", service.ErrInvalidPaymentEvent, err)
+	}
+
+	return flightBooking.HandlePaymentEvent(r.Context(), event)
+}
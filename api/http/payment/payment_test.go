@@ -0,0 +1,108 @@
+package payment_test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/elkoshar/bookcabin/api"
+	"github.com/elkoshar/bookcabin/api/http/payment"
+	"github.com/elkoshar/bookcabin/service"
+	svcpayment "github.com/elkoshar/bookcabin/service/payment"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const secret = "test-secret"
+
+// MockFlightBooking implements the payment part of api.FlightBooking for testing
+type MockFlightBooking struct {
+	api.FlightBooking
+	mock.Mock
+}
+
+func (m *MockFlightBooking) HandlePaymentEvent(ctx context.Context, event service.PaymentEvent) error {
+	args := m.Called(ctx, event)
+	return args.Error(0)
+}
+
+func newRouter() http.Handler {
+	r := chi.NewRouter()
+	r.Post("/payments/webhook", payment.Webhook)
+	r.Post("/payments/{id}/challenge", payment.Challenge)
+	return r
+}
+
+func TestWebhook(t *testing.T) {
+	body := []byte(`{"id":"evt_1","type":"payment.authorized","payment_id":"pay_000001","reference":"ABC234"}`)
+
+	tests := []struct {
+		name       string
+		body       []byte
+		signature  string
+		err        error
+		wantCalled bool
+		wantStatus int
+	}{
+		{name: "valid signature", body: body, signature: svcpayment.Sign(secret, body), wantCalled: true, wantStatus: http.StatusOK},
+		{name: "wrong secret", body: body, signature: svcpayment.Sign("other", body), wantStatus: http.StatusUnauthorized},
+		{name: "missing signature", body: body, wantStatus: http.StatusUnauthorized},
+		{name: "invalid payload", body: []byte(`{`), signature: svcpayment.Sign(secret, []byte(`{`)), wantStatus: http.StatusBadRequest},
+		{name: "unknown payment", body: body, signature: svcpayment.Sign(secret, body), err: service.ErrPaymentNotFound, wantCalled: true, wantStatus: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockFlightBooking{}
+			payment.Init(mockService, secret, nil)
+			if tt.wantCalled {
+				mockService.On("HandlePaymentEvent", mock.Anything, mock.MatchedBy(func(event service.PaymentEvent) bool {
+					return event.PaymentID == "pay_000001" && event.Type == service.PaymentEventAuthorized
+				})).Return(tt.err)
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/payments/webhook", bytes.NewBuffer(tt.body))
+			req.Header.Set(svcpayment.SignatureHeader, tt.signature)
+			w := httptest.NewRecorder()
+			newRouter().ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestChallenge(t *testing.T) {
+	gateway := svcpayment.NewFake(secret)
+	pending, err := gateway.Authorize(context.Background(), service.AuthorizeRequest{
+		Reference: "ABC234",
+		Amount:    service.PriceInfo{Amount: 1000000, Currency: "IDR"},
+		Card:      service.PaymentRequest{CardNumber: svcpayment.CardChallenge},
+	})
+	require.NoError(t, err)
+
+	mockService := &MockFlightBooking{}
+	payment.Init(mockService, secret, gateway)
+	mockService.On("HandlePaymentEvent", mock.Anything, mock.MatchedBy(func(event service.PaymentEvent) bool {
+		return event.PaymentID == pending.ID && event.Reference == "ABC234"
+	})).Return(nil).Once()
+
+	w := httptest.NewRecorder()
+	newRouter().ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/payments/"+pending.ID+"/challenge", bytes.NewBufferString(`{"approved":true}`)))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = httptest.NewRecorder()
+	newRouter().ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/payments/"+pending.ID+"/challenge", bytes.NewBufferString(`{"approved":true}`)))
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	mockService.AssertExpectations(t)
+
+	payment.Init(mockService, secret, nil)
+	w = httptest.NewRecorder()
+	newRouter().ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/payments/"+pending.ID+"/challenge", bytes.NewBufferString(`{"approved":true}`)))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
@@ -13,10 +13,16 @@ import (
 
 	"github.com/elkoshar/bookcabin/api"
 	"github.com/elkoshar/bookcabin/api/http/aggregator"
+	"github.com/elkoshar/bookcabin/api/http/booking"
+	"github.com/elkoshar/bookcabin/api/http/payment"
+	"github.com/elkoshar/bookcabin/api/http/searchjob"
 	config "github.com/elkoshar/bookcabin/configs"
 	"github.com/elkoshar/bookcabin/pkg/helpers"
+	"github.com/elkoshar/bookcabin/pkg/i18n"
+	"github.com/elkoshar/bookcabin/pkg/idempotency"
 	"github.com/elkoshar/bookcabin/pkg/logger"
 	"github.com/elkoshar/bookcabin/pkg/panics"
+	svcpayment "github.com/elkoshar/bookcabin/service/payment"
 )
 
 func root(w http.ResponseWriter, r *http.Request) {
@@ -30,11 +36,12 @@ func root(w http.ResponseWriter, r *http.Request) {
 	w.Write(data)
 }
 
-func handler(checker api.HealthChecker, cfg *config.Config) http.Handler {
+func handler(checker api.HealthChecker, cfg *config.Config, store idempotency.Store) http.Handler {
 	r := chi.NewRouter()
 
 	r.Use(middleware.Heartbeat("/ping"))
 	r.Use(middleware.RequestID)
+	r.Use(i18n.Middleware)
 	r.Use(middleware.RealIP)
 	r.Use(panics.HTTPRecoveryMiddleware)
 	r.Use(middleware.Timeout(cfg.HttpInboundTimeout))
@@ -59,7 +66,8 @@ func handler(checker api.HealthChecker, cfg *config.Config) http.Handler {
 		cors := cors.New(cors.Options{
 			AllowedOrigins: []string{"*"},
 			AllowedMethods: []string{"GET", "POST", "PUT", "DELETE", "PATCH"},
-			AllowedHeaders: []string{"Accept", "Authorization", "Content-Type"},
+			AllowedHeaders: []string{"Accept", "Authorization", "Accept-Language", "Content-Type", api.IdempotencyKeyHeader, svcpayment.SignatureHeader, "If-None-Match"},
+			ExposedHeaders: []string{"ETag", "Last-Modified", "Content-Language", "Content-Disposition"},
 		})
 		r.Use(cors.Handler)
 
@@ -69,13 +77,63 @@ func handler(checker api.HealthChecker, cfg *config.Config) http.Handler {
 
 		r.With(api.InterceptorRequest()).Route("/bookcabin", func(r chi.Router) {
 			r.Use(api.NewMetricMiddleware())
+			r.Use(api.Idempotency(store, cfg.IdempotencyTTL))
 
-			r.Route("/flight/search", func(r chi.Router) {
-				r.Post("/", aggregator.Search)
-			})
-
+			// unversioned paths are v1, kept for the clients that predate versioning
+			v1(r)
+			r.Route("/v1", v1)
+			r.Route("/v2", v2)
 		})
 	})
 
 	return r
 }
+
+// v1 registers the routes of the first API version. Its response schema is frozen; changes to the shape
+// of a response go into a new version instead.
+func v1(r chi.Router) {
+	r.Route("/flight/search", func(r chi.Router) {
+		r.Post("/", aggregator.Search)
+		r.Get("/", aggregator.SearchQuery)
+		r.Get("/stream", aggregator.SearchStream)
+		r.Post("/async", searchjob.Submit)
+		r.Get("/{id}", searchjob.Get)
+		r.Delete("/{id}", searchjob.Cancel)
+	})
+	r.Post("/flight/revalidate", aggregator.Revalidate)
+	r.Get("/flight/{id}", aggregator.GetFlight)
+	r.Get("/flight/{id}/seatmap", aggregator.SeatMap)
+	r.Get("/flight/{id}/ancillaries", aggregator.Ancillaries)
+	r.Get("/flight/{id}/fare-rules", aggregator.FareRules)
+
+	r.Route("/bookings", func(r chi.Router) {
+		r.Post("/", booking.Create)
+		r.Get("/{ref}", booking.Get)
+		r.Delete("/{ref}", booking.Cancel)
+		r.Post("/{ref}/cancel", booking.RequestCancellation)
+		r.Post("/{ref}/payment", booking.Pay)
+		r.Get("/{ref}/ticket", booking.Ticket)
+		r.Post("/{ref}/seats", booking.SelectSeats)
+		r.Post("/{ref}/ancillaries", booking.SelectAncillaries)
+	})
+
+	r.Route("/payments", func(r chi.Router) {
+		r.Post("/webhook", payment.Webhook)
+
+		// the fake gateway's 3DS challenge lets the caller approve any payment, so it is never
+		// mounted in production
+		if helpers.GetEnvString() != helpers.EnvProduction {
+			r.Post("/{id}/challenge", payment.Challenge)
+		}
+	})
+}
+
+// v2 registers the routes whose response schema changed in the second API version. They are served by the
+// same services as v1 and render their results through the DTOs of api/http/v2.
+func v2(r chi.Router) {
+	r.Route("/flight/search", func(r chi.Router) {
+		r.Post("/", aggregator.SearchV2)
+		r.Get("/", aggregator.SearchQueryV2)
+	})
+	r.Get("/flight/{id}", aggregator.GetFlightV2)
+}
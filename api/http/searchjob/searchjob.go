@@ -0,0 +1,119 @@
+package searchjob
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/elkoshar/bookcabin/api"
+	"github.com/elkoshar/bookcabin/pkg/helpers"
+	"github.com/elkoshar/bookcabin/pkg/i18n"
+	"github.com/elkoshar/bookcabin/pkg/response"
+	"github.com/elkoshar/bookcabin/service"
+	"github.com/go-chi/chi/v5"
+)
+
+const (
+	ErrCreateDataMsg    = "Create Data Failed. %+v"
+	ErrGetDataMsg       = "Get Data Failed. %+v"
+	ErrDeleteDataMsg    = "Delete Data Failed. %+v"
+	ErrParseValidateMsg = "Failed to Parse and Validate. err=%v"
+)
+
+var (
+	searchJobs api.SearchJobRunner
+)
+
+func Init(runner api.SearchJobRunner) {
+	searchJobs = runner
+}
+
+// Submit : HTTP Handler for starting an asynchronous flight search
+// @Summary Start Async Search
+// @Description Submit queues a search and returns its ID immediately, poll GET /flight/search/{id} for its progress
+// @Tags Flight
+// @Accept json
+// @Produce json
+// @Param Accept-Language header string true "accept language" default(id)
+// @Param body body service.SearchCriteria true "Request Body"
+// @Success 202 {object} response.Response{data=service.SearchJob} "Success Response"
+// @Router /flight/search/async [POST]
+func Submit(w http.ResponseWriter, r *http.Request) {
+
+	resp := response.Response{}
+	defer resp.Render(w, r)
+
+	var (
+		err    error
+		req    service.SearchCriteria
+		result service.SearchJob
+	)
+
+	err = helpers.ParseBodyAndValidate(r, &req)
+	if err != nil {
+		slog.WarnContext(r.Context(), fmt.Sprintf(ErrParseValidateMsg, err))
+		resp.SetError(err, http.StatusBadRequest)
+		return
+	}
+
+	result, err = searchJobs.Submit(r.Context(), req)
+	if err != nil {
+		slog.WarnContext(r.Context(), fmt.Sprintf(ErrCreateDataMsg, err))
+		resp.SetError(err)
+		return
+	}
+
+	w.Header().Set("Location", fmt.Sprintf("/bookcabin/flight/search/%s", result.ID))
+	resp.Data = result.Localize(i18n.FromContext(r.Context()))
+	resp.Code = http.StatusAccepted
+}
+
+// Get : HTTP Handler for polling an asynchronous flight search
+// @Summary Get Async Search
+// @Description Get returns the status of a search job, the results gathered so far and the progress of each provider
+// @Tags Flight
+// @Produce json
+// @Param Accept-Language header string true "accept language" default(id)
+// @Param id path string true "Search Job ID"
+// @Success 200 {object} response.Response{data=service.SearchJob} "Success Response"
+// @Router /flight/search/{id} [GET]
+func Get(w http.ResponseWriter, r *http.Request) {
+
+	resp := response.Response{}
+	defer resp.Render(w, r)
+
+	result, err := searchJobs.Get(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		slog.WarnContext(r.Context(), fmt.Sprintf(ErrGetDataMsg, err))
+		resp.SetError(err)
+		return
+	}
+
+	resp.Data = result.Localize(i18n.FromContext(r.Context()))
+	resp.Code = http.StatusOK
+}
+
+// Cancel : HTTP Handler for cancelling an asynchronous flight search
+// @Summary Cancel Async Search
+// @Description Cancel stops a queued or running search job, the results gathered so far stay available until it expires
+// @Tags Flight
+// @Produce json
+// @Param Accept-Language header string true "accept language" default(id)
+// @Param id path string true "Search Job ID"
+// @Success 200 {object} response.Response{data=service.SearchJob} "Success Response"
+// @Router /flight/search/{id} [DELETE]
+func Cancel(w http.ResponseWriter, r *http.Request) {
+
+	resp := response.Response{}
+	defer resp.Render(w, r)
+
+	result, err := searchJobs.Cancel(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		slog.WarnContext(r.Context(), fmt.Sprintf(ErrDeleteDataMsg, err))
+		resp.SetError(err)
+		return
+	}
+
+	resp.Data = result.Localize(i18n.FromContext(r.Context()))
+	resp.Code = http.StatusOK
+}
@@ -0,0 +1,130 @@
+package searchjob_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/elkoshar/bookcabin/api"
+	"github.com/elkoshar/bookcabin/api/http/searchjob"
+	"github.com/elkoshar/bookcabin/service"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockSearchJobRunner implements api.SearchJobRunner for testing
+type MockSearchJobRunner struct {
+	mock.Mock
+}
+
+var _ api.SearchJobRunner = (*MockSearchJobRunner)(nil)
+
+func (m *MockSearchJobRunner) Submit(ctx context.Context, criteria service.SearchCriteria) (service.SearchJob, error) {
+	args := m.Called(ctx, criteria)
+	return args.Get(0).(service.SearchJob), args.Error(1)
+}
+
+func (m *MockSearchJobRunner) Get(ctx context.Context, id string) (service.SearchJob, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(service.SearchJob), args.Error(1)
+}
+
+func (m *MockSearchJobRunner) Cancel(ctx context.Context, id string) (service.SearchJob, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(service.SearchJob), args.Error(1)
+}
+
+func newRouter() http.Handler {
+	r := chi.NewRouter()
+	r.Post("/flight/search/async", searchjob.Submit)
+	r.Get("/flight/search/{id}", searchjob.Get)
+	r.Delete("/flight/search/{id}", searchjob.Cancel)
+	return r
+}
+
+func TestSubmit(t *testing.T) {
+	criteria := service.SearchCriteria{Origin: "CGK", Destination: "DPS", DepartureDate: "2030-12-15", Passengers: 1, CabinClass: "economy"}
+
+	tests := []struct {
+		name       string
+		body       string
+		err        error
+		wantStatus int
+	}{
+		{name: "accepted", body: `{"Origin":"CGK","Destination":"DPS","DepartureDate":"2030-12-15","Passengers":1,"CabinClass":"economy"}`, wantStatus: http.StatusAccepted},
+		{name: "invalid body", body: `{`, wantStatus: http.StatusBadRequest},
+		{name: "queue full", body: `{"Origin":"CGK","Destination":"DPS","DepartureDate":"2030-12-15","Passengers":1,"CabinClass":"economy"}`, err: service.ErrSearchQueueFull, wantStatus: http.StatusServiceUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := &MockSearchJobRunner{}
+			searchjob.Init(mockService)
+			mockService.On("Submit", mock.Anything, criteria).Return(service.SearchJob{ID: "job-1", Status: service.SearchJobQueued}, tt.err).Maybe()
+
+			req := httptest.NewRequest(http.MethodPost, "/flight/search/async", bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			newRouter().ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			if tt.wantStatus == http.StatusAccepted {
+				assert.Equal(t, "/bookcabin/flight/search/job-1", w.Header().Get("Location"))
+
+				var response map[string]interface{}
+				assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+				data := response["data"].(map[string]interface{})
+				assert.Equal(t, "job-1", data["id"])
+				assert.Equal(t, service.SearchJobQueued, data["status"])
+			}
+		})
+	}
+}
+
+func TestGetAndCancel(t *testing.T) {
+	mockService := &MockSearchJobRunner{}
+	searchjob.Init(mockService)
+
+	partial := service.SearchJob{
+		ID:        "job-1",
+		Status:    service.SearchJobPartial,
+		Providers: []service.ProviderProgress{{Provider: "Lion Air", Status: service.ProviderProgressComplete, Results: 1}},
+		Flights:   []service.UnifiedFlight{{ID: "JT650"}},
+	}
+	mockService.On("Get", mock.Anything, "job-1").Return(partial, nil)
+	mockService.On("Get", mock.Anything, "gone").Return(service.SearchJob{}, service.ErrSearchJobNotFound)
+	mockService.On("Cancel", mock.Anything, "job-1").Return(service.SearchJob{ID: "job-1", Status: service.SearchJobCancelled}, nil)
+	mockService.On("Cancel", mock.Anything, "done").Return(service.SearchJob{ID: "done", Status: service.SearchJobComplete}, service.ErrSearchJobFinished)
+
+	tests := []struct {
+		method     string
+		id         string
+		wantStatus int
+		wantJob    string
+	}{
+		{method: http.MethodGet, id: "job-1", wantStatus: http.StatusOK, wantJob: service.SearchJobPartial},
+		{method: http.MethodGet, id: "gone", wantStatus: http.StatusNotFound},
+		{method: http.MethodDelete, id: "job-1", wantStatus: http.StatusOK, wantJob: service.SearchJobCancelled},
+		{method: http.MethodDelete, id: "done", wantStatus: http.StatusConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.id, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			newRouter().ServeHTTP(w, httptest.NewRequest(tt.method, "/flight/search/"+tt.id, nil))
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			if tt.wantJob != "" {
+				var response map[string]interface{}
+				assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+				data := response["data"].(map[string]interface{})
+				assert.Equal(t, tt.wantJob, data["status"])
+			}
+		})
+	}
+	mockService.AssertExpectations(t)
+}
@@ -8,7 +8,12 @@ import (
 
 	"github.com/elkoshar/bookcabin/api"
 	"github.com/elkoshar/bookcabin/api/http/aggregator"
+	"github.com/elkoshar/bookcabin/api/http/booking"
+	"github.com/elkoshar/bookcabin/api/http/payment"
+	"github.com/elkoshar/bookcabin/api/http/searchjob"
 	config "github.com/elkoshar/bookcabin/configs"
+	"github.com/elkoshar/bookcabin/pkg/idempotency"
+	"github.com/elkoshar/bookcabin/pkg/response"
 )
 
 type Server struct {
@@ -16,18 +21,29 @@ type Server struct {
 	Cfg         *config.Config
 	HealthCheck api.HealthChecker
 	Aggregator  api.FlightAggregator
+	Booking     api.FlightBooking
+	SearchJobs  api.SearchJobRunner
+	Idempotency idempotency.Store
+
+	// Challenger completes 3DS challenges locally when the payment gateway supports it
+	Challenger api.PaymentChallenger
 }
 
 var ()
 
 func (s *Server) Serve(port string) error {
 
-	aggregator.Init(s.Aggregator)
+	response.ProblemJSON = s.Cfg.ErrorProblemJSON
+
+	aggregator.Init(s.Aggregator, s.Cfg.SearchCacheMaxAge)
+	booking.Init(s.Booking)
+	payment.Init(s.Booking, s.Cfg.PaymentWebhookSecret, s.Challenger)
+	searchjob.Init(s.SearchJobs)
 
 	s.server = &http.Server{
 		ReadTimeout:  s.Cfg.HttpReadTimeout * time.Second,
 		WriteTimeout: s.Cfg.HttpWriteTimeout * time.Second,
-		Handler:      handler(s.HealthCheck, s.Cfg),
+		Handler:      handler(s.HealthCheck, s.Cfg, s.Idempotency),
 	}
 
 	lis, err := net.Listen("tcp", ":"+port)
@@ -0,0 +1,156 @@
+package v2
+
+import (
+	"time"
+
+	"github.com/elkoshar/bookcabin/service"
+)
+
+// dateTimeLayouts are the datetime formats providers are known to send
+var dateTimeLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05-0700",
+	"2006-01-02T15:04-07:00",
+	"2006-01-02 15:04:05-07:00",
+}
+
+// FromSearchResponse maps a search result to the v2 schema
+func FromSearchResponse(r service.SearchResponse) SearchResponse {
+	legs := make([][]Flight, len(r.MultiCityFlights))
+	for i, leg := range r.MultiCityFlights {
+		legs[i] = FromFlights(leg)
+	}
+
+	return SearchResponse{
+		SearchID:         r.SearchID,
+		Criteria:         FromCriteria(r.Criteria),
+		Metadata:         Metadata(r.Metadata),
+		Flights:          FromFlights(r.Flights),
+		ReturnFlights:    FromFlights(r.ReturnFlights),
+		MultiCityFlights: legs,
+	}
+}
+
+// FromCriteria maps service search criteria back to the request they were read from
+func FromCriteria(c service.SearchCriteria) SearchRequest {
+	req := SearchRequest{
+		Origin:           c.Origin,
+		Destination:      c.Destination,
+		DepartureDate:    c.DepartureDate,
+		ReturnDate:       c.ReturnDate,
+		Passengers:       c.Passengers,
+		CabinClass:       c.CabinClass,
+		TripType:         c.TripType,
+		ExcludeDominated: c.ExcludeDominated,
+		RefundableOnly:   c.RefundableOnly,
+		NearbyRadiusKm:   c.NearbyRadiusKm,
+		Currency:         c.Currency,
+	}
+	for _, seg := range c.Segments {
+		req.Segments = append(req.Segments, RouteSegment(seg))
+	}
+	return req
+}
+
+// FromFlights maps a result list, nil becomes an empty list
+func FromFlights(flights []service.UnifiedFlight) []Flight {
+	mapped := make([]Flight, len(flights))
+	for i, f := range flights {
+		mapped[i] = FromFlight(f)
+	}
+	return mapped
+}
+
+// FromFlight maps a single flight offer to the v2 schema
+func FromFlight(f service.UnifiedFlight) Flight {
+	flight := Flight{
+		ID:                    f.ID,
+		Provider:              f.Provider,
+		Airline:               Airline(f.Airline),
+		FlightNumber:          f.FlightNumber,
+		OperatingCarrier:      f.OperatingCarrier,
+		OperatingFlightNumber: f.OperatingFlightNumber,
+		Departure:             fromLocation(f.Departure),
+		Arrival:               fromLocation(f.Arrival),
+		Duration:              Duration(f.Duration),
+		Stops:                 f.Stops,
+		Segments:              make([]Segment, len(f.Segments)),
+		Price:                 Price(f.Price),
+		AvailableSeats:        f.AvailableSeats,
+		CabinClass:            f.CabinClass,
+		Aircraft:              f.Aircraft,
+		Amenities:             nonNil(f.Amenities),
+		MealIncluded:          f.MealIncluded,
+		Labels:                nonNil(f.Labels),
+		ParetoOptimal:         f.ParetoOptimal,
+		AlternativeOffers:     make([]AlternativeOffer, len(f.AlternativeOffers)),
+		Score:                 f.Score,
+	}
+
+	for i, seg := range f.Segments {
+		flight.Segments[i] = Segment{
+			FlightNumber: seg.FlightNumber,
+			Departure:    fromLocation(seg.Departure),
+			Arrival:      fromLocation(seg.Arrival),
+			Duration:     Duration(seg.Duration),
+		}
+	}
+	for i, offer := range f.AlternativeOffers {
+		flight.AlternativeOffers[i] = AlternativeOffer{
+			ID:           offer.ID,
+			Provider:     offer.Provider,
+			FlightNumber: offer.FlightNumber,
+			Price:        Price(offer.Price),
+		}
+	}
+
+	if f.Baggage != nil {
+		baggage := Baggage(*f.Baggage)
+		flight.Baggage = &baggage
+	}
+	if f.FareRules != nil {
+		flight.FareRules = &FareRules{
+			Family:          f.FareRules.Family,
+			Refundable:      f.FareRules.Refundable,
+			Changeable:      f.FareRules.Changeable,
+			ChangeFee:       Price(f.FareRules.ChangeFee),
+			CancellationFee: Price(f.FareRules.CancellationFee),
+		}
+	}
+	if f.AirportMatch != nil {
+		match := AirportMatch(*f.AirportMatch)
+		flight.AirportMatch = &match
+	}
+	return flight
+}
+
+func fromLocation(l service.LocationInfo) Location {
+	return Location{
+		Airport:   l.Airport,
+		City:      l.City,
+		Terminal:  l.Terminal,
+		DateTime:  normalizeDateTime(l.DateTime, l.Timestamp),
+		Timestamp: l.Timestamp,
+	}
+}
+
+// normalizeDateTime rewrites a provider datetime as RFC 3339, keeping the UTC offset it was sent with.
+// A datetime in an unknown format is rebuilt in UTC from its timestamp.
+func normalizeDateTime(raw string, timestamp int64) string {
+	for _, layout := range dateTimeLayouts {
+		if t, err := time.Parse(layout, raw); err == nil {
+			return t.Format(time.RFC3339)
+		}
+	}
+	if timestamp == 0 {
+		return raw
+	}
+	return time.Unix(timestamp, 0).UTC().Format(time.RFC3339)
+}
+
+func nonNil(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
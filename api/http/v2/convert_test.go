@@ -0,0 +1,77 @@
+package v2_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	v2 "github.com/elkoshar/bookcabin/api/http/v2"
+	"github.com/elkoshar/bookcabin/service"
+)
+
+func TestFromFlight_DateTime(t *testing.T) {
+	tests := []struct {
+		name     string
+		location service.LocationInfo
+		want     string
+	}{
+		{name: "rfc 3339", location: service.LocationInfo{DateTime: "2025-12-15T06:00:00+07:00"}, want: "2025-12-15T06:00:00+07:00"},
+		{name: "offset without colon", location: service.LocationInfo{DateTime: "2025-12-15T07:15:00+0700"}, want: "2025-12-15T07:15:00+07:00"},
+		{name: "without seconds", location: service.LocationInfo{DateTime: "2025-12-15T08:50+08:00"}, want: "2025-12-15T08:50:00+08:00"},
+		{name: "utc", location: service.LocationInfo{DateTime: "2025-12-14T23:00:00Z"}, want: "2025-12-14T23:00:00Z"},
+		{name: "unknown format", location: service.LocationInfo{DateTime: "15/12/2025 06:00", Timestamp: 1765753200}, want: "2025-12-14T23:00:00Z"},
+		{name: "unknown format without timestamp", location: service.LocationInfo{DateTime: "15/12/2025 06:00"}, want: "15/12/2025 06:00"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			flight := v2.FromFlight(service.UnifiedFlight{Departure: tt.location})
+			assert.Equal(t, tt.want, flight.Departure.DateTime)
+		})
+	}
+}
+
+func TestFromSearchResponse(t *testing.T) {
+	resp := service.SearchResponse{
+		SearchID: "search-1",
+		Criteria: service.SearchCriteria{
+			Passengers: 1,
+			CabinClass: "economy",
+			Segments:   []service.RouteSegment{{Origin: "CGK", Destination: "DPS", DepartureDate: "2025-12-15"}},
+		},
+		Metadata: service.Metadata{TotalResults: 1},
+		Flights: []service.UnifiedFlight{{
+			ID:        "GA400",
+			Price:     service.PriceInfo{Amount: 1250000, Currency: "IDR"},
+			Baggage:   &service.BaggageInfo{CheckedKg: 20},
+			FareRules: &service.FareRulesSummary{Family: "lite", ChangeFee: service.PriceInfo{Amount: 150000, Currency: "IDR"}},
+			Segments:  []service.FlightSegment{{FlightNumber: "GA400"}},
+			Score:     8.1,
+		}},
+	}
+
+	got := v2.FromSearchResponse(resp)
+
+	assert.Equal(t, "search-1", got.SearchID)
+	assert.Equal(t, []v2.RouteSegment{{Origin: "CGK", Destination: "DPS", DepartureDate: "2025-12-15"}}, got.Criteria.Segments)
+	assert.Equal(t, resp.Criteria, got.Criteria.Criteria())
+	assert.Equal(t, 8.1, got.Flights[0].Score)
+	assert.Equal(t, 20, got.Flights[0].Baggage.CheckedKg)
+	assert.Equal(t, 150000.0, got.Flights[0].FareRules.ChangeFee.Amount)
+	assert.Len(t, got.Flights[0].Segments, 1)
+
+	data, err := json.Marshal(got)
+	assert.NoError(t, err)
+
+	var body map[string]interface{}
+	assert.NoError(t, json.Unmarshal(data, &body))
+	assert.Equal(t, []interface{}{}, body["return_flights"])
+	assert.Equal(t, []interface{}{}, body["multi_city_flights"])
+
+	flight := body["flights"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, []interface{}{}, flight["amenities"])
+	assert.Equal(t, []interface{}{}, flight["labels"])
+	assert.Equal(t, []interface{}{}, flight["alternative_offers"])
+	assert.Equal(t, 8.1, flight["score"])
+}
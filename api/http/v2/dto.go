@@ -0,0 +1,165 @@
+// Package v2 is the response schema of the /v2 HTTP API. Its types are mapped from the service types
+// instead of rendering them directly, so the service can change without changing what clients receive.
+// Unlike v1, lists are always arrays, the ranking score is exposed and every datetime is RFC 3339 in the
+// local time of its airport.
+package v2
+
+import "github.com/elkoshar/bookcabin/service"
+
+// SearchRequest is the body of a v2 search, with snake_case fields throughout
+type SearchRequest struct {
+	Origin           string         `json:"origin"`
+	Destination      string         `json:"destination"`
+	DepartureDate    string         `json:"departure_date"`
+	ReturnDate       string         `json:"return_date,omitempty"`
+	Passengers       int            `json:"passengers"`
+	CabinClass       string         `json:"cabin_class"`
+	TripType         string         `json:"trip_type,omitempty"`
+	Segments         []RouteSegment `json:"segments,omitempty"`
+	ExcludeDominated bool           `json:"exclude_dominated,omitempty"`
+	RefundableOnly   bool           `json:"refundable_only,omitempty"`
+	NearbyRadiusKm   float64        `json:"nearby_radius_km,omitempty"`
+	Currency         string         `json:"currency,omitempty"`
+}
+
+type RouteSegment struct {
+	Origin        string `json:"origin"`
+	Destination   string `json:"destination"`
+	DepartureDate string `json:"departure_date"`
+}
+
+type SearchResponse struct {
+	SearchID         string        `json:"search_id"`
+	Criteria         SearchRequest `json:"search_criteria"`
+	Metadata         Metadata      `json:"metadata"`
+	Flights          []Flight      `json:"flights"`
+	ReturnFlights    []Flight      `json:"return_flights"`
+	MultiCityFlights [][]Flight    `json:"multi_city_flights"`
+}
+
+type Metadata struct {
+	TotalResults       int   `json:"total_results"`
+	ProvidersQueried   int   `json:"providers_queried"`
+	ProvidersSucceeded int   `json:"providers_succeeded"`
+	ProvidersFailed    int   `json:"providers_failed"`
+	DominatedRemoved   int   `json:"dominated_removed"`
+	DuplicatesMerged   int   `json:"duplicates_merged"`
+	SearchTimeMs       int64 `json:"search_time_ms"`
+}
+
+type Flight struct {
+	ID                    string             `json:"id"`
+	Provider              string             `json:"provider"`
+	Airline               Airline            `json:"airline"`
+	FlightNumber          string             `json:"flight_number"`
+	OperatingCarrier      string             `json:"operating_carrier,omitempty"`
+	OperatingFlightNumber string             `json:"operating_flight_number,omitempty"`
+	Departure             Location           `json:"departure"`
+	Arrival               Location           `json:"arrival"`
+	Duration              Duration           `json:"duration"`
+	Stops                 int                `json:"stops"`
+	Segments              []Segment          `json:"segments"`
+	Price                 Price              `json:"price"`
+	AvailableSeats        int                `json:"available_seats"`
+	CabinClass            string             `json:"cabin_class"`
+	Aircraft              string             `json:"aircraft,omitempty"`
+	Amenities             []string           `json:"amenities"`
+	MealIncluded          bool               `json:"meal_included"`
+	Baggage               *Baggage           `json:"baggage,omitempty"`
+	FareRules             *FareRules         `json:"fare_rules,omitempty"`
+	AirportMatch          *AirportMatch      `json:"airport_match,omitempty"`
+	Labels                []string           `json:"labels"`
+	ParetoOptimal         bool               `json:"pareto_optimal"`
+	AlternativeOffers     []AlternativeOffer `json:"alternative_offers"`
+
+	// Score is the ranking score of the flight within its search, lower is better. It is zero for
+	// flights that were not ranked, such as a single offer looked up by ID.
+	Score float64 `json:"score"`
+}
+
+type Airline struct {
+	Name string `json:"name"`
+	Code string `json:"code"`
+}
+
+type Location struct {
+	Airport   string `json:"airport"`
+	City      string `json:"city"`
+	Terminal  string `json:"terminal,omitempty"`
+	DateTime  string `json:"datetime"`
+	Timestamp int64  `json:"timestamp"`
+}
+
+type Duration struct {
+	TotalMinutes int    `json:"total_minutes"`
+	Formatted    string `json:"formatted"`
+}
+
+type Price struct {
+	Amount           float64 `json:"amount"`
+	Currency         string  `json:"currency"`
+	Formatted        string  `json:"formatted"`
+	OriginalAmount   float64 `json:"original_amount,omitempty"`
+	OriginalCurrency string  `json:"original_currency,omitempty"`
+	ExchangeRate     float64 `json:"exchange_rate,omitempty"`
+}
+
+type Segment struct {
+	FlightNumber string   `json:"flight_number"`
+	Departure    Location `json:"departure"`
+	Arrival      Location `json:"arrival"`
+	Duration     Duration `json:"duration"`
+}
+
+type AlternativeOffer struct {
+	ID           string `json:"id"`
+	Provider     string `json:"provider"`
+	FlightNumber string `json:"flight_number"`
+	Price        Price  `json:"price"`
+}
+
+type Baggage struct {
+	CabinPieces   int    `json:"cabin_pieces"`
+	CabinKg       int    `json:"cabin_kg"`
+	CheckedPieces int    `json:"checked_pieces"`
+	CheckedKg     int    `json:"checked_kg"`
+	Note          string `json:"note,omitempty"`
+}
+
+type FareRules struct {
+	Family          string `json:"family"`
+	Refundable      bool   `json:"refundable"`
+	Changeable      bool   `json:"changeable"`
+	ChangeFee       Price  `json:"change_fee"`
+	CancellationFee Price  `json:"cancellation_fee"`
+}
+
+type AirportMatch struct {
+	RequestedOrigin       string  `json:"requested_origin"`
+	RequestedDestination  string  `json:"requested_destination"`
+	Origin                string  `json:"origin"`
+	Destination           string  `json:"destination"`
+	OriginDistanceKm      float64 `json:"origin_distance_km"`
+	DestinationDistanceKm float64 `json:"destination_distance_km"`
+}
+
+// Criteria returns the service search criteria of the request
+func (r SearchRequest) Criteria() service.SearchCriteria {
+	criteria := service.SearchCriteria{
+		Origin:           r.Origin,
+		Destination:      r.Destination,
+		DepartureDate:    r.DepartureDate,
+		ReturnDate:       r.ReturnDate,
+		Passengers:       r.Passengers,
+		CabinClass:       r.CabinClass,
+		TripType:         r.TripType,
+		ExcludeDominated: r.ExcludeDominated,
+		RefundableOnly:   r.RefundableOnly,
+		NearbyRadiusKm:   r.NearbyRadiusKm,
+		Currency:         r.Currency,
+	}
+	for _, seg := range r.Segments {
+		criteria.Segments = append(criteria.Segments, service.RouteSegment(seg))
+	}
+	return criteria
+}
@@ -0,0 +1,132 @@
+package api
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/elkoshar/bookcabin/pkg/idempotency"
+	"github.com/elkoshar/bookcabin/pkg/response"
+)
+
+const (
+	IdempotencyKeyHeader      = "Idempotency-Key"
+	IdempotencyReplayedHeader = "Idempotent-Replayed"
+)
+
+var (
+	ErrIdempotencyKeyReused     = errors.New("idempotency key was already used with a different request")
+	ErrIdempotencyKeyInProgress = errors.New("a request with this idempotency key is still in progress")
+)
+
+// responseCapture records the response of a handler while still writing it to the client
+type responseCapture struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (c *responseCapture) WriteHeader(status int) {
+	c.status = status
+	c.ResponseWriter.WriteHeader(status)
+}
+
+func (c *responseCapture) Write(b []byte) (int, error) {
+	if c.status == 0 {
+		c.status = http.StatusOK
+	}
+	c.body.Write(b)
+	return c.ResponseWriter.Write(b)
+}
+
+// fingerprint identifies a request by method, path and body
+func fingerprint(r *http.Request, body []byte) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s %s\n", r.Method, r.URL.Path)
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Idempotency replays the first response of a POST request when it is retried with the same
+// Idempotency-Key header. Reusing a key with a different request returns 409. Server errors
+// are not stored so the client can retry them.
+func Idempotency(store idempotency.Store, ttl time.Duration) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get(IdempotencyKeyHeader)
+			if r.Method != http.MethodPost || key == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			body, err := io.ReadAll(r.Body)
+			if err != nil {
+				conflict(w, r, err, http.StatusBadRequest)
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+
+			fp := fingerprint(r, body)
+			record, reserved, err := store.Reserve(r.Context(), key, fp, ttl)
+			if err != nil {
+				slog.ErrorContext(r.Context(), fmt.Sprintf("idempotency store reserve failed: %v", err))
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if !reserved {
+				switch {
+				case record.Fingerprint != fp:
+					conflict(w, r, ErrIdempotencyKeyReused, http.StatusConflict)
+				case !record.Completed:
+					conflict(w, r, ErrIdempotencyKeyInProgress, http.StatusConflict)
+				default:
+					replay(w, record)
+				}
+				return
+			}
+
+			capture := &responseCapture{ResponseWriter: w}
+			defer func() {
+				if capture.status == 0 || capture.status >= http.StatusInternalServerError {
+					if err := store.Release(r.Context(), key); err != nil {
+						slog.ErrorContext(r.Context(), fmt.Sprintf("idempotency store release failed: %v", err))
+					}
+					return
+				}
+
+				record.StatusCode = capture.status
+				record.Header = capture.Header().Clone()
+				record.Body = capture.body.Bytes()
+				if err := store.Complete(r.Context(), key, record, ttl); err != nil {
+					slog.ErrorContext(r.Context(), fmt.Sprintf("idempotency store complete failed: %v", err))
+				}
+			}()
+
+			next.ServeHTTP(capture, r)
+		})
+	}
+}
+
+func replay(w http.ResponseWriter, record idempotency.Record) {
+	for k, values := range record.Header {
+		for _, v := range values {
+			w.Header().Add(k, v)
+		}
+	}
+	w.Header().Set(IdempotencyReplayedHeader, "true")
+	w.WriteHeader(record.StatusCode)
+	w.Write(record.Body)
+}
+
+func conflict(w http.ResponseWriter, r *http.Request, err error, code int) {
+	resp := response.Response{}
+	resp.SetError(err, code)
+	resp.Render(w, r)
+}
@@ -0,0 +1,87 @@
+package api_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/elkoshar/bookcabin/api"
+	"github.com/elkoshar/bookcabin/pkg/idempotency"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIdempotency(t *testing.T) {
+	newRouter := func(calls *int, status int) http.Handler {
+		r := chi.NewRouter()
+		r.Use(api.Idempotency(idempotency.NewMemoryStore(), time.Minute))
+		r.Post("/bookings", func(w http.ResponseWriter, r *http.Request) {
+			*calls++
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(status)
+			w.Write([]byte(`{"reference":"ABC123"}`))
+		})
+		return r
+	}
+
+	post := func(h http.Handler, key, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/bookings", strings.NewReader(body))
+		if key != "" {
+			req.Header.Set(api.IdempotencyKeyHeader, key)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+
+	t.Run("replays first response on retry", func(t *testing.T) {
+		calls := 0
+		h := newRouter(&calls, http.StatusCreated)
+
+		first := post(h, "key-1", `{"offer_id":"a"}`)
+		second := post(h, "key-1", `{"offer_id":"a"}`)
+
+		assert.Equal(t, 1, calls)
+		assert.Equal(t, http.StatusCreated, second.Code)
+		assert.Equal(t, first.Body.String(), second.Body.String())
+		assert.Equal(t, "application/json", second.Header().Get("Content-Type"))
+		assert.Equal(t, "true", second.Header().Get(api.IdempotencyReplayedHeader))
+		assert.Empty(t, first.Header().Get(api.IdempotencyReplayedHeader))
+	})
+
+	t.Run("rejects key reused with different body", func(t *testing.T) {
+		calls := 0
+		h := newRouter(&calls, http.StatusCreated)
+
+		post(h, "key-1", `{"offer_id":"a"}`)
+		rec := post(h, "key-1", `{"offer_id":"b"}`)
+
+		assert.Equal(t, 1, calls)
+		assert.Equal(t, http.StatusConflict, rec.Code)
+	})
+
+	t.Run("requests without key are not deduplicated", func(t *testing.T) {
+		calls := 0
+		h := newRouter(&calls, http.StatusCreated)
+
+		post(h, "", `{"offer_id":"a"}`)
+		post(h, "", `{"offer_id":"a"}`)
+
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("server errors are not stored", func(t *testing.T) {
+		calls := 0
+		h := newRouter(&calls, http.StatusInternalServerError)
+
+		post(h, "key-1", `{"offer_id":"a"}`)
+		rec := post(h, "key-1", `{"offer_id":"a"}`)
+
+		assert.Equal(t, 2, calls)
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+		assert.Empty(t, rec.Header().Get(api.IdempotencyReplayedHeader))
+	})
+}
@@ -2,6 +2,7 @@ package api
 
 import (
 	"context"
+	"time"
 
 	"github.com/elkoshar/bookcabin/service"
 )
@@ -13,4 +14,96 @@ type FlightProvider interface {
 
 type FlightAggregator interface {
 	SearchAll(ctx context.Context, criteria service.SearchCriteria) (service.SearchResponse, error)
+	SearchStream(ctx context.Context, criteria service.SearchCriteria) (<-chan service.SearchEvent, error)
+	GetFlight(ctx context.Context, id string) (service.UnifiedFlight, error)
+	Reprice(ctx context.Context, req service.RepriceRequest) (service.RepriceResult, error)
+	SeatMap(ctx context.Context, offerID string) (service.SeatMap, error)
+	Ancillaries(ctx context.Context, offerID string) (service.AncillaryCatalogue, error)
+	FareRules(ctx context.Context, offerID string) (service.FareRules, error)
+}
+
+// FXRateProvider supplies the exchange rates used to show prices in other currencies
+type FXRateProvider interface {
+	Rates(ctx context.Context) (service.FXRates, error)
+}
+
+// SearchJobRunner runs searches in the background so clients can poll their progress
+type SearchJobRunner interface {
+	Submit(ctx context.Context, criteria service.SearchCriteria) (service.SearchJob, error)
+	Get(ctx context.Context, id string) (service.SearchJob, error)
+	Cancel(ctx context.Context, id string) (service.SearchJob, error)
+}
+
+// BookingProvider is implemented by providers that can hold seats for a flight they sell
+type BookingProvider interface {
+	Name() string
+	CreateHold(ctx context.Context, req service.HoldRequest) (service.ProviderHold, error)
+	RetrieveHold(ctx context.Context, locator string) (service.ProviderHold, error)
+	CancelHold(ctx context.Context, locator string) error
+
+	// ConfirmHold turns an active hold into a sold booking whose seats no longer expire
+	ConfirmHold(ctx context.Context, locator string) error
+
+	// CancelConfirmed cancels a confirmed booking and gives its seats back
+	CancelConfirmed(ctx context.Context, locator string) error
+}
+
+// SeatMapProvider is implemented by providers that can show seat maps and reserve seats on a hold
+type SeatMapProvider interface {
+	SeatMap(ctx context.Context, offerID string) (service.SeatMap, error)
+	ReserveSeats(ctx context.Context, locator string, seatNumbers []string) ([]service.Seat, error)
+}
+
+type FlightBooking interface {
+	CreateBooking(ctx context.Context, req service.BookingRequest) (service.Booking, error)
+	GetBooking(ctx context.Context, reference string) (service.Booking, error)
+	CancelBooking(ctx context.Context, reference string) (service.Booking, error)
+	SelectSeats(ctx context.Context, reference string, req service.SeatSelectionRequest) (service.Booking, error)
+	SelectAncillaries(ctx context.Context, reference string, req service.AncillarySelectionRequest) (service.Booking, error)
+	QuoteCancellation(ctx context.Context, reference string) (service.CancellationQuote, error)
+	ConfirmCancellation(ctx context.Context, reference, quoteID string) (service.Booking, error)
+	Pay(ctx context.Context, reference string, req service.PaymentRequest) (service.Booking, error)
+	HandlePaymentEvent(ctx context.Context, event service.PaymentEvent) error
+	TicketDocument(ctx context.Context, reference, format string) (service.Document, error)
+}
+
+// PaymentGateway authorizes card payments and moves money once a booking is confirmed
+type PaymentGateway interface {
+	Authorize(ctx context.Context, req service.AuthorizeRequest) (service.Payment, error)
+	Capture(ctx context.Context, id string, amount float64) (service.Payment, error)
+	Void(ctx context.Context, id string) (service.Payment, error)
+	Refund(ctx context.Context, id string, amount float64) (service.Payment, error)
+}
+
+// PaymentChallenger is implemented by gateways that let a 3DS challenge be completed locally. It
+// returns the signed webhook notification of the outcome.
+type PaymentChallenger interface {
+	ResolveChallenge(ctx context.Context, id string, approved bool) (body []byte, signature string, err error)
+}
+
+// EventPublisher delivers booking status changes to interested parties
+type EventPublisher interface {
+	Publish(ctx context.Context, event service.BookingEvent) error
+}
+
+// Storage persists search sessions, result snapshots, bookings and price history
+type Storage interface {
+	SaveSearchSession(ctx context.Context, session service.SearchSession) error
+	GetSearchSession(ctx context.Context, id string) (service.SearchSession, error)
+	SaveSnapshot(ctx context.Context, snapshot service.SearchSnapshot) error
+	GetSnapshot(ctx context.Context, searchID string) (service.SearchSnapshot, error)
+
+	// PruneSearches removes the sessions and snapshots of searches made before before and returns how
+	// many searches it removed
+	PruneSearches(ctx context.Context, before time.Time) (int, error)
+
+	SaveBooking(ctx context.Context, booking service.Booking) error
+	GetBooking(ctx context.Context, reference string) (service.Booking, error)
+
+	// RecordPrices appends observations to the price history of their offers, which keeps only the most
+	// recent ones
+	RecordPrices(ctx context.Context, points ...service.PricePoint) error
+
+	// PriceHistory returns the observations of an offer, oldest first
+	PriceHistory(ctx context.Context, offerID string) ([]service.PricePoint, error)
 }
@@ -60,6 +60,11 @@ type statusRecorder struct {
 	Status int
 }
 
+// Unwrap lets http.ResponseController reach the underlying writer, so streamed responses can be flushed
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
 func GetPathName(r *http.Request) string {
 	path := r.URL.Path
 	if rctx := chi.RouteContext(r.Context()); rctx != nil {
@@ -82,6 +82,7 @@ func Get() *Config {
 
 func setDefault() {
 	viper.SetDefault("SERVER_PORT", "8080")
+	viper.SetDefault("SERVER_GRPC_PORT", "9090")
 	viper.SetDefault("ENV", "development")
 	viper.SetDefault("GLOBAL_TIMEOUT", 5*time.Second)
 	viper.SetDefault("HTTP_INBOUND_TIMEOUT", 60*time.Second)
@@ -91,6 +92,29 @@ func setDefault() {
 	viper.SetDefault("AIRASIA_PATH", "")
 	viper.SetDefault("BATIK_PATH", "")
 	viper.SetDefault("AGGREGATOR_TIMEOUT", 5*time.Second)
+	viper.SetDefault("SEARCH_CACHE_MAX_AGE", time.Minute)
+	viper.SetDefault("ERROR_PROBLEM_JSON", false)
+
+	viper.SetDefault("FX_RATES_PATH", "")
+	viper.SetDefault("FX_REFRESH_INTERVAL", time.Hour)
+
+	viper.SetDefault("SEARCH_JOB_WORKERS", 4)
+	viper.SetDefault("SEARCH_JOB_QUEUE_SIZE", 100)
+	viper.SetDefault("SEARCH_JOB_TTL", 10*time.Minute)
+
+	viper.SetDefault("BOOKING_HOLD_TTL", 15*time.Minute)
+	viper.SetDefault("BOOKING_PRICE_TOLERANCE", 0.02)
+
+	viper.SetDefault("IDEMPOTENCY_TTL", 24*time.Hour)
+
+	viper.SetDefault("STORAGE_DRIVER", "file")
+	viper.SetDefault("STORAGE_PATH", "data")
+	viper.SetDefault("SEARCH_RETENTION", 24*time.Hour)
+	viper.SetDefault("SEARCH_PRUNE_INTERVAL", time.Hour)
+
+	// no default, a secret published here would let anyone forge payment webhooks. Binding the key
+	// still lets viper unmarshal it from the environment.
+	viper.BindEnv("PAYMENT_WEBHOOK_SECRET")
 }
 
 func (c *Config) postprocess() error {
@@ -7,6 +7,7 @@ type (
 	Config struct {
 		AppVersion     string `mapstructure:"APP_VERSION"`
 		ServerHttpPort string `mapstructure:"SERVER_PORT"`
+		ServerGrpcPort string `mapstructure:"SERVER_GRPC_PORT"`
 		LogLevel       string `mapstructure:"LOG_LEVEL"`
 		LogFormat      string `mapstructure:"LOG_FORMAT"`
 
@@ -25,5 +26,28 @@ type (
 		BatikPath   string `mapstructure:"BATIK_PATH"`
 
 		AggregatorTimeout time.Duration `mapstructure:"AGGREGATOR_TIMEOUT"`
+		SearchCacheMaxAge time.Duration `mapstructure:"SEARCH_CACHE_MAX_AGE"`
+
+		ErrorProblemJSON bool `mapstructure:"ERROR_PROBLEM_JSON"`
+
+		FXRatesPath       string        `mapstructure:"FX_RATES_PATH"`
+		FXRefreshInterval time.Duration `mapstructure:"FX_REFRESH_INTERVAL"`
+
+		SearchJobWorkers   int           `mapstructure:"SEARCH_JOB_WORKERS"`
+		SearchJobQueueSize int           `mapstructure:"SEARCH_JOB_QUEUE_SIZE"`
+		SearchJobTTL       time.Duration `mapstructure:"SEARCH_JOB_TTL"`
+
+		BookingHoldTTL        time.Duration `mapstructure:"BOOKING_HOLD_TTL"`
+		BookingPriceTolerance float64       `mapstructure:"BOOKING_PRICE_TOLERANCE"`
+
+		IdempotencyTTL time.Duration `mapstructure:"IDEMPOTENCY_TTL"`
+
+		StorageDriver string `mapstructure:"STORAGE_DRIVER"`
+		StoragePath   string `mapstructure:"STORAGE_PATH"`
+
+		SearchRetention     time.Duration `mapstructure:"SEARCH_RETENTION"`
+		SearchPruneInterval time.Duration `mapstructure:"SEARCH_PRUNE_INTERVAL"`
+
+		PaymentWebhookSecret string `mapstructure:"PAYMENT_WEBHOOK_SECRET"`
 	}
 )
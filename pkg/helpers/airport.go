@@ -1,19 +1,53 @@
 package helpers
 
-import "strings"
+import (
+	"math"
+	"sort"
+	"strings"
+)
 
 type AirportInfo struct {
-	Code     string
-	City     string
-	Name     string
-	Timezone string
+	Code      string
+	City      string
+	Name      string
+	Timezone  string
+	Location  string // IANA time zone, such as Asia/Jakarta
+	Latitude  float64
+	Longitude float64
 }
 
-// AirportMap contains only airports that exist in the current mock data
+// NearbyAirport is an airport resolved from a requested code together with its
+// distance (in km) from the closest airport the requested code stands for.
+type NearbyAirport struct {
+	Code       string
+	DistanceKm float64
+}
+
+const earthRadiusKm = 6371.0
+
+// AirportMap contains the airports that exist in the current mock data and their neighbours
 var AirportMap = map[string]AirportInfo{
-	"CGK": {Code: "CGK", City: "Jakarta", Name: "Soekarno-Hatta International Airport", Timezone: "WIB"},
-	"DPS": {Code: "DPS", City: "Denpasar", Name: "I Gusti Ngurah Rai International Airport", Timezone: "WITA"},
-	"SUB": {Code: "SUB", City: "Surabaya", Name: "Juanda International Airport", Timezone: "WIB"},
+	"CGK": {Code: "CGK", City: "Jakarta", Name: "Soekarno-Hatta International Airport", Timezone: "WIB", Location: "Asia/Jakarta", Latitude: -6.1256, Longitude: 106.6558},
+	"HLP": {Code: "HLP", City: "Jakarta", Name: "Halim Perdanakusuma International Airport", Timezone: "WIB", Location: "Asia/Jakarta", Latitude: -6.2666, Longitude: 106.8910},
+	"BDO": {Code: "BDO", City: "Bandung", Name: "Husein Sastranegara International Airport", Timezone: "WIB", Location: "Asia/Jakarta", Latitude: -6.9006, Longitude: 107.5763},
+	"KJT": {Code: "KJT", City: "Majalengka", Name: "Kertajati International Airport", Timezone: "WIB", Location: "Asia/Jakarta", Latitude: -6.6489, Longitude: 108.1667},
+	"SUB": {Code: "SUB", City: "Surabaya", Name: "Juanda International Airport", Timezone: "WIB", Location: "Asia/Jakarta", Latitude: -7.3798, Longitude: 112.7868},
+	"SOC": {Code: "SOC", City: "Surakarta", Name: "Adi Soemarmo International Airport", Timezone: "WIB", Location: "Asia/Jakarta", Latitude: -7.5161, Longitude: 110.7569},
+	"JOG": {Code: "JOG", City: "Yogyakarta", Name: "Adisutjipto Airport", Timezone: "WIB", Location: "Asia/Jakarta", Latitude: -7.7882, Longitude: 110.4318},
+	"YIA": {Code: "YIA", City: "Yogyakarta", Name: "Yogyakarta International Airport", Timezone: "WIB", Location: "Asia/Jakarta", Latitude: -7.9050, Longitude: 110.0570},
+	"DPS": {Code: "DPS", City: "Denpasar", Name: "I Gusti Ngurah Rai International Airport", Timezone: "WITA", Location: "Asia/Makassar", Latitude: -8.7482, Longitude: 115.1672},
+	"LOP": {Code: "LOP", City: "Lombok", Name: "Lombok International Airport", Timezone: "WITA", Location: "Asia/Makassar", Latitude: -8.7573, Longitude: 116.2767},
+	"UPG": {Code: "UPG", City: "Makassar", Name: "Sultan Hasanuddin International Airport", Timezone: "WITA", Location: "Asia/Makassar", Latitude: -5.0616, Longitude: 119.5540},
+	"SIN": {Code: "SIN", City: "Singapore", Name: "Singapore Changi Airport", Timezone: "SGT", Location: "Asia/Singapore", Latitude: 1.3644, Longitude: 103.9915},
+	"KUL": {Code: "KUL", City: "Kuala Lumpur", Name: "Kuala Lumpur International Airport", Timezone: "MYT", Location: "Asia/Kuala_Lumpur", Latitude: 2.7456, Longitude: 101.7072},
+	"NRT": {Code: "NRT", City: "Tokyo", Name: "Narita International Airport", Timezone: "JST", Location: "Asia/Tokyo", Latitude: 35.7720, Longitude: 140.3929},
+	"HND": {Code: "HND", City: "Tokyo", Name: "Haneda Airport", Timezone: "JST", Location: "Asia/Tokyo", Latitude: 35.5494, Longitude: 139.7798},
+}
+
+// MetroAreaMap groups airports that serve the same metropolitan area under its city code
+var MetroAreaMap = map[string][]string{
+	"JKT": {"CGK", "HLP"},
+	"TYO": {"NRT", "HND"},
 }
 
 func GetAirportDetail(code string) AirportInfo {
@@ -26,9 +60,80 @@ func GetAirportDetail(code string) AirportInfo {
 		City:     code,
 		Name:     "Unknown Airport",
 		Timezone: "WIB",
+		Location: "Asia/Jakarta",
 	}
 }
 
 func GetCityName(code string) string {
 	return GetAirportDetail(code).City
 }
+
+// IsMetroCode reports whether code is a metropolitan area code rather than an airport
+func IsMetroCode(code string) bool {
+	_, ok := MetroAreaMap[strings.ToUpper(code)]
+	return ok
+}
+
+// DistanceKm returns the great-circle distance between two airports using the haversine formula
+func DistanceKm(a, b AirportInfo) float64 {
+	lat1 := a.Latitude * math.Pi / 180
+	lat2 := b.Latitude * math.Pi / 180
+	dLat := (b.Latitude - a.Latitude) * math.Pi / 180
+	dLon := (b.Longitude - a.Longitude) * math.Pi / 180
+
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadiusKm * math.Asin(math.Sqrt(h))
+}
+
+// ExpandAirports resolves a requested code into the set of airports to search.
+// Metro codes expand into their member airports, and when radiusKm is positive every
+// known airport within that distance of one of them is added. Requested airports come
+// first, followed by nearby airports ordered by distance.
+func ExpandAirports(code string, radiusKm float64) []NearbyAirport {
+	code = strings.ToUpper(code)
+
+	base, ok := MetroAreaMap[code]
+	if !ok {
+		base = []string{code}
+	}
+
+	seen := make(map[string]bool, len(base))
+	result := make([]NearbyAirport, 0, len(base))
+	for _, c := range base {
+		seen[c] = true
+		result = append(result, NearbyAirport{Code: c})
+	}
+
+	if radiusKm <= 0 {
+		return result
+	}
+
+	var nearby []NearbyAirport
+	for c, info := range AirportMap {
+		if seen[c] {
+			continue
+		}
+
+		closest := math.MaxFloat64
+		for _, b := range base {
+			origin, known := AirportMap[b]
+			if !known {
+				continue
+			}
+			closest = math.Min(closest, DistanceKm(origin, info))
+		}
+
+		if closest <= radiusKm {
+			nearby = append(nearby, NearbyAirport{Code: c, DistanceKm: math.Round(closest*10) / 10})
+		}
+	}
+
+	sort.Slice(nearby, func(i, j int) bool {
+		if nearby[i].DistanceKm == nearby[j].DistanceKm {
+			return nearby[i].Code < nearby[j].Code
+		}
+		return nearby[i].DistanceKm < nearby[j].DistanceKm
+	})
+
+	return append(result, nearby...)
+}
@@ -0,0 +1,38 @@
+package helpers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExpandAirports(t *testing.T) {
+	tests := []struct {
+		name     string
+		code     string
+		radiusKm float64
+		want     []string
+	}{
+		{name: "single airport", code: "CGK", want: []string{"CGK"}},
+		{name: "metro code", code: "jkt", want: []string{"CGK", "HLP"}},
+		{name: "nearby airports", code: "DPS", radiusKm: 150, want: []string{"DPS", "LOP"}},
+		{name: "unknown airport", code: "XXX", radiusKm: 500, want: []string{"XXX"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, a := range ExpandAirports(tt.code, tt.radiusKm) {
+				got = append(got, a.Code)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestDistanceKm(t *testing.T) {
+	d := DistanceKm(AirportMap["CGK"], AirportMap["DPS"])
+
+	assert.InDelta(t, 982, d, 10)
+	assert.Equal(t, 0.0, DistanceKm(AirportMap["SUB"], AirportMap["SUB"]))
+}
@@ -0,0 +1,13 @@
+package helpers
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+)
+
+// GenerateSearchID returns a random 128-bit identifier encoded as hex
+func GenerateSearchID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,17 @@
+package helpers
+
+import (
+	"math/rand/v2"
+)
+
+// locatorAlphabet leaves out 0, 1, I and O so references can be read out over the phone
+const locatorAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+
+// GenerateLocator returns a random PNR-like record locator of the given length
+func GenerateLocator(length int) string {
+	b := make([]byte, length)
+	for i := range b {
+		b[i] = locatorAlphabet[rand.IntN(len(locatorAlphabet))]
+	}
+	return string(b)
+}
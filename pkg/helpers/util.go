@@ -2,7 +2,10 @@ package helpers
 
 import (
 	"fmt"
+	"strconv"
+	"strings"
 	"time"
+	"unicode"
 )
 
 func StringExists(arr []string, item string) bool {
@@ -62,3 +65,17 @@ func FormatIDR(amount float64) string {
 	}
 	return "IDR " + string(result)
 }
+
+// ParseWeightKg returns the first number in a weight such as "20 kg" or "7kg cabin", 0 when there is none
+func ParseWeightKg(s string) int {
+	start := strings.IndexFunc(s, unicode.IsDigit)
+	if start < 0 {
+		return 0
+	}
+	end := start
+	for end < len(s) && unicode.IsDigit(rune(s[end])) {
+		end++
+	}
+	kg, _ := strconv.Atoi(s[start:end])
+	return kg
+}
@@ -0,0 +1,13 @@
+package helpers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseWeightKg(t *testing.T) {
+	assert.Equal(t, 20, ParseWeightKg("20 kg"))
+	assert.Equal(t, 7, ParseWeightKg(" 7kg cabin"))
+	assert.Equal(t, 0, ParseWeightKg("Cabin baggage only"))
+}
@@ -7,12 +7,22 @@ import (
 	"github.com/elkoshar/bookcabin/pkg/validator"
 )
 
+// Normalizer is implemented by requests that clean up their fields, e.g. the case of codes, before
+// they are validated
+type Normalizer interface {
+	Normalize()
+}
+
 func ParseBodyAndValidate(r *http.Request, req interface{}) error {
 	err := json.NewDecoder(r.Body).Decode(req)
 	if err != nil {
 		return err
 	}
 
+	if n, ok := req.(Normalizer); ok {
+		n.Normalize()
+	}
+
 	_, err = validator.ValidateStruct(req)
 	if err != nil {
 		return err
@@ -0,0 +1,14 @@
+package i18n
+
+// catalogEN is the English catalog, other languages fall back to it for missing keys. English error and
+// validation messages are the ones of the errors themselves, so they have no entries here.
+var catalogEN = map[string]string{
+	"duration.format": "%dh %dm",
+
+	"number.thousands": ",",
+	"number.decimal":   ".",
+
+	"currency.USD": "US$",
+	"currency.SGD": "S$",
+	"currency.MYR": "RM",
+}
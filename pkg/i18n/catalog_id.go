@@ -0,0 +1,78 @@
+package i18n
+
+// catalogID is the Indonesian catalog
+var catalogID = map[string]string{
+	"duration.format": "%dj %dm",
+
+	"number.thousands": ".",
+	"number.decimal":   ",",
+
+	"currency.IDR": "Rp",
+	"currency.USD": "US$",
+	"currency.SGD": "S$",
+	"currency.MYR": "RM",
+
+	"city.SIN": "Singapura",
+
+	"validation.required":         "wajib diisi",
+	"validation.required_without": "wajib diisi",
+	"validation.required_if":      "wajib diisi",
+	"validation.iata":             "harus berupa kode IATA 3 huruf kapital",
+	"validation.airport":          "bukan kode bandara atau kota yang dikenal",
+	"validation.datetime":         "harus berupa tanggal dengan format YYYY-MM-DD",
+	"validation.not_past":         "tidak boleh tanggal yang sudah lewat",
+	"validation.date_gtefield":    "tidak boleh sebelum %s",
+	"validation.nefield":          "harus berbeda dari %s",
+	"validation.oneof":            "harus salah satu dari: %s",
+	"validation.chronological":    "harus berurutan menurut %s",
+	"validation.min":              "minimal %s",
+	"validation.max":              "maksimal %s",
+	"validation.email":            "harus berupa alamat email yang valid",
+
+	"error.BAD_REQUEST":           "Permintaan tidak valid",
+	"error.VALIDATION_FAILED":     "Validasi gagal",
+	"error.UNAUTHORIZED":          "Tidak memiliki akses",
+	"error.NOT_FOUND":             "Data tidak ditemukan",
+	"error.METHOD_NOT_ALLOWED":    "Metode tidak diizinkan",
+	"error.NOT_ACCEPTABLE":        "Format respons yang diminta tidak didukung",
+	"error.CONFLICT":              "Permintaan bertentangan dengan kondisi saat ini",
+	"error.TOO_MANY_REQUESTS":     "Terlalu banyak permintaan, coba lagi nanti",
+	"error.INTERNAL_SERVER_ERROR": "Terjadi kesalahan pada server",
+	"error.SERVICE_UNAVAILABLE":   "Layanan sedang tidak tersedia",
+
+	"error.INVALID_ITINERARY":           "Rencana perjalanan tidak valid",
+	"error.INVALID_OFFER_ID":            "ID penawaran tidak valid",
+	"error.INVALID_SEAT_SELECTION":      "Pilihan kursi tidak valid",
+	"error.INVALID_ANCILLARY_SELECTION": "Pilihan layanan tambahan tidak valid",
+	"error.UNSUPPORTED_FORMAT":          "Format tidak didukung",
+	"error.UNSUPPORTED_CURRENCY":        "Mata uang tidak didukung",
+	"error.INVALID_PAYMENT_EVENT":       "Notifikasi pembayaran tidak valid",
+	"error.RATES_UNAVAILABLE":           "Kurs mata uang sedang tidak tersedia",
+	"error.PAYMENT_GATEWAY_UNAVAILABLE": "Layanan pembayaran sedang tidak tersedia",
+	"error.INVALID_SIGNATURE":           "Tanda tangan webhook tidak valid",
+	"error.PAYMENT_DECLINED":            "Pembayaran ditolak",
+	"error.OFFER_NOT_FOUND":             "Penawaran tidak ditemukan",
+	"error.SEAT_MAP_UNAVAILABLE":        "Denah kursi tidak tersedia untuk penawaran ini",
+	"error.BOOKING_NOT_FOUND":           "Pemesanan tidak ditemukan",
+	"error.PAYMENT_NOT_FOUND":           "Pembayaran tidak ditemukan",
+	"error.SEARCH_NOT_FOUND":            "Pencarian tidak ditemukan",
+	"error.SEARCH_JOB_NOT_FOUND":        "Pencarian asinkron tidak ditemukan",
+	"error.INSUFFICIENT_SEATS":          "Kursi yang tersedia tidak mencukupi",
+	"error.OFFER_SOLD_OUT":              "Penawaran sudah habis terjual",
+	"error.PRICE_CHANGED":               "Harga penawaran berubah melebihi toleransi",
+	"error.SEAT_UNAVAILABLE":            "Kursi tidak tersedia",
+	"error.BOOKING_NOT_CANCELLABLE":     "Pemesanan tidak dapat dibatalkan dalam status saat ini",
+	"error.BOOKING_NOT_HELD":            "Pemesanan sudah tidak ditahan",
+	"error.HOLD_NOT_FOUND":              "Penahanan kursi tidak ditemukan",
+	"error.INVALID_BOOKING_TRANSITION":  "Perubahan status pemesanan tidak diizinkan",
+	"error.QUOTE_EXPIRED":               "Penawaran pembatalan sudah tidak berlaku",
+	"error.PAYMENT_PENDING":             "Masih ada pembayaran yang menunggu autentikasi",
+	"error.INVALID_PAYMENT_STATE":       "Status pembayaran tidak memungkinkan operasi ini",
+	"error.BOOKING_NOT_TICKETED":        "Tiket untuk pemesanan ini belum diterbitkan",
+	"error.SEARCH_JOB_FINISHED":         "Pencarian asinkron sudah selesai",
+	"error.IDEMPOTENCY_KEY_IN_PROGRESS": "Permintaan dengan idempotency key ini masih diproses",
+	"error.IDEMPOTENCY_KEY_REUSED":      "Idempotency key sudah digunakan untuk permintaan lain",
+	"error.PROVIDERS_UNAVAILABLE":       "Semua maskapai gagal merespons",
+	"error.PROVIDERS_TIMEOUT":           "Semua maskapai tidak merespons tepat waktu",
+	"error.SEARCH_QUEUE_FULL":           "Terlalu banyak pencarian dalam antrean, coba lagi nanti",
+}
@@ -0,0 +1,61 @@
+package i18n
+
+import (
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/elkoshar/bookcabin/pkg/helpers"
+)
+
+// FormatDuration formats minutes as hours and minutes, e.g. "1h 45m" in English and "1j 45m" in Indonesian
+func FormatDuration(lang string, minutes int) string {
+	return T(lang, "duration.format", minutes/60, minutes%60)
+}
+
+// FormatPrice formats an amount with the currency symbol and separators of lang, e.g. "Rp 650.000" in
+// Indonesian and "IDR 650,000" in English. Rupiah amounts have no decimals, other currencies have two.
+func FormatPrice(lang string, amount float64, currency string) string {
+	decimals := 2
+	if currency == "IDR" {
+		decimals = 0
+	}
+
+	symbol, ok := Lookup(lang, "currency."+currency)
+	if !ok {
+		symbol = currency
+	}
+
+	text := strconv.FormatFloat(math.Abs(amount), 'f', decimals, 64)
+	whole, fraction, _ := strings.Cut(text, ".")
+
+	var b strings.Builder
+	if amount < 0 {
+		b.WriteByte('-')
+	}
+	b.WriteString(symbol)
+	b.WriteByte(' ')
+	for i, digit := range whole {
+		if i > 0 && (len(whole)-i)%3 == 0 {
+			b.WriteString(T(lang, "number.thousands"))
+		}
+		b.WriteRune(digit)
+	}
+	if fraction != "" {
+		b.WriteString(T(lang, "number.decimal"))
+		b.WriteString(fraction)
+	}
+	return b.String()
+}
+
+// City returns the name of the city served by an airport in lang, taken from the airport database.
+// fallback is returned for airports the database does not know.
+func City(lang, airport, fallback string) string {
+	if name, ok := Lookup(lang, "city."+airport); ok {
+		return name
+	}
+	if info, ok := helpers.AirportMap[airport]; ok {
+		return info.City
+	}
+	return fallback
+}
@@ -0,0 +1,121 @@
+package i18n
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Supported languages
+const (
+	Indonesian = "id"
+	English    = "en"
+)
+
+// Default is the language used when the client does not ask for a supported one
+const Default = Indonesian
+
+// catalogs holds the translated messages of every supported language by key
+var catalogs = map[string]map[string]string{
+	Indonesian: catalogID,
+	English:    catalogEN,
+}
+
+type contextKey struct{}
+
+// WithLanguage returns a copy of ctx carrying lang
+func WithLanguage(ctx context.Context, lang string) context.Context {
+	return context.WithValue(ctx, contextKey{}, lang)
+}
+
+// FromContext returns the language negotiated for the request, Default when there is none
+func FromContext(ctx context.Context) string {
+	if lang, ok := ctx.Value(contextKey{}).(string); ok {
+		return lang
+	}
+	return Default
+}
+
+// Supported reports whether lang has a catalog
+func Supported(lang string) bool {
+	_, ok := catalogs[lang]
+	return ok
+}
+
+// Negotiate picks the supported language the client prefers most from an Accept-Language header,
+// such as "en-US,en;q=0.9,id;q=0.8". Region subtags are ignored.
+func Negotiate(header string) string {
+	type candidate struct {
+		lang string
+		q    float64
+	}
+
+	var candidates []candidate
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		lang, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(tag)), "-")
+		if !Supported(lang) {
+			continue
+		}
+
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		if q > 0 {
+			candidates = append(candidates, candidate{lang: lang, q: q})
+		}
+	}
+
+	if len(candidates) == 0 {
+		return Default
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].q > candidates[j].q
+	})
+	return candidates[0].lang
+}
+
+// Middleware negotiates the language of every request from its Accept-Language header and announces it
+// in Content-Language
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lang := Negotiate(r.Header.Get("Accept-Language"))
+
+		w.Header().Set("Content-Language", lang)
+		w.Header().Add("Vary", "Accept-Language")
+
+		next.ServeHTTP(w, r.WithContext(WithLanguage(r.Context(), lang)))
+	})
+}
+
+// T returns the message of key in lang, formatted with args when given. It falls back to English,
+// then to an empty string when no catalog has the key.
+func T(lang, key string, args ...interface{}) string {
+	msg, ok := catalogs[lang][key]
+	if !ok {
+		msg, ok = catalogEN[key]
+	}
+	if !ok {
+		return ""
+	}
+
+	if len(args) > 0 {
+		return fmt.Sprintf(msg, args...)
+	}
+	return msg
+}
+
+// Lookup reports whether lang has its own translation of key, without falling back to English
+func Lookup(lang, key string) (string, bool) {
+	msg, ok := catalogs[lang][key]
+	return msg, ok
+}
@@ -0,0 +1,116 @@
+package i18n
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestNegotiate(t *testing.T) {
+	tests := []struct {
+		header string
+		want   string
+	}{
+		{header: "", want: Default},
+		{header: "en", want: English},
+		{header: "id", want: Indonesian},
+		{header: "en-US,en;q=0.9", want: English},
+		{header: "id-ID,en;q=0.5", want: Indonesian},
+		{header: "fr-FR,en;q=0.8,id;q=0.9", want: Indonesian},
+		{header: "fr, de", want: Default},
+		{header: "en;q=0,id;q=0.1", want: Indonesian},
+		{header: "EN-gb", want: English},
+		{header: "en;q=abc", want: Default},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.header, func(t *testing.T) {
+			if got := Negotiate(tt.header); got != tt.want {
+				t.Errorf("Negotiate(%q) = %q, want %q", tt.header, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatDuration(t *testing.T) {
+	if got := FormatDuration(English, 105); got != "1h 45m" {
+		t.Errorf("FormatDuration(en) = %q, want %q", got, "1h 45m")
+	}
+	if got := FormatDuration(Indonesian, 105); got != "1j 45m" {
+		t.Errorf("FormatDuration(id) = %q, want %q", got, "1j 45m")
+	}
+}
+
+func TestFormatPrice(t *testing.T) {
+	tests := []struct {
+		lang     string
+		amount   float64
+		currency string
+		want     string
+	}{
+		{lang: Indonesian, amount: 650000, currency: "IDR", want: "Rp 650.000"},
+		{lang: English, amount: 650000, currency: "IDR", want: "IDR 650,000"},
+		{lang: Indonesian, amount: 1234567.4, currency: "IDR", want: "Rp 1.234.567"},
+		{lang: Indonesian, amount: 500, currency: "IDR", want: "Rp 500"},
+		{lang: English, amount: -25000, currency: "IDR", want: "-IDR 25,000"},
+		{lang: English, amount: 1045.5, currency: "USD", want: "US$ 1,045.50"},
+		{lang: Indonesian, amount: 1045.5, currency: "USD", want: "US$ 1.045,50"},
+		{lang: English, amount: 53.6, currency: "SGD", want: "S$ 53.60"},
+		{lang: Indonesian, amount: 187.2, currency: "MYR", want: "RM 187,20"},
+		{lang: English, amount: 9800, currency: "JPY", want: "JPY 9,800.00"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got := FormatPrice(tt.lang, tt.amount, tt.currency); got != tt.want {
+				t.Errorf("FormatPrice() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCity(t *testing.T) {
+	if got := City(Indonesian, "SIN", "Singapore"); got != "Singapura" {
+		t.Errorf("City(id, SIN) = %q, want Singapura", got)
+	}
+	if got := City(English, "SIN", "SINGAPORE"); got != "Singapore" {
+		t.Errorf("City(en, SIN) = %q, want Singapore", got)
+	}
+	if got := City(English, "XYZ", "Somewhere"); got != "Somewhere" {
+		t.Errorf("City(en, XYZ) = %q, want Somewhere", got)
+	}
+}
+
+func TestT(t *testing.T) {
+	if got := T(Indonesian, "number.thousands"); got != "." {
+		t.Errorf("T(id) = %q, want %q", got, ".")
+	}
+	if got := T("fr", "duration.format", 1, 5); got != "1h 5m" {
+		t.Errorf("T() without a catalog = %q, want the English message", got)
+	}
+	if got := T(English, "missing.key"); got != "" {
+		t.Errorf("T() of a missing key = %q, want empty", got)
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	var got string
+	handler := Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = FromContext(r.Context())
+	}))
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("Accept-Language", "en-US,en;q=0.9")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+
+	if got != English {
+		t.Errorf("FromContext() = %q, want %q", got, English)
+	}
+	if lang := w.Header().Get("Content-Language"); lang != English {
+		t.Errorf("Content-Language = %q, want %q", lang, English)
+	}
+	if vary := w.Header().Get("Vary"); vary != "Accept-Language" {
+		t.Errorf("Vary = %q, want Accept-Language", vary)
+	}
+}
@@ -0,0 +1,63 @@
+package idempotency
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type entry struct {
+	record    Record
+	expiresAt time.Time
+}
+
+// MemoryStore is a Store kept in process memory, suitable for a single instance
+type MemoryStore struct {
+	mu      sync.Mutex
+	entries map[string]entry
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{entries: make(map[string]entry)}
+}
+
+func (m *MemoryStore) Reserve(ctx context.Context, key, fingerprint string, ttl time.Duration) (Record, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	m.evictExpired(now)
+
+	if e, ok := m.entries[key]; ok {
+		return e.record, false, nil
+	}
+
+	record := Record{Fingerprint: fingerprint, CreatedAt: now}
+	m.entries[key] = entry{record: record, expiresAt: now.Add(ttl)}
+	return record, true, nil
+}
+
+func (m *MemoryStore) Complete(ctx context.Context, key string, record Record, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	record.Completed = true
+	m.entries[key] = entry{record: record, expiresAt: record.CreatedAt.Add(ttl)}
+	return nil
+}
+
+func (m *MemoryStore) Release(ctx context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.entries, key)
+	return nil
+}
+
+func (m *MemoryStore) evictExpired(now time.Time) {
+	for key, e := range m.entries {
+		if now.After(e.expiresAt) {
+			delete(m.entries, key)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package idempotency_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/elkoshar/bookcabin/pkg/idempotency"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryStore(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("reserve then replay completed record", func(t *testing.T) {
+		store := idempotency.NewMemoryStore()
+
+		record, reserved, err := store.Reserve(ctx, "key-1", "fp-1", time.Minute)
+		assert.NoError(t, err)
+		assert.True(t, reserved)
+		assert.False(t, record.Completed)
+
+		existing, reserved, err := store.Reserve(ctx, "key-1", "fp-1", time.Minute)
+		assert.NoError(t, err)
+		assert.False(t, reserved)
+		assert.False(t, existing.Completed)
+
+		record.StatusCode = http.StatusCreated
+		record.Body = []byte(`{"ok":true}`)
+		assert.NoError(t, store.Complete(ctx, "key-1", record, time.Minute))
+
+		existing, reserved, err = store.Reserve(ctx, "key-1", "fp-2", time.Minute)
+		assert.NoError(t, err)
+		assert.False(t, reserved)
+		assert.True(t, existing.Completed)
+		assert.Equal(t, "fp-1", existing.Fingerprint)
+		assert.Equal(t, http.StatusCreated, existing.StatusCode)
+	})
+
+	t.Run("released key can be reserved again", func(t *testing.T) {
+		store := idempotency.NewMemoryStore()
+
+		_, _, _ = store.Reserve(ctx, "key-1", "fp-1", time.Minute)
+		assert.NoError(t, store.Release(ctx, "key-1"))
+
+		_, reserved, err := store.Reserve(ctx, "key-1", "fp-2", time.Minute)
+		assert.NoError(t, err)
+		assert.True(t, reserved)
+	})
+
+	t.Run("expired key can be reserved again", func(t *testing.T) {
+		store := idempotency.NewMemoryStore()
+
+		_, _, _ = store.Reserve(ctx, "key-1", "fp-1", time.Millisecond)
+		time.Sleep(5 * time.Millisecond)
+
+		_, reserved, err := store.Reserve(ctx, "key-1", "fp-2", time.Minute)
+		assert.NoError(t, err)
+		assert.True(t, reserved)
+	})
+}
@@ -0,0 +1,30 @@
+package idempotency
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// Record is the stored outcome of the first request made with an idempotency key
+type Record struct {
+	Fingerprint string
+	Completed   bool
+	StatusCode  int
+	Header      http.Header
+	Body        []byte
+	CreatedAt   time.Time
+}
+
+// Store keeps idempotency records for a limited time
+type Store interface {
+	// Reserve creates an in-flight record for key. When the key is already taken it
+	// returns the existing record and false instead.
+	Reserve(ctx context.Context, key, fingerprint string, ttl time.Duration) (Record, bool, error)
+
+	// Complete stores the response of the request that reserved key
+	Complete(ctx context.Context, key string, record Record, ttl time.Duration) error
+
+	// Release forgets key so the request can be retried
+	Release(ctx context.Context, key string) error
+}
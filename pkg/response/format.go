@@ -0,0 +1,149 @@
+package response
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// FormatJSON is the name of the default format, the JSON envelope of Response
+const FormatJSON = "json"
+
+// ErrNotEncodable is returned by an Encoder for data it cannot represent
+var ErrNotEncodable = errors.New("response cannot be encoded in the requested format")
+
+// Encoder writes the data of a successful response in a format other than JSON
+type Encoder func(w io.Writer, data interface{}) error
+
+// Format is a representation clients can ask for with the format query parameter or the Accept header
+type Format struct {
+	Name        string
+	ContentType string
+	Extension   string
+	Encode      Encoder
+}
+
+var (
+	formatsMu sync.RWMutex
+	formats   []Format
+)
+
+// RegisterFormat makes f available to every exportable response rendered with Render
+func RegisterFormat(f Format) {
+	formatsMu.Lock()
+	defer formatsMu.Unlock()
+
+	formats = append(formats, f)
+}
+
+// NegotiateFormat picks the format of the response to r. The format query parameter wins over the Accept
+// header, whose media ranges are tried by quality. JSON is returned when neither asks for a registered
+// format, and an error when the format parameter names an unknown one.
+func NegotiateFormat(r *http.Request) (Format, error) {
+	formatsMu.RLock()
+	defer formatsMu.RUnlock()
+
+	if name := strings.ToLower(r.URL.Query().Get("format")); name != "" {
+		if name == FormatJSON {
+			return jsonFormat(), nil
+		}
+		for _, f := range formats {
+			if f.Name == name {
+				return f, nil
+			}
+		}
+		return Format{}, fmt.Errorf("%w: unsupported format %q", ErrNotEncodable, name)
+	}
+
+	for _, mediaType := range acceptedTypes(r.Header.Get("Accept")) {
+		if mediaType == "application/json" {
+			return jsonFormat(), nil
+		}
+		for _, f := range formats {
+			if mediaType == baseType(f.ContentType) {
+				return f, nil
+			}
+		}
+	}
+	return jsonFormat(), nil
+}
+
+func jsonFormat() Format {
+	return Format{Name: FormatJSON, ContentType: "application/json"}
+}
+
+// acceptedTypes lists the media types of an Accept header from the most to the least preferred,
+// leaving out the ones with a quality of zero
+func acceptedTypes(header string) []string {
+	type candidate struct {
+		mediaType string
+		q         float64
+	}
+
+	var candidates []candidate
+	for _, part := range strings.Split(header, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+
+		q := 1.0
+		if v, ok := params["q"]; ok {
+			if q, err = strconv.ParseFloat(v, 64); err != nil {
+				continue
+			}
+		}
+		if q > 0 {
+			candidates = append(candidates, candidate{mediaType: mediaType, q: q})
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].q > candidates[j].q
+	})
+
+	types := make([]string, len(candidates))
+	for i, c := range candidates {
+		types[i] = c.mediaType
+	}
+	return types
+}
+
+func baseType(contentType string) string {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return contentType
+	}
+	return mediaType
+}
+
+// renderFormat writes the data of res in f as a download named after res.Filename. The data is encoded
+// before anything is written, so an encoding failure can still be reported as a JSON error.
+func (res *Response) renderFormat(w http.ResponseWriter, r *http.Request, f Format, status int) error {
+	var buf bytes.Buffer
+	if err := f.Encode(&buf, res.Data); err != nil {
+		return err
+	}
+
+	filename := res.Filename
+	if filename == "" {
+		filename = "export"
+	}
+
+	w.Header().Set("Content-Type", f.ContentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename+"."+f.Extension))
+	w.Header().Set("Content-Length", strconv.Itoa(buf.Len()))
+	w.WriteHeader(status)
+	if _, err := w.Write(buf.Bytes()); err != nil {
+		slog.WarnContext(r.Context(), fmt.Sprintf("Failed to write %s response: %v", f.Name, err))
+	}
+	return nil
+}
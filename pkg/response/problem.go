@@ -0,0 +1,123 @@
+package response
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/go-chi/chi/v5/middleware"
+
+	"github.com/elkoshar/bookcabin/pkg/validator"
+)
+
+// ProblemContentType is the media type of RFC 7807 error documents
+const ProblemContentType = "application/problem+json"
+
+// CodeValidationFailed is the code of requests rejected by pkg/validator
+const CodeValidationFailed = "VALIDATION_FAILED"
+
+// ProblemJSON renders every error as application/problem+json, otherwise only clients that ask for it
+// in their Accept header get it
+var ProblemJSON bool
+
+// Definition is one entry of the error catalogue
+type Definition struct {
+	Status int
+	Code   string
+	Title  string
+}
+
+type catalogueEntry struct {
+	err error
+	def Definition
+}
+
+var (
+	catalogueMu sync.RWMutex
+	catalogue   []catalogueEntry
+)
+
+// Register adds err to the error catalogue, errors that wrap it are reported with the given status,
+// stable code and title. Errors registered first win when an error wraps several of them.
+func Register(err error, status int, code, title string) {
+	catalogueMu.Lock()
+	defer catalogueMu.Unlock()
+
+	catalogue = append(catalogue, catalogueEntry{err: err, def: Definition{Status: status, Code: code, Title: title}})
+}
+
+// Lookup returns the catalogue entry of err
+func Lookup(err error) (Definition, bool) {
+	var fields validator.Errors
+	if errors.As(err, &fields) {
+		return Definition{Status: http.StatusBadRequest, Code: CodeValidationFailed, Title: "Validation failed"}, true
+	}
+
+	catalogueMu.RLock()
+	defer catalogueMu.RUnlock()
+
+	for _, entry := range catalogue {
+		if errors.Is(err, entry.err) {
+			return entry.def, true
+		}
+	}
+	return Definition{}, false
+}
+
+// statusCode turns an HTTP status into a stable code, such as BAD_REQUEST, for errors missing from the catalogue
+func statusCode(status int) string {
+	text := http.StatusText(status)
+	if text == "" {
+		return "UNKNOWN_ERROR"
+	}
+	return strings.ToUpper(strings.NewReplacer(" ", "_", "-", "_", "'", "").Replace(text))
+}
+
+// Problem is an RFC 7807 error document
+type Problem struct {
+	Type      string                 `json:"type"`
+	Title     string                 `json:"title"`
+	Status    int                    `json:"status"`
+	Detail    string                 `json:"detail"`
+	Instance  string                 `json:"instance,omitempty"`
+	Code      string                 `json:"code"`
+	RequestID string                 `json:"request_id,omitempty"`
+	Errors    []validator.FieldError `json:"errors,omitempty"`
+}
+
+// wantsProblem reports whether the error of r should be rendered as problem+json
+func wantsProblem(r *http.Request) bool {
+	return ProblemJSON || strings.Contains(r.Header.Get("Accept"), ProblemContentType)
+}
+
+// renderProblem writes the error of res as an RFC 7807 document
+func (res *Response) renderProblem(w http.ResponseWriter, r *http.Request, status int) {
+	problem := Problem{
+		Type:      "urn:bookcabin:error:" + strings.ToLower(res.Error.ErrorCode),
+		Title:     res.Error.Title,
+		Status:    status,
+		Detail:    res.Error.Msg,
+		Instance:  r.URL.Path,
+		Code:      res.Error.ErrorCode,
+		RequestID: res.Error.RequestID,
+		Errors:    res.Error.Fields,
+	}
+	if problem.Title == "" {
+		problem.Title = http.StatusText(status)
+	}
+
+	w.Header().Set("Content-Type", ProblemContentType)
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(problem); err != nil {
+		slog.WarnContext(r.Context(), fmt.Sprintf("Failed to write problem response: %v", err))
+	}
+}
+
+// requestID returns the ID middleware.RequestID gave to r
+func requestID(r *http.Request) string {
+	return middleware.GetReqID(r.Context())
+}
@@ -1,10 +1,16 @@
 package response
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/go-chi/render"
+
+	"github.com/elkoshar/bookcabin/pkg/i18n"
+	"github.com/elkoshar/bookcabin/pkg/validator"
 )
 
 type Response struct {
@@ -16,6 +22,13 @@ type Response struct {
 	IsStringCode       bool           `json:"-"`
 	CodeRender         interface{}    `json:"code"`
 	OverrideStatusText map[int]string `json:"-"`
+
+	// Exportable lets Data be rendered in a registered format picked by the format query parameter or
+	// the Accept header. Responses that leave it unset are always JSON.
+	Exportable bool `json:"-"`
+
+	// Filename names the download, without extension, when Data is rendered in a format other than JSON
+	Filename string `json:"-"`
 }
 
 func NewResponse() Response {
@@ -26,6 +39,18 @@ type Error struct {
 	Status bool   `json:"status" example:"false"`
 	Msg    string `json:"msg" example:" "`
 	Code   int    `json:"code" example:"0"`
+
+	// ErrorCode is the stable code of the error from the error catalogue, such as INVALID_ITINERARY
+	ErrorCode string `json:"error_code,omitempty" example:"VALIDATION_FAILED"`
+
+	// RequestID identifies the request in the server logs
+	RequestID string `json:"request_id,omitempty"`
+
+	// Fields lists each field that failed validation
+	Fields []validator.FieldError `json:"fields,omitempty"`
+
+	// Title is the short summary of the error used by problem+json responses
+	Title string `json:"-"`
 }
 
 func NewError(err error, code int) *Error {
@@ -47,6 +72,43 @@ func (res *Response) Render(w http.ResponseWriter, r *http.Request, statusCode .
 	}
 
 	res.ServerTime = time.Now().Unix()
+
+	if res.Exportable && !res.Error.Status && res.Data != nil {
+		status := res.Code
+		if len(statusCode) > 0 {
+			status = statusCode[0]
+		}
+
+		format, err := NegotiateFormat(r)
+		if err == nil && format.Encode != nil {
+			if err = res.renderFormat(w, r, format, status); err == nil {
+				return
+			}
+		}
+		if err != nil {
+			status = http.StatusInternalServerError
+			if errors.Is(err, ErrNotEncodable) {
+				status = http.StatusNotAcceptable
+			}
+			res.Data = nil
+			res.SetError(err, status)
+			statusCode = nil
+		}
+	}
+
+	if res.Error.Status {
+		res.Error.RequestID = requestID(r)
+		res.Error.localize(i18n.FromContext(r.Context()))
+		if wantsProblem(r) {
+			status := res.Code
+			if len(statusCode) > 0 {
+				status = statusCode[0]
+			}
+			res.renderProblem(w, r, status)
+			return
+		}
+	}
+
 	render.Status(r, res.Code)
 
 	if len(statusCode) > 0 {
@@ -79,18 +141,59 @@ func (res *Response) SetError(err error, code ...int) {
 		code = []int{cerr.Code}
 	}
 
-	if len(code) > 0 {
+	def, catalogued := Lookup(err)
+
+	switch {
+	case len(code) > 0:
 		res.Code = code[0]
-	} else {
+	case catalogued:
+		res.Code = def.Status
+	default:
 		res.Code = http.StatusInternalServerError
 	}
 
+	if !catalogued {
+		def = Definition{Code: statusCode(res.Code), Title: http.StatusText(res.Code)}
+	}
+
 	if err != nil {
 		res.Error = Error{
-			Msg:    err.Error(),
-			Status: true,
-			Code:   res.Code,
+			Msg:       err.Error(),
+			Status:    true,
+			Code:      res.Code,
+			ErrorCode: def.Code,
+			Title:     def.Title,
+		}
+
+		var fields validator.Errors
+		if errors.As(err, &fields) {
+			res.Error.Fields = fields
 		}
 	}
 
 }
+
+// localize translates the message of the error, and of each failed field, when lang has a translation
+// for them
+func (e *Error) localize(lang string) {
+	if len(e.Fields) > 0 {
+		fields := make(validator.Errors, len(e.Fields))
+		for i, f := range e.Fields {
+			if msg, ok := i18n.Lookup(lang, "validation."+f.Rule); ok {
+				if strings.Contains(msg, "%s") {
+					msg = fmt.Sprintf(msg, strings.ReplaceAll(f.Param, " ", ", "))
+				}
+				f.Message = msg
+			}
+			fields[i] = f
+		}
+		e.Fields = fields
+		e.Msg = fields.Error()
+	} else if msg, ok := i18n.Lookup(lang, "error."+e.ErrorCode); ok {
+		e.Msg = msg
+	}
+
+	if title, ok := i18n.Lookup(lang, "error."+e.ErrorCode); ok {
+		e.Title = title
+	}
+}
@@ -1 +1,240 @@
 package response
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elkoshar/bookcabin/pkg/i18n"
+	"github.com/elkoshar/bookcabin/pkg/validator"
+)
+
+var errCatalogued = errors.New("catalogued")
+
+func init() {
+	Register(errCatalogued, http.StatusBadGateway, "CATALOGUED", "Catalogued error")
+}
+
+func TestSetError(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		code      []int
+		status    int
+		errorCode string
+	}{
+		{name: "catalogued", err: errCatalogued, status: http.StatusBadGateway, errorCode: "CATALOGUED"},
+		{name: "wrapped", err: fmt.Errorf("provider: %w", errCatalogued), status: http.StatusBadGateway, errorCode: "CATALOGUED"},
+		{name: "explicit status wins", err: errCatalogued, code: []int{http.StatusServiceUnavailable}, status: http.StatusServiceUnavailable, errorCode: "CATALOGUED"},
+		{name: "validation", err: validator.Errors{{Field: "Origin", Rule: "required"}}, status: http.StatusBadRequest, errorCode: CodeValidationFailed},
+		{name: "uncatalogued with status", err: errors.New("bad json"), code: []int{http.StatusBadRequest}, status: http.StatusBadRequest, errorCode: "BAD_REQUEST"},
+		{name: "uncatalogued", err: errors.New("boom"), status: http.StatusInternalServerError, errorCode: "INTERNAL_SERVER_ERROR"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := Response{}
+			res.SetError(tt.err, tt.code...)
+
+			assert.Equal(t, tt.status, res.Code)
+			assert.Equal(t, tt.status, res.Error.Code)
+			assert.Equal(t, tt.errorCode, res.Error.ErrorCode)
+			assert.Equal(t, tt.err.Error(), res.Error.Msg)
+		})
+	}
+}
+
+func TestRender_Problem(t *testing.T) {
+	tests := []struct {
+		name        string
+		accept      string
+		problemJSON bool
+		wantProblem bool
+	}{
+		{name: "default", accept: "application/json"},
+		{name: "accept header", accept: "application/problem+json", wantProblem: true},
+		{name: "server option", accept: "application/json", problemJSON: true, wantProblem: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ProblemJSON = tt.problemJSON
+			defer func() { ProblemJSON = false }()
+
+			r := httptest.NewRequest(http.MethodGet, "/flight/search", nil)
+			r.Header.Set("Accept", tt.accept)
+			r = r.WithContext(context.WithValue(r.Context(), middleware.RequestIDKey, "req-1"))
+			w := httptest.NewRecorder()
+
+			res := Response{}
+			res.SetError(fmt.Errorf("%w: no answer", errCatalogued))
+			res.Render(w, r)
+
+			assert.Equal(t, http.StatusBadGateway, w.Code)
+			if !tt.wantProblem {
+				var body Response
+				assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+				assert.Equal(t, "CATALOGUED", body.Error.ErrorCode)
+				assert.Equal(t, "req-1", body.Error.RequestID)
+				return
+			}
+
+			assert.Equal(t, ProblemContentType, w.Header().Get("Content-Type"))
+
+			var problem Problem
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+			assert.Equal(t, Problem{
+				Type:      "urn:bookcabin:error:catalogued",
+				Title:     "Catalogued error",
+				Status:    http.StatusBadGateway,
+				Detail:    "catalogued: no answer",
+				Instance:  "/flight/search",
+				Code:      "CATALOGUED",
+				RequestID: "req-1",
+			}, problem)
+		})
+	}
+}
+
+func TestRender_Localized(t *testing.T) {
+	tests := []struct {
+		name    string
+		lang    string
+		err     error
+		wantMsg string
+		wantFld string
+	}{
+		{name: "validation in indonesian", lang: i18n.Indonesian, err: validator.Errors{{Field: "Passengers", Rule: "max", Param: "9", Message: "must be at most 9"}},
+			wantMsg: "Passengers maksimal 9", wantFld: "maksimal 9"},
+		{name: "validation in english", lang: i18n.English, err: validator.Errors{{Field: "Passengers", Rule: "max", Param: "9", Message: "must be at most 9"}},
+			wantMsg: "Passengers must be at most 9", wantFld: "must be at most 9"},
+		{name: "status error in indonesian", lang: i18n.Indonesian, err: errors.New("boom"), wantMsg: "Terjadi kesalahan pada server"},
+		{name: "status error in english", lang: i18n.English, err: errors.New("boom"), wantMsg: "boom"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r = r.WithContext(i18n.WithLanguage(r.Context(), tt.lang))
+			w := httptest.NewRecorder()
+
+			res := Response{}
+			res.SetError(tt.err)
+			res.Render(w, r)
+
+			var body Response
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+			assert.Equal(t, tt.wantMsg, body.Error.Msg)
+			if tt.wantFld != "" {
+				assert.Equal(t, tt.wantFld, body.Error.Fields[0].Message)
+			}
+		})
+	}
+}
+
+func init() {
+	RegisterFormat(Format{Name: "csv", ContentType: "text/csv; charset=utf-8", Extension: "csv", Encode: func(w io.Writer, data interface{}) error {
+		rows, ok := data.([]string)
+		if !ok {
+			return ErrNotEncodable
+		}
+		_, err := io.WriteString(w, strings.Join(rows, "\n"))
+		return err
+	}})
+}
+
+func TestNegotiateFormat(t *testing.T) {
+	tests := []struct {
+		name    string
+		target  string
+		accept  string
+		want    string
+		wantErr bool
+	}{
+		{name: "default", target: "/", want: FormatJSON},
+		{name: "query", target: "/?format=CSV", want: "csv"},
+		{name: "query wins over accept", target: "/?format=json", accept: "text/csv", want: FormatJSON},
+		{name: "accept", target: "/", accept: "text/csv", want: "csv"},
+		{name: "accept by quality", target: "/", accept: "application/json;q=0.5, text/csv", want: "csv"},
+		{name: "accept json first", target: "/", accept: "application/json, text/csv", want: FormatJSON},
+		{name: "accept anything", target: "/", accept: "text/html, */*;q=0.8", want: FormatJSON},
+		{name: "refused format", target: "/", accept: "text/csv;q=0", want: FormatJSON},
+		{name: "unknown query format", target: "/?format=xml", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, tt.target, nil)
+			r.Header.Set("Accept", tt.accept)
+
+			got, err := NegotiateFormat(r)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrNotEncodable)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got.Name)
+		})
+	}
+}
+
+func TestRender_Format(t *testing.T) {
+	tests := []struct {
+		name       string
+		target     string
+		data       interface{}
+		exportable bool
+		wantStatus int
+		wantType   string
+		wantBody   string
+	}{
+		{name: "encoded", target: "/?format=csv", data: []string{"a", "b"}, exportable: true, wantStatus: http.StatusOK, wantType: "text/csv; charset=utf-8", wantBody: "a\nb"},
+		{name: "not encodable", target: "/?format=csv", data: map[string]string{"a": "b"}, exportable: true, wantStatus: http.StatusNotAcceptable, wantType: "application/json"},
+		{name: "unknown format", target: "/?format=xml", data: []string{"a"}, exportable: true, wantStatus: http.StatusNotAcceptable, wantType: "application/json"},
+		{name: "json", target: "/", data: []string{"a"}, exportable: true, wantStatus: http.StatusOK, wantType: "application/json"},
+		{name: "not exportable", target: "/?format=csv", data: []string{"a"}, wantStatus: http.StatusOK, wantType: "application/json"},
+		{name: "not exportable unknown format", target: "/?format=xml", data: []string{"a"}, wantStatus: http.StatusOK, wantType: "application/json"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, tt.target, nil)
+			r = r.WithContext(i18n.WithLanguage(r.Context(), i18n.English))
+			w := httptest.NewRecorder()
+
+			res := Response{Data: tt.data, Exportable: tt.exportable, Filename: "rows"}
+			res.Render(w, r)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			assert.Contains(t, w.Header().Get("Content-Type"), tt.wantType)
+			if tt.wantBody != "" {
+				assert.Equal(t, tt.wantBody, w.Body.String())
+				assert.Equal(t, `attachment; filename="rows.csv"`, w.Header().Get("Content-Disposition"))
+			}
+			if tt.wantStatus == http.StatusNotAcceptable {
+				var body Response
+				assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+				assert.Nil(t, body.Data)
+				assert.Equal(t, "NOT_ACCEPTABLE", body.Error.ErrorCode)
+			}
+		})
+	}
+
+	// errors stay JSON whatever the format
+	r := httptest.NewRequest(http.MethodGet, "/?format=csv", nil)
+	w := httptest.NewRecorder()
+	res := Response{}
+	res.SetError(errCatalogued)
+	res.Render(w, r)
+	assert.Equal(t, http.StatusBadGateway, w.Code)
+	assert.Contains(t, w.Header().Get("Content-Type"), "application/json")
+}
@@ -2,17 +2,79 @@ package validator
 
 import (
 	"errors"
+	"fmt"
 	"reflect"
+	"regexp"
+	"strings"
+	"time"
 
 	"github.com/go-playground/validator/v10"
 )
 
+// DateLayout is the format of the dates checked by the not_past and date_gtefield rules
+const DateLayout = "2006-01-02"
+
 var engine *validator.Validate
 
+// messages holds the error message of every rule added through RegisterValidation
+var messages = map[string]string{}
+
+// Now returns the current time, the not_past rule compares dates against it
+var Now = time.Now
+
+var iataPattern = regexp.MustCompile(`^[A-Z]{3}$`)
+
 // init the decoder
 func init() {
 	// validator.New() is safe to call multiple times since it already use sync.Pool
 	engine = validator.New()
+
+	// report fields by their JSON name when they have one
+	engine.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+		switch name {
+		case "-":
+			return ""
+		case "":
+			return field.Name
+		}
+		return name
+	})
+
+	engine.RegisterValidation("iata", isIATA)
+	engine.RegisterValidation("not_past", isNotPast)
+	engine.RegisterValidation("date_gtefield", isDateGteField)
+	engine.RegisterValidation("chronological", isChronological)
+}
+
+// RegisterValidation adds a custom rule to the engine and the message reported when a field fails it.
+// It must be called before the tag is used, usually from the init function of the package declaring
+// the validated types.
+func RegisterValidation(tag string, fn validator.Func, message string) error {
+	if err := engine.RegisterValidation(tag, fn); err != nil {
+		return err
+	}
+	messages[tag] = message
+	return nil
+}
+
+// FieldError describes a field that failed one of its rules
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Param   string `json:"param,omitempty"`
+	Message string `json:"message"`
+}
+
+// Errors lists every field that failed validation
+type Errors []FieldError
+
+func (e Errors) Error() string {
+	msgs := make([]string, len(e))
+	for i, fe := range e {
+		msgs[i] = fe.Field + " " + fe.Message
+	}
+	return strings.Join(msgs, "; ")
 }
 
 // ValidateStruct validate given struct that have validate tag
@@ -25,13 +87,146 @@ func ValidateStruct(object interface{}) (isValid bool, err error) {
 			err = errors.New("object is empty")
 			return
 		}
-		err = engine.Var(object, "required,dive")
+		err = fieldErrors(engine.Var(object, "required,dive"))
 		isValid = err == nil
 		return
 
 	}
 
-	err = engine.Struct(object)
+	err = fieldErrors(engine.Struct(object))
 	isValid = err == nil
 	return
 }
+
+// fieldErrors converts the errors of the engine into Errors, other errors are returned as is
+func fieldErrors(err error) error {
+	var verrs validator.ValidationErrors
+	if !errors.As(err, &verrs) {
+		return err
+	}
+
+	out := make(Errors, len(verrs))
+	for i, fe := range verrs {
+		out[i] = FieldError{
+			Field:   fieldPath(fe),
+			Rule:    fe.Tag(),
+			Param:   fe.Param(),
+			Message: message(fe),
+		}
+	}
+	return out
+}
+
+// fieldPath drops the struct name from the namespace, e.g. Segments[1].DepartureDate
+func fieldPath(fe validator.FieldError) string {
+	ns := fe.Namespace()
+	if i := strings.Index(ns, "."); i >= 0 {
+		return ns[i+1:]
+	}
+	return ns
+}
+
+func message(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required", "required_without", "required_if":
+		return "is required"
+	case "iata":
+		return "must be a 3-letter uppercase IATA code"
+	case "datetime":
+		return fmt.Sprintf("must be a date in %s format", strings.NewReplacer("2006", "YYYY", "01", "MM", "02", "DD").Replace(fe.Param()))
+	case "not_past":
+		return "cannot be in the past"
+	case "date_gtefield":
+		return fmt.Sprintf("cannot be before %s", fe.Param())
+	case "nefield":
+		return fmt.Sprintf("must be different from %s", fe.Param())
+	case "oneof":
+		return fmt.Sprintf("must be one of: %s", strings.ReplaceAll(fe.Param(), " ", ", "))
+	case "chronological":
+		return fmt.Sprintf("must be in chronological order of %s", fe.Param())
+	case "min":
+		if fe.Kind() == reflect.Slice {
+			return fmt.Sprintf("must have at least %s items", fe.Param())
+		}
+		return fmt.Sprintf("must be at least %s", fe.Param())
+	case "max":
+		if fe.Kind() == reflect.Slice {
+			return fmt.Sprintf("must have at most %s items", fe.Param())
+		}
+		return fmt.Sprintf("must be at most %s", fe.Param())
+	}
+	if msg, ok := messages[fe.Tag()]; ok {
+		return msg
+	}
+	return fmt.Sprintf("failed the %s rule", fe.Tag())
+}
+
+// isIATA checks a 3-letter uppercase airport or city code
+func isIATA(fl validator.FieldLevel) bool {
+	return iataPattern.MatchString(fl.Field().String())
+}
+
+// isNotPast checks that a date is today or later. Malformed dates are left to the datetime rule.
+func isNotPast(fl validator.FieldLevel) bool {
+	date, err := time.Parse(DateLayout, fl.Field().String())
+	if err != nil {
+		return true
+	}
+	return !date.Before(today())
+}
+
+// isDateGteField checks that a date is not before the date held by the sibling field named in
+// the parameter, e.g. date_gtefield=DepartureDate
+func isDateGteField(fl validator.FieldLevel) bool {
+	other, _, _, ok := fl.GetStructFieldOKAdvanced2(fl.Parent(), fl.Param())
+	if !ok || other.Kind() != reflect.String {
+		return false
+	}
+
+	date, err := time.Parse(DateLayout, fl.Field().String())
+	if err != nil {
+		return true
+	}
+	start, err := time.Parse(DateLayout, other.String())
+	if err != nil {
+		return true
+	}
+	return !date.Before(start)
+}
+
+// isChronological checks that the elements of a slice are ordered by the date field named in the
+// parameter, e.g. chronological=DepartureDate. Malformed dates are left to the element rules.
+func isChronological(fl validator.FieldLevel) bool {
+	field := fl.Field()
+	if field.Kind() != reflect.Slice {
+		return false
+	}
+
+	var previous time.Time
+	for i := 0; i < field.Len(); i++ {
+		elem := reflect.Indirect(field.Index(i))
+		if elem.Kind() != reflect.Struct {
+			return false
+		}
+		value := elem.FieldByName(fl.Param())
+		if !value.IsValid() || value.Kind() != reflect.String {
+			return false
+		}
+
+		date, err := time.Parse(DateLayout, value.String())
+		if err != nil {
+			continue
+		}
+		if date.Before(previous) {
+			return false
+		}
+		previous = date
+	}
+	return true
+}
+
+// today is the current calendar date at UTC midnight, like the dates parsed from requests
+func today() time.Time {
+	now := Now()
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+}
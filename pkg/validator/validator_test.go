@@ -1,6 +1,12 @@
 package validator
 
-import "testing"
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+)
 
 func TestValidateStruct(t *testing.T) {
 	type tData struct {
@@ -65,3 +71,75 @@ func TestValidateStruct(t *testing.T) {
 		})
 	}
 }
+
+func TestCustomRules(t *testing.T) {
+	Now = func() time.Time { return time.Date(2025, 12, 10, 15, 0, 0, 0, time.Local) }
+	defer func() { Now = time.Now }()
+
+	type leg struct {
+		Date string `json:"date" validate:"datetime=2006-01-02"`
+	}
+	type trip struct {
+		Code   string `json:"code" validate:"iata"`
+		Depart string `json:"depart" validate:"datetime=2006-01-02,not_past"`
+		Return string `json:"return" validate:"omitempty,date_gtefield=Depart"`
+		Legs   []leg  `json:"legs" validate:"chronological=Date,dive"`
+	}
+	valid := func() trip {
+		return trip{Code: "CGK", Depart: "2025-12-10", Return: "2025-12-10", Legs: []leg{{"2025-12-10"}, {"2025-12-12"}}}
+	}
+
+	tests := []struct {
+		name      string
+		modify    func(*trip)
+		wantField string
+		wantRule  string
+	}{
+		{name: "valid", modify: func(*trip) {}},
+		{name: "lowercase code", modify: func(tr *trip) { tr.Code = "cgk" }, wantField: "code", wantRule: "iata"},
+		{name: "long code", modify: func(tr *trip) { tr.Code = "CGKX" }, wantField: "code", wantRule: "iata"},
+		{name: "malformed date", modify: func(tr *trip) { tr.Depart = "10-12-2025" }, wantField: "depart", wantRule: "datetime"},
+		{name: "past date", modify: func(tr *trip) { tr.Depart, tr.Return = "2025-12-09", "" }, wantField: "depart", wantRule: "not_past"},
+		{name: "return before departure", modify: func(tr *trip) { tr.Return = "2025-12-09" }, wantField: "return", wantRule: "date_gtefield"},
+		{name: "legs out of order", modify: func(tr *trip) { tr.Legs[0].Date = "2025-12-13" }, wantField: "legs", wantRule: "chronological"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tr := valid()
+			tt.modify(&tr)
+
+			_, err := ValidateStruct(tr)
+			if tt.wantRule == "" {
+				if err != nil {
+					t.Fatalf("ValidateStruct() error = %v", err)
+				}
+				return
+			}
+
+			var fields Errors
+			if !errors.As(err, &fields) || len(fields) != 1 {
+				t.Fatalf("ValidateStruct() error = %v, want one field error", err)
+			}
+			if fields[0].Field != tt.wantField || fields[0].Rule != tt.wantRule || fields[0].Message == "" {
+				t.Errorf("ValidateStruct() field error = %+v, want field %s rule %s", fields[0], tt.wantField, tt.wantRule)
+			}
+		})
+	}
+}
+
+func TestRegisterValidation(t *testing.T) {
+	err := RegisterValidation("even", func(fl validator.FieldLevel) bool { return fl.Field().Int()%2 == 0 }, "must be even")
+	if err != nil {
+		t.Fatalf("RegisterValidation() error = %v", err)
+	}
+
+	type tData struct {
+		Count int `validate:"even"`
+	}
+
+	_, err = ValidateStruct(tData{Count: 3})
+	if err == nil || err.Error() != "Count must be even" {
+		t.Errorf("ValidateStruct() error = %v, want %q", err, "Count must be even")
+	}
+}
@@ -0,0 +1,52 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+
+	grpcapi "github.com/elkoshar/bookcabin/api/grpc"
+)
+
+// runGRPCServer will run gRPC server with specified parameter and do gracefull shutdown if receive sigint or sigterm
+func runGRPCServer(grpcsrv *grpcapi.Server, port string) error {
+	stopped := make(chan struct{})
+	served := make(chan struct{})
+	go func() {
+
+		signals := make(chan os.Signal, 1)
+
+		// SIGHUP is for handling upstart reload
+		signal.Notify(signals, os.Interrupt, syscall.SIGTERM, syscall.SIGHUP)
+		defer signal.Stop(signals)
+
+		select {
+		case <-signals:
+		case <-served:
+			close(stopped)
+			return
+		}
+
+		// when received an os signal, wait for running calls to finish.
+		if err := grpcsrv.Shutdown(context.Background()); err != nil {
+			slog.Info(fmt.Sprintf("gRPC server Shutdown: %v", err))
+		}
+		close(stopped)
+	}()
+
+	slog.Info(fmt.Sprintf("gRPC server listening on port %s", port))
+
+	err := grpcsrv.Serve(port)
+	close(served)
+	if err != nil {
+		// Error starting or closing listener:
+		return err
+	}
+
+	<-stopped
+	slog.Info("gRPC server shutdown gracefully")
+	return nil
+}
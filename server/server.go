@@ -1,18 +1,49 @@
 package server
 
 import (
+	"errors"
+	"fmt"
+
 	"github.com/elkoshar/bookcabin/api"
+	grpcapi "github.com/elkoshar/bookcabin/api/grpc"
 	httpapi "github.com/elkoshar/bookcabin/api/http"
 	config "github.com/elkoshar/bookcabin/configs"
+	"github.com/elkoshar/bookcabin/pkg/helpers"
+	"github.com/elkoshar/bookcabin/pkg/idempotency"
 	"github.com/elkoshar/bookcabin/service/aggregator"
 	"github.com/elkoshar/bookcabin/service/airasia"
 	"github.com/elkoshar/bookcabin/service/batik"
+	"github.com/elkoshar/bookcabin/service/booking"
+	"github.com/elkoshar/bookcabin/service/events"
+	"github.com/elkoshar/bookcabin/service/fx"
 	"github.com/elkoshar/bookcabin/service/garuda"
 	"github.com/elkoshar/bookcabin/service/lion"
+	"github.com/elkoshar/bookcabin/service/payment"
+	"github.com/elkoshar/bookcabin/service/searchjob"
+	"github.com/elkoshar/bookcabin/service/storage"
+)
+
+// DevWebhookSecret is the placeholder secret the bundled env files ship with for local runs
+const DevWebhookSecret = "dev-only-insecure-webhook-secret"
+
+var (
+	// ErrMissingWebhookSecret stops startup when no secret is configured to verify payment webhooks, a
+	// well-known secret would let anyone forge them
+	ErrMissingWebhookSecret = errors.New("PAYMENT_WEBHOOK_SECRET must be set")
+
+	// ErrDevWebhookSecret stops a production startup that still uses the published placeholder secret
+	ErrDevWebhookSecret = errors.New("PAYMENT_WEBHOOK_SECRET must be overridden in production")
 )
 
 func InitHttp(config *config.Config) error {
 
+	if config.PaymentWebhookSecret == "" {
+		return ErrMissingWebhookSecret
+	}
+	if config.PaymentWebhookSecret == DevWebhookSecret && helpers.GetEnvString() == helpers.EnvProduction {
+		return ErrDevWebhookSecret
+	}
+
 	garudaProvider := garuda.New(
 		config.GarudaPath,
 	)
@@ -20,8 +51,60 @@ func InitHttp(config *config.Config) error {
 	airAsiaProvider := airasia.New(config.AirAsiaPath)
 	batikProvider := batik.New(config.BatikPath)
 
+	store, err := storage.New(config.StorageDriver, config.StoragePath)
+	if err != nil {
+		return err
+	}
+	pruner := storage.NewSearchPruner(store, config.SearchRetention, config.SearchPruneInterval)
+	defer pruner.Close()
+
+	var rates api.FXRateProvider
+	if config.FXRatesPath != "" {
+		fileRates, err := fx.NewFile(config.FXRatesPath, config.FXRefreshInterval)
+		if err != nil {
+			return err
+		}
+		defer fileRates.Close()
+		rates = fileRates
+	}
+
 	aggregator := aggregator.NewAggregator(
 		config.AggregatorTimeout,
+		store,
+		rates,
+		garudaProvider,
+		lionProvider,
+		airAsiaProvider,
+		batikProvider,
+	)
+	defer aggregator.Close()
+
+	searchJobs := searchjob.NewRunner(
+		aggregator,
+		config.SearchJobWorkers,
+		config.SearchJobQueueSize,
+		config.SearchJobTTL,
+	)
+	defer searchJobs.Close()
+
+	// the fake gateway approves test cards and lets anyone complete a 3DS challenge, so production
+	// gets no gateway until a real one is wired in
+	var (
+		gateway    api.PaymentGateway = payment.Unavailable{}
+		challenger api.PaymentChallenger
+	)
+	if helpers.GetEnvString() != helpers.EnvProduction {
+		fake := payment.NewFake(config.PaymentWebhookSecret)
+		gateway, challenger = fake, fake
+	}
+
+	booking := booking.NewBooking(
+		config.BookingHoldTTL,
+		config.BookingPriceTolerance,
+		aggregator,
+		store,
+		events.NewBus(),
+		gateway,
 		garudaProvider,
 		lionProvider,
 		airAsiaProvider,
@@ -32,7 +115,36 @@ func InitHttp(config *config.Config) error {
 		Cfg:         config,
 		HealthCheck: api.HealthChecker{},
 		Aggregator:  aggregator,
+		Booking:     booking,
+		SearchJobs:  searchJobs,
+		Idempotency: idempotency.NewMemoryStore(),
+		Challenger:  challenger,
 	}
 
-	return runHTTPServer(httpserver, config.ServerHttpPort)
+	grpcserver := grpcapi.NewServer(config, aggregator)
+
+	grpcErr := make(chan error, 1)
+	go func() {
+		grpcErr <- runGRPCServer(grpcserver, config.ServerGrpcPort)
+	}()
+
+	httpErr := make(chan error, 1)
+	go func() {
+		httpErr <- runHTTPServer(httpserver, config.ServerHttpPort)
+	}()
+
+	// a gRPC server that fails, e.g. because its port is taken, stops the service instead of
+	// leaving it running with HTTP only
+	select {
+	case err := <-grpcErr:
+		if err != nil {
+			return fmt.Errorf("gRPC server: %w", err)
+		}
+		return <-httpErr
+	case err := <-httpErr:
+		if err != nil {
+			return err
+		}
+		return <-grpcErr
+	}
 }
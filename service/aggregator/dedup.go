@@ -0,0 +1,88 @@
+package aggregator
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/elkoshar/bookcabin/service"
+)
+
+// normalizeFlightNumber turns "ga 0400", "GA-400" and "GA400" into the same "GA400"
+func normalizeFlightNumber(number string) string {
+	var b strings.Builder
+	for _, r := range strings.ToUpper(number) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		}
+	}
+	clean := b.String()
+	if len(clean) <= 2 {
+		return clean
+	}
+
+	digits := strings.TrimLeft(clean[2:], "0")
+	if digits == "" {
+		digits = "0"
+	}
+	return clean[:2] + digits
+}
+
+// flightKey identifies the physical flight an offer sells, or "" when it cannot be identified. It falls
+// back to the marketing carrier and flight number when the provider did not report the operating ones,
+// which today is every provider, so codeshares of the same flight are only merged once an adapter maps them
+func flightKey(f service.UnifiedFlight) string {
+	carrier := f.OperatingCarrier
+	if carrier == "" {
+		carrier = f.Airline.Code
+	}
+	number := f.OperatingFlightNumber
+	if number == "" {
+		number = f.FlightNumber
+	}
+	if number == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s|%s|%d", strings.ToUpper(carrier), normalizeFlightNumber(number), f.Departure.Timestamp)
+}
+
+// deduplicate merges offers for the same physical flight, keeping the cheapest one and
+// attaching the others as alternatives. It returns how many duplicates were merged.
+func deduplicate(flights []service.UnifiedFlight) ([]service.UnifiedFlight, int) {
+	sort.SliceStable(flights, func(i, j int) bool {
+		if flights[i].Price.Amount == flights[j].Price.Amount {
+			return flights[i].Provider < flights[j].Provider
+		}
+		return flights[i].Price.Amount < flights[j].Price.Amount
+	})
+
+	kept := make([]service.UnifiedFlight, 0, len(flights))
+	index := make(map[string]int, len(flights))
+	merged := 0
+
+	for _, f := range flights {
+		key := flightKey(f)
+		if key == "" {
+			kept = append(kept, f)
+			continue
+		}
+
+		i, seen := index[key]
+		if !seen {
+			index[key] = len(kept)
+			kept = append(kept, f)
+			continue
+		}
+
+		kept[i].AlternativeOffers = append(kept[i].AlternativeOffers, service.AlternativeOffer{
+			ID:           f.ID,
+			Provider:     f.Provider,
+			FlightNumber: f.FlightNumber,
+			Price:        f.Price,
+		})
+		merged++
+	}
+
+	return kept, merged
+}
@@ -0,0 +1,28 @@
+package aggregator
+
+import (
+	"context"
+
+	"github.com/elkoshar/bookcabin/service"
+	"github.com/elkoshar/bookcabin/service/farerules"
+)
+
+// FareRules returns the full change and refund conditions of an offer
+func (s *FlightAggregator) FareRules(ctx context.Context, id string) (service.FareRules, error) {
+	flight, err := s.GetFlight(ctx, id)
+	if err != nil {
+		return service.FareRules{}, err
+	}
+	return farerules.Details(flight), nil
+}
+
+// refundableOnly keeps the flights whose fare rules allow a refund
+func refundableOnly(flights []service.UnifiedFlight) []service.UnifiedFlight {
+	filtered := flights[:0]
+	for _, f := range flights {
+		if f.FareRules != nil && f.FareRules.Refundable {
+			filtered = append(filtered, f)
+		}
+	}
+	return filtered
+}
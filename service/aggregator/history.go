@@ -0,0 +1,193 @@
+package aggregator
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/elkoshar/bookcabin/api"
+	"github.com/elkoshar/bookcabin/service"
+)
+
+// priceQueueSize is how many batches of observations can wait for the price writer before new ones are dropped
+const priceQueueSize = 256
+
+// saveSearch stores the session and result snapshot of a search. Storage failures are logged
+// and do not fail the search, the results were already fetched.
+func (s *FlightAggregator) saveSearch(ctx context.Context, tripType string, resp service.SearchResponse) {
+	if s.store == nil {
+		return
+	}
+
+	now := resp.GeneratedAt
+	session := service.SearchSession{
+		ID:           resp.SearchID,
+		Criteria:     resp.Criteria,
+		TripType:     tripType,
+		TotalResults: resp.Metadata.TotalResults,
+		CreatedAt:    now,
+	}
+	if err := s.store.SaveSearchSession(ctx, session); err != nil {
+		slog.Error(fmt.Sprintf("[Aggregator] Failed to save search session %s: %v", resp.SearchID, err))
+		return
+	}
+
+	snapshot := service.SearchSnapshot{SearchID: resp.SearchID, Response: resp, CreatedAt: now}
+	if err := s.store.SaveSnapshot(ctx, snapshot); err != nil {
+		slog.Error(fmt.Sprintf("[Aggregator] Failed to save search snapshot %s: %v", resp.SearchID, err))
+	}
+}
+
+// recordPrices queues the price of every offer, including merged alternatives, for its price history
+func (s *FlightAggregator) recordPrices(ctx context.Context, flights []service.UnifiedFlight) {
+	if s.prices == nil || len(flights) == 0 {
+		return
+	}
+
+	now := time.Now()
+	points := make([]service.PricePoint, 0, len(flights))
+	for _, f := range flights {
+		points = append(points, service.PricePoint{OfferID: f.ID, Amount: f.Price.Amount, Currency: f.Price.Currency, ObservedAt: now})
+		for _, alt := range f.AlternativeOffers {
+			points = append(points, service.PricePoint{OfferID: alt.ID, Amount: alt.Price.Amount, Currency: alt.Price.Currency, ObservedAt: now})
+		}
+	}
+
+	s.prices.enqueue(points)
+}
+
+// priceWriter records price observations in the background, so a search does not wait for the
+// price history of every offer it returned to be rewritten. Batches that queue up while a write is
+// in progress are written together.
+type priceWriter struct {
+	store api.Storage
+	queue chan []service.PricePoint
+
+	// pending holds the newest queued observation of each offer until it is written, so a reprice
+	// right after a search still compares against the quote the search returned
+	mu      sync.Mutex
+	pending map[string]service.PricePoint
+
+	ctx  context.Context
+	stop context.CancelFunc
+	done chan struct{}
+}
+
+func newPriceWriter(store api.Storage) *priceWriter {
+	ctx, stop := context.WithCancel(context.Background())
+	w := &priceWriter{
+		store:   store,
+		queue:   make(chan []service.PricePoint, priceQueueSize),
+		pending: make(map[string]service.PricePoint),
+		ctx:     ctx,
+		stop:    stop,
+		done:    make(chan struct{}),
+	}
+	go w.run()
+	return w
+}
+
+// enqueue hands points to the writer. The price history is best effort, points are dropped rather
+// than slowing the caller down when the queue is full or the writer is closed.
+func (w *priceWriter) enqueue(points []service.PricePoint) {
+	if w.ctx.Err() != nil {
+		return
+	}
+
+	w.mu.Lock()
+	for _, p := range points {
+		w.pending[p.OfferID] = p
+	}
+	w.mu.Unlock()
+
+	select {
+	case w.queue <- points:
+	default:
+		w.settle(points)
+		slog.Warn(fmt.Sprintf("[Aggregator] Price history queue is full, dropped %d observations", len(points)))
+	}
+}
+
+// quote returns the newest observation of an offer that is still waiting to be written
+func (w *priceWriter) quote(offerID string) (service.PricePoint, bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	p, ok := w.pending[offerID]
+	return p, ok
+}
+
+// settle forgets the pending observations among points, unless a newer one was queued meanwhile
+func (w *priceWriter) settle(points []service.PricePoint) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for _, p := range points {
+		if w.pending[p.OfferID] == p {
+			delete(w.pending, p.OfferID)
+		}
+	}
+}
+
+func (w *priceWriter) run() {
+	defer close(w.done)
+	for {
+		select {
+		case <-w.ctx.Done():
+			w.write(w.drain(nil))
+			return
+		case points := <-w.queue:
+			w.write(w.drain(points))
+		}
+	}
+}
+
+// drain appends every batch already waiting in the queue to points
+func (w *priceWriter) drain(points []service.PricePoint) []service.PricePoint {
+	for {
+		select {
+		case more := <-w.queue:
+			points = append(points, more...)
+		default:
+			return points
+		}
+	}
+}
+
+func (w *priceWriter) write(points []service.PricePoint) {
+	if len(points) == 0 {
+		return
+	}
+	if err := w.store.RecordPrices(context.Background(), points...); err != nil {
+		slog.Error(fmt.Sprintf("[Aggregator] Failed to record price history: %v", err))
+	}
+	w.settle(points)
+}
+
+// close stops the writer once the observations already queued are written
+func (w *priceWriter) close() {
+	w.stop()
+	<-w.done
+}
+
+// lastQuote returns the most recent price recorded for an offer, or 0 when it was never quoted
+func (s *FlightAggregator) lastQuote(ctx context.Context, offerID string) float64 {
+	if s.store == nil {
+		return 0
+	}
+	if p, ok := s.prices.quote(offerID); ok {
+		return p.Amount
+	}
+
+	history, err := s.store.PriceHistory(ctx, offerID)
+	if err != nil {
+		slog.Error(fmt.Sprintf("[Aggregator] Failed to read price history of %s: %v", offerID, err))
+		return 0
+	}
+	if len(history) == 0 {
+		return 0
+	}
+	return history[len(history)-1].Amount
+}
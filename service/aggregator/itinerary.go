@@ -0,0 +1,135 @@
+package aggregator
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/elkoshar/bookcabin/service"
+)
+
+const (
+	dateLayout = "2006-01-02"
+
+	// minStopoverDuration is the shortest stay at an intermediate city that counts as a stopover
+	minStopoverDuration = 24 * time.Hour
+)
+
+// resolveTripType returns the explicit trip type or infers it from the criteria shape
+func resolveTripType(criteria service.SearchCriteria) string {
+	if criteria.TripType != "" {
+		return strings.ToLower(criteria.TripType)
+	}
+	if len(criteria.Segments) > 0 {
+		return service.TripTypeMultiCity
+	}
+	if criteria.ReturnDate != "" {
+		return service.TripTypeRoundTrip
+	}
+	return service.TripTypeOneWay
+}
+
+// validateItinerary checks that the criteria carry the fields required by the trip type
+func validateItinerary(tripType string, criteria service.SearchCriteria) error {
+	switch tripType {
+	case service.TripTypeOneWay, service.TripTypeRoundTrip:
+		if len(criteria.Segments) > 0 {
+			return fmt.Errorf("%s trip cannot have segments", tripType)
+		}
+		if criteria.Origin == criteria.Destination {
+			return fmt.Errorf("origin and destination cannot be the same")
+		}
+		if tripType == service.TripTypeOneWay {
+			if criteria.ReturnDate != "" {
+				return fmt.Errorf("one_way trip cannot have a return date")
+			}
+			return nil
+		}
+		if criteria.ReturnDate == "" {
+			return fmt.Errorf("round_trip requires a return date")
+		}
+		if criteria.ReturnDate < criteria.DepartureDate {
+			return fmt.Errorf("return date cannot be before departure date")
+		}
+		return nil
+
+	case service.TripTypeMultiCity:
+		if len(criteria.Segments) == 0 {
+			return fmt.Errorf("multi_city trip requires at least one segment")
+		}
+		return nil
+
+	case service.TripTypeOpenJaw:
+		if len(criteria.Segments) != 2 {
+			return fmt.Errorf("open_jaw trip requires exactly 2 segments")
+		}
+		out, in := criteria.Segments[0], criteria.Segments[1]
+		if out.Destination == in.Origin && out.Origin == in.Destination {
+			return fmt.Errorf("open_jaw trip must return from or to a different airport, use round_trip instead")
+		}
+		if out.Destination != in.Origin && out.Origin != in.Destination {
+			return fmt.Errorf("open_jaw trip must share either the turnaround or the home airport")
+		}
+		return validateSegmentDates(criteria.Segments, 0)
+
+	case service.TripTypeStopover:
+		if len(criteria.Segments) < 2 {
+			return fmt.Errorf("stopover trip requires at least 2 segments")
+		}
+		for i := 1; i < len(criteria.Segments); i++ {
+			if criteria.Segments[i].Origin != criteria.Segments[i-1].Destination {
+				return fmt.Errorf("stopover segment %d must depart from %s", i+1, criteria.Segments[i-1].Destination)
+			}
+		}
+		return validateSegmentDates(criteria.Segments, minStopoverDuration)
+	}
+
+	return fmt.Errorf("unsupported trip type %q", tripType)
+}
+
+// validateSegmentDates checks every segment departs at least minGap after the previous one
+func validateSegmentDates(segments []service.RouteSegment, minGap time.Duration) error {
+	var prev time.Time
+	for i, seg := range segments {
+		if seg.Origin == seg.Destination {
+			return fmt.Errorf("segment %d origin and destination cannot be the same", i+1)
+		}
+
+		date, err := time.Parse(dateLayout, seg.DepartureDate)
+		if err != nil {
+			return fmt.Errorf("segment %d has invalid departure date %q", i+1, seg.DepartureDate)
+		}
+		if i > 0 && date.Before(prev) {
+			return fmt.Errorf("segment %d cannot depart before segment %d", i+1, i)
+		}
+		if i > 0 && date.Sub(prev) < minGap {
+			return fmt.Errorf("segment %d departs too soon after segment %d", i+1, i)
+		}
+		prev = date
+	}
+	return nil
+}
+
+// departingAfter keeps the flights of a leg that leave at least minGap after the
+// earliest arrival of the previous leg, so every result can be combined into an itinerary
+func departingAfter(previous, next []service.UnifiedFlight, minGap time.Duration) []service.UnifiedFlight {
+	if len(previous) == 0 {
+		return next
+	}
+
+	earliest := previous[0].Arrival.Timestamp
+	for _, f := range previous[1:] {
+		if f.Arrival.Timestamp < earliest {
+			earliest = f.Arrival.Timestamp
+		}
+	}
+
+	cutoff := earliest + int64(minGap.Seconds())
+	filtered := make([]service.UnifiedFlight, 0, len(next))
+	for _, f := range next {
+		if f.Departure.Timestamp >= cutoff {
+			filtered = append(filtered, f)
+		}
+	}
+	return filtered
+}
@@ -0,0 +1,76 @@
+package aggregator
+
+import (
+	"github.com/elkoshar/bookcabin/service"
+)
+
+// dominates reports whether a is at least as cheap and as fast as b and strictly better in one of them
+func dominates(a, b service.UnifiedFlight) bool {
+	if a.Price.Amount > b.Price.Amount || a.Duration.TotalMinutes > b.Duration.TotalMinutes {
+		return false
+	}
+	return a.Price.Amount < b.Price.Amount || a.Duration.TotalMinutes < b.Duration.TotalMinutes
+}
+
+// markParetoFront flags every flight that no other flight dominates on price and duration
+func markParetoFront(flights []service.UnifiedFlight) {
+	for i := range flights {
+		flights[i].ParetoOptimal = true
+		for j := range flights {
+			if i != j && dominates(flights[j], flights[i]) {
+				flights[i].ParetoOptimal = false
+				break
+			}
+		}
+	}
+}
+
+// applyLabels tags the standout flights of an already scored and sorted result set.
+// Ties go to the flight ranked first, so each label is attached exactly once.
+func applyLabels(flights []service.UnifiedFlight) {
+	if len(flights) == 0 {
+		return
+	}
+
+	cheapest, fastest, earliest := 0, 0, 0
+	bestValue, directCheapest := -1, -1
+
+	for i, f := range flights {
+		if f.Price.Amount < flights[cheapest].Price.Amount {
+			cheapest = i
+		}
+		if f.Duration.TotalMinutes < flights[fastest].Duration.TotalMinutes {
+			fastest = i
+		}
+		if f.Departure.Timestamp < flights[earliest].Departure.Timestamp {
+			earliest = i
+		}
+		if f.ParetoOptimal && (bestValue < 0 || f.Score < flights[bestValue].Score) {
+			bestValue = i
+		}
+		if f.Stops == 0 && (directCheapest < 0 || f.Price.Amount < flights[directCheapest].Price.Amount) {
+			directCheapest = i
+		}
+	}
+
+	flights[cheapest].Labels = append(flights[cheapest].Labels, service.LabelCheapest)
+	flights[fastest].Labels = append(flights[fastest].Labels, service.LabelFastest)
+	if bestValue >= 0 {
+		flights[bestValue].Labels = append(flights[bestValue].Labels, service.LabelBestValue)
+	}
+	flights[earliest].Labels = append(flights[earliest].Labels, service.LabelEarliest)
+	if directCheapest >= 0 {
+		flights[directCheapest].Labels = append(flights[directCheapest].Labels, service.LabelDirectCheapest)
+	}
+}
+
+// dropDominated keeps only the Pareto-optimal flights and returns how many were removed
+func dropDominated(flights []service.UnifiedFlight) ([]service.UnifiedFlight, int) {
+	kept := make([]service.UnifiedFlight, 0, len(flights))
+	for _, f := range flights {
+		if f.ParetoOptimal {
+			kept = append(kept, f)
+		}
+	}
+	return kept, len(flights) - len(kept)
+}
@@ -2,45 +2,181 @@ package aggregator
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"math"
 	"math/rand"
 	"sort"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/elkoshar/bookcabin/api"
 	"github.com/elkoshar/bookcabin/pkg/helpers"
 	"github.com/elkoshar/bookcabin/service"
+	"github.com/elkoshar/bookcabin/service/ancillary"
 )
 
 type FlightAggregator struct {
 	providers []api.FlightProvider
 	timeout   time.Duration
+
+	// store keeps search sessions, their results and the price history Reprice compares against
+	store api.Storage
+
+	// prices writes the price history to store off the search path
+	prices *priceWriter
+
+	// rates converts search results into the currency asked for, searches in IDR work without it
+	rates api.FXRateProvider
 }
 
-func NewAggregator(timeout time.Duration, providers ...api.FlightProvider) *FlightAggregator {
-	return &FlightAggregator{
+func NewAggregator(timeout time.Duration, store api.Storage, rates api.FXRateProvider, providers ...api.FlightProvider) *FlightAggregator {
+	s := &FlightAggregator{
 		providers: providers,
 		timeout:   timeout,
+		store:     store,
+		rates:     rates,
+	}
+	if store != nil {
+		s.prices = newPriceWriter(store)
+	}
+	return s
+}
+
+// Close writes the price observations still queued and stops the price writer
+func (s *FlightAggregator) Close() {
+	if s.prices != nil {
+		s.prices.close()
 	}
 }
 
 func (s *FlightAggregator) SearchAll(ctx context.Context, criteria service.SearchCriteria) (service.SearchResponse, error) {
 
-	if len(criteria.Segments) > 0 {
-		return s.searchMultiCity(ctx, criteria)
+	tripType := resolveTripType(criteria)
+	if err := validateItinerary(tripType, criteria); err != nil {
+		return service.SearchResponse{}, fmt.Errorf("%w: %w", service.ErrInvalidItinerary, err)
 	}
 
-	startTime := time.Now()
+	rates, err := s.exchangeRates(ctx, criteria.Currency)
+	if err != nil {
+		return service.SearchResponse{}, err
+	}
+
+	resp, err := s.search(ctx, tripType, criteria, nil)
+	if err != nil {
+		return service.SearchResponse{}, err
+	}
+
+	resp.SearchID = helpers.GenerateSearchID()
+	resp.GeneratedAt = time.Now()
+	s.saveSearch(ctx, tripType, resp)
+
+	if criteria.Currency == "" {
+		return resp, nil
+	}
+	return resp.Convert(rates, criteria.Currency)
+}
+
+// SearchStream starts a search in the background and returns a channel that receives the results of each
+// provider as soon as they arrive, then a summary event with the final ranking. The channel is closed after
+// the summary, or earlier when ctx is done.
+func (s *FlightAggregator) SearchStream(ctx context.Context, criteria service.SearchCriteria) (<-chan service.SearchEvent, error) {
+
+	tripType := resolveTripType(criteria)
+	if err := validateItinerary(tripType, criteria); err != nil {
+		return nil, fmt.Errorf("%w: %w", service.ErrInvalidItinerary, err)
+	}
+
+	rates, err := s.exchangeRates(ctx, criteria.Currency)
+	if err != nil {
+		return nil, err
+	}
 
-	if criteria.Origin == criteria.Destination {
-		return service.SearchResponse{}, fmt.Errorf("origin and destination cannot be the same")
+	events := make(chan service.SearchEvent)
+	send := func(event service.SearchEvent) {
+		if criteria.Currency != "" {
+			converted, err := event.Convert(rates, criteria.Currency)
+			if err != nil {
+				converted = service.SearchEvent{Type: service.SearchEventError, Error: err.Error(), Err: err}
+			}
+			event = converted
+		}
+
+		select {
+		case events <- event:
+		case <-ctx.Done():
+		}
 	}
 
+	go func() {
+		defer close(events)
+
+		resp, err := s.search(ctx, tripType, criteria, send)
+		if err != nil {
+			send(service.SearchEvent{Type: service.SearchEventError, Error: err.Error(), Err: err})
+			return
+		}
+
+		resp.SearchID = helpers.GenerateSearchID()
+		resp.GeneratedAt = time.Now()
+		s.saveSearch(ctx, tripType, resp)
+
+		send(service.SearchEvent{Type: service.SearchEventSummary, Summary: &resp})
+	}()
+
+	return events, nil
+}
+
+// exchangeRates returns the rates that convert IDR fares into currency. Searches in IDR need no
+// rates and get the zero FXRates.
+func (s *FlightAggregator) exchangeRates(ctx context.Context, currency string) (service.FXRates, error) {
+	if currency == "" || currency == service.CurrencyIDR {
+		return service.FXRates{}, nil
+	}
+	if s.rates == nil {
+		return service.FXRates{}, service.ErrRatesUnavailable
+	}
+
+	rates, err := s.rates.Rates(ctx)
+	if err != nil {
+		return service.FXRates{}, fmt.Errorf("%w: %w", service.ErrRatesUnavailable, err)
+	}
+	if _, err := rates.Rate(service.CurrencyIDR, currency); err != nil {
+		return service.FXRates{}, err
+	}
+	return rates, nil
+}
+
+// reportFunc receives provider events while a search runs, it is nil when only the final response is needed
+type reportFunc func(service.SearchEvent)
+
+// forLeg tags every reported event with the index of the itinerary leg being searched
+func (r reportFunc) forLeg(leg int) reportFunc {
+	if r == nil {
+		return nil
+	}
+	return func(event service.SearchEvent) {
+		event.Leg = leg
+		r(event)
+	}
+}
+
+func (s *FlightAggregator) search(ctx context.Context, tripType string, criteria service.SearchCriteria, report reportFunc) (service.SearchResponse, error) {
+	switch tripType {
+	case service.TripTypeMultiCity:
+		return s.searchMultiCity(ctx, criteria, report)
+	case service.TripTypeOpenJaw:
+		return s.searchOpenJaw(ctx, criteria, report)
+	case service.TripTypeStopover:
+		return s.searchStopover(ctx, criteria, report)
+	}
+
+	startTime := time.Now()
+
 	slog.Info(fmt.Sprintf("[Aggregator] Searching DEPART: %s -> %s on %s", criteria.Origin, criteria.Destination, criteria.DepartureDate))
-	departFlights, departMeta, err := s.executeSearch(ctx, criteria)
+	departFlights, departMeta, err := s.executeSearch(ctx, criteria, report.forLeg(0))
 	if err != nil {
 		return service.SearchResponse{}, err
 	}
@@ -55,23 +191,21 @@ func (s *FlightAggregator) SearchAll(ctx context.Context, criteria service.Searc
 		returnCriteria.Origin = criteria.Destination
 		returnCriteria.Destination = criteria.Origin
 		returnCriteria.DepartureDate = criteria.ReturnDate
-		returnFlights, returnMeta, err = s.executeSearch(ctx, returnCriteria)
+		returnFlights, returnMeta, err = s.executeSearch(ctx, returnCriteria, report.forLeg(1))
 		if err != nil {
 			slog.Error(fmt.Sprintf("Failed to fetch return flights: %v", err))
 		}
 	}
 
 	totalResults := len(departFlights) + len(returnFlights)
-	totalQueried := len(s.providers)
-	if criteria.ReturnDate != "" {
-		totalQueried = len(s.providers) * 2
-	}
 
 	finalMetadata := service.Metadata{
 		TotalResults:       totalResults,
-		ProvidersQueried:   totalQueried,
+		ProvidersQueried:   departMeta.ProvidersQueried + returnMeta.ProvidersQueried,
 		ProvidersSucceeded: departMeta.ProvidersSucceeded + returnMeta.ProvidersSucceeded,
 		ProvidersFailed:    departMeta.ProvidersFailed + returnMeta.ProvidersFailed,
+		DominatedRemoved:   departMeta.DominatedRemoved + returnMeta.DominatedRemoved,
+		DuplicatesMerged:   departMeta.DuplicatesMerged + returnMeta.DuplicatesMerged,
 		SearchTimeMs:       time.Since(startTime).Milliseconds(),
 	}
 
@@ -83,6 +217,123 @@ func (s *FlightAggregator) SearchAll(ctx context.Context, criteria service.Searc
 	}, nil
 }
 
+// GetFlight returns the current details of an offer by re-querying only the provider encoded in its ID
+func (s *FlightAggregator) GetFlight(ctx context.Context, id string) (service.UnifiedFlight, error) {
+	key, err := service.ParseOfferID(id)
+	if err != nil {
+		return service.UnifiedFlight{}, err
+	}
+
+	provider := s.provider(key.Provider)
+	if provider == nil {
+		return service.UnifiedFlight{}, service.ErrOfferNotFound
+	}
+
+	ctxWithTimeout, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	flights, err := s.searchProcess(ctxWithTimeout, provider, key.Criteria())
+	if err != nil {
+		return service.UnifiedFlight{}, fmt.Errorf("provider %s failed: %w", provider.Name(), err)
+	}
+
+	for _, f := range flights {
+		if f.ID == id {
+			f.Price.Formatted = helpers.FormatIDR(f.Price.Amount)
+			f.Score = calculateScore(f)
+			return f, nil
+		}
+	}
+	return service.UnifiedFlight{}, service.ErrOfferNotFound
+}
+
+// Reprice re-queries the provider of an offer and reports whether it is unchanged, changed in price or sold out
+func (s *FlightAggregator) Reprice(ctx context.Context, req service.RepriceRequest) (service.RepriceResult, error) {
+	passengers := req.Passengers
+	if passengers == 0 {
+		passengers = 1
+	}
+
+	oldAmount := req.ExpectedAmount
+	if oldAmount == 0 {
+		oldAmount = s.lastQuote(ctx, req.ID)
+	}
+
+	result := service.RepriceResult{
+		ID:        req.ID,
+		OldAmount: oldAmount,
+	}
+
+	flight, err := s.GetFlight(ctx, req.ID)
+	if errors.Is(err, service.ErrOfferNotFound) {
+		result.Status = service.RepriceStatusSoldOut
+		return result, nil
+	}
+	if err != nil {
+		return service.RepriceResult{}, err
+	}
+
+	result.NewAmount = flight.Price.Amount
+	result.Currency = flight.Price.Currency
+	result.Flight = &flight
+
+	switch {
+	case flight.AvailableSeats < passengers:
+		result.Status = service.RepriceStatusSoldOut
+	case oldAmount != 0 && oldAmount != flight.Price.Amount:
+		result.Status = service.RepriceStatusPriceChanged
+	default:
+		result.Status = service.RepriceStatusUnchanged
+		result.OldAmount = flight.Price.Amount
+	}
+
+	s.recordPrices(ctx, []service.UnifiedFlight{flight})
+	return result, nil
+}
+
+// SeatMap returns the seat map of an offer from its provider, when the provider supports seat maps
+func (s *FlightAggregator) SeatMap(ctx context.Context, id string) (service.SeatMap, error) {
+	key, err := service.ParseOfferID(id)
+	if err != nil {
+		return service.SeatMap{}, err
+	}
+
+	provider := s.provider(key.Provider)
+	if provider == nil {
+		return service.SeatMap{}, service.ErrOfferNotFound
+	}
+
+	seatMapProvider, ok := provider.(api.SeatMapProvider)
+	if !ok {
+		return service.SeatMap{}, service.ErrSeatMapUnavailable
+	}
+
+	ctxWithTimeout, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	return seatMapProvider.SeatMap(ctxWithTimeout, id)
+}
+
+// Ancillaries returns the optional products that can be added to an offer at its current state
+func (s *FlightAggregator) Ancillaries(ctx context.Context, id string) (service.AncillaryCatalogue, error) {
+	flight, err := s.GetFlight(ctx, id)
+	if err != nil {
+		return service.AncillaryCatalogue{}, err
+	}
+
+	return service.AncillaryCatalogue{OfferID: id, Ancillaries: ancillary.Catalogue(flight)}, nil
+}
+
+// provider looks up a configured provider by name
+func (s *FlightAggregator) provider(name string) api.FlightProvider {
+	for _, p := range s.providers {
+		if p.Name() == name {
+			return p
+		}
+	}
+	return nil
+}
+
 func (s *FlightAggregator) searchProcess(ctx context.Context, p api.FlightProvider, c service.SearchCriteria) ([]service.UnifiedFlight, error) {
 	maxRetries := 3
 	baseDelay := 100 * time.Millisecond
@@ -126,9 +377,43 @@ func calculateScore(f service.UnifiedFlight) float64 {
 	return score
 }
 
-func (s *FlightAggregator) executeSearch(ctx context.Context, criteria service.SearchCriteria) ([]service.UnifiedFlight, service.Metadata, error) {
-	resultChan := make(chan []service.UnifiedFlight, len(s.providers))
-	errorChan := make(chan error, len(s.providers))
+// airportPair is one concrete origin/destination combination searched for a requested route
+type airportPair struct {
+	origin      helpers.NearbyAirport
+	destination helpers.NearbyAirport
+}
+
+// airportPairs expands the requested origin and destination into every airport pair to query
+func airportPairs(criteria service.SearchCriteria) []airportPair {
+	origins := helpers.ExpandAirports(criteria.Origin, criteria.NearbyRadiusKm)
+	destinations := helpers.ExpandAirports(criteria.Destination, criteria.NearbyRadiusKm)
+
+	var pairs []airportPair
+	for _, o := range origins {
+		for _, d := range destinations {
+			if o.Code == d.Code {
+				continue
+			}
+			pairs = append(pairs, airportPair{origin: o, destination: d})
+		}
+	}
+	return pairs
+}
+
+// providerResult is the outcome of querying one provider for one airport pair
+type providerResult struct {
+	provider api.FlightProvider
+	flights  []service.UnifiedFlight
+	err      error
+}
+
+func (s *FlightAggregator) executeSearch(ctx context.Context, criteria service.SearchCriteria, report reportFunc) ([]service.UnifiedFlight, service.Metadata, error) {
+	pairs := airportPairs(criteria)
+	// airport codes are expanded in upper case, so a lowercase request for a single airport is not an expansion
+	expanded := len(pairs) > 1 || (len(pairs) == 1 && (!strings.EqualFold(pairs[0].origin.Code, criteria.Origin) || !strings.EqualFold(pairs[0].destination.Code, criteria.Destination)))
+
+	total := len(s.providers) * len(pairs)
+	resultChan := make(chan providerResult, total)
 	var wg sync.WaitGroup
 
 	ctxWithTimeout, cancel := context.WithTimeout(ctx, s.timeout)
@@ -136,39 +421,88 @@ func (s *FlightAggregator) executeSearch(ctx context.Context, criteria service.S
 
 	providersSucceeded := 0
 	providersFailed := 0
-
-	for _, p := range s.providers {
-		wg.Add(1)
-		go func(prov api.FlightProvider) {
-			defer wg.Done()
-
-			time.Sleep(time.Duration(rand.Intn(50)) * time.Millisecond)
-
-			flights, err := s.searchProcess(ctxWithTimeout, prov, criteria)
-			if err != nil {
-				slog.Error(fmt.Sprintf("Provider %s failed: %v", prov.Name(), err))
-				errorChan <- err
-				return
-			}
-			resultChan <- flights
-		}(p)
+	providersTimedOut := 0
+
+	for _, pair := range pairs {
+		pairCriteria := criteria
+		pairCriteria.Origin = pair.origin.Code
+		pairCriteria.Destination = pair.destination.Code
+
+		for _, p := range s.providers {
+			wg.Add(1)
+			go func(prov api.FlightProvider, c service.SearchCriteria, pair airportPair) {
+				defer wg.Done()
+
+				time.Sleep(time.Duration(rand.Intn(50)) * time.Millisecond)
+
+				flights, err := s.searchProcess(ctxWithTimeout, prov, c)
+				if err != nil {
+					slog.Error(fmt.Sprintf("Provider %s failed: %v", prov.Name(), err))
+					resultChan <- providerResult{provider: prov, err: err}
+					return
+				}
+
+				if expanded {
+					flights = append([]service.UnifiedFlight(nil), flights...)
+					for i := range flights {
+						flights[i].AirportMatch = &service.AirportMatch{
+							RequestedOrigin:       criteria.Origin,
+							RequestedDestination:  criteria.Destination,
+							Origin:                pair.origin.Code,
+							Destination:           pair.destination.Code,
+							OriginDistanceKm:      pair.origin.DistanceKm,
+							DestinationDistanceKm: pair.destination.DistanceKm,
+						}
+					}
+				}
+				resultChan <- providerResult{provider: prov, flights: flights}
+			}(p, pairCriteria, pair)
+		}
 	}
 
 	go func() {
 		wg.Wait()
 		close(resultChan)
-		close(errorChan)
 	}()
 
 	var allFlights []service.UnifiedFlight
 	for res := range resultChan {
-		allFlights = append(allFlights, res...)
+		if res.err != nil {
+			providersFailed++
+			if errors.Is(res.err, context.DeadlineExceeded) {
+				providersTimedOut++
+			}
+			if report != nil {
+				report(service.SearchEvent{Type: service.SearchEventProviderError, Provider: res.provider.Name(), Error: res.err.Error()})
+			}
+			continue
+		}
 		providersSucceeded++
+
+		flights := append([]service.UnifiedFlight(nil), res.flights...)
+		if criteria.RefundableOnly {
+			flights = refundableOnly(flights)
+		}
+		allFlights = append(allFlights, flights...)
+
+		if report != nil {
+			report(service.SearchEvent{Type: service.SearchEventProviderResults, Provider: res.provider.Name(), Flights: preview(flights)})
+		}
 	}
-	for range errorChan {
-		providersFailed++
+
+	if providersSucceeded == 0 && providersFailed > 0 {
+		meta := service.Metadata{ProvidersQueried: total, ProvidersFailed: providersFailed}
+		if errors.Is(ctx.Err(), context.Canceled) {
+			return nil, meta, ctx.Err()
+		}
+		if providersTimedOut == providersFailed {
+			return nil, meta, service.ErrProvidersTimeout
+		}
+		return nil, meta, service.ErrProvidersUnavailable
 	}
 
+	allFlights, duplicatesMerged := deduplicate(allFlights)
+
 	for i := range allFlights {
 		allFlights[i].Price.Formatted = helpers.FormatIDR(allFlights[i].Price.Amount)
 		allFlights[i].Score = calculateScore(allFlights[i])
@@ -178,20 +512,115 @@ func (s *FlightAggregator) executeSearch(ctx context.Context, criteria service.S
 		return allFlights[i].Score < allFlights[j].Score
 	})
 
+	markParetoFront(allFlights)
+
+	dominatedRemoved := 0
+	if criteria.ExcludeDominated {
+		allFlights, dominatedRemoved = dropDominated(allFlights)
+	}
+
+	applyLabels(allFlights)
+	s.recordPrices(ctx, allFlights)
+
 	meta := service.Metadata{
+		ProvidersQueried:   total,
 		ProvidersSucceeded: providersSucceeded,
 		ProvidersFailed:    providersFailed,
+		DominatedRemoved:   dominatedRemoved,
+		DuplicatesMerged:   duplicatesMerged,
 	}
 
 	return allFlights, meta, nil
 }
 
-func (s *FlightAggregator) searchMultiCity(ctx context.Context, criteria service.SearchCriteria) (service.SearchResponse, error) {
+// preview scores and orders the flights of a single provider so they can be shown before the search completes
+func preview(flights []service.UnifiedFlight) []service.UnifiedFlight {
+	ranked := append([]service.UnifiedFlight(nil), flights...)
+	for i := range ranked {
+		ranked[i].Price.Formatted = helpers.FormatIDR(ranked[i].Price.Amount)
+		ranked[i].Score = calculateScore(ranked[i])
+	}
+
+	sort.Slice(ranked, func(i, j int) bool {
+		return ranked[i].Score < ranked[j].Score
+	})
+	return ranked
+}
+
+func (s *FlightAggregator) searchMultiCity(ctx context.Context, criteria service.SearchCriteria, report reportFunc) (service.SearchResponse, error) {
 	startTime := time.Now()
-	var multiResults [][]service.UnifiedFlight
 
+	multiResults, meta, err := s.searchSegments(ctx, criteria, report)
+	if err != nil {
+		return service.SearchResponse{}, err
+	}
+	meta.SearchTimeMs = time.Since(startTime).Milliseconds()
+
+	return service.SearchResponse{
+		Criteria:         criteria,
+		Metadata:         meta,
+		MultiCityFlights: multiResults,
+	}, nil
+}
+
+// searchOpenJaw searches the outbound and inbound legs of an open-jaw trip and keeps only
+// inbound flights that leave after the outbound leg can have landed
+func (s *FlightAggregator) searchOpenJaw(ctx context.Context, criteria service.SearchCriteria, report reportFunc) (service.SearchResponse, error) {
+	startTime := time.Now()
+
+	legs, meta, err := s.searchSegments(ctx, criteria, report)
+	if err != nil {
+		return service.SearchResponse{}, err
+	}
+	departFlights, returnFlights := legs[0], departingAfter(legs[0], legs[1], 0)
+
+	meta.TotalResults = len(departFlights) + len(returnFlights)
+	meta.SearchTimeMs = time.Since(startTime).Milliseconds()
+
+	return service.SearchResponse{
+		Criteria:      criteria,
+		Metadata:      meta,
+		Flights:       departFlights,
+		ReturnFlights: returnFlights,
+	}, nil
+}
+
+// searchStopover searches every leg of a stopover trip and keeps only onward flights that
+// leave at least minStopoverDuration after the previous leg can have landed
+func (s *FlightAggregator) searchStopover(ctx context.Context, criteria service.SearchCriteria, report reportFunc) (service.SearchResponse, error) {
+	startTime := time.Now()
+
+	legs, meta, err := s.searchSegments(ctx, criteria, report)
+	if err != nil {
+		return service.SearchResponse{}, err
+	}
+
+	meta.TotalResults = len(legs[0])
+	for i := 1; i < len(legs); i++ {
+		legs[i] = departingAfter(legs[i-1], legs[i], minStopoverDuration)
+		meta.TotalResults += len(legs[i])
+	}
+	meta.SearchTimeMs = time.Since(startTime).Milliseconds()
+
+	return service.SearchResponse{
+		Criteria:         criteria,
+		Metadata:         meta,
+		MultiCityFlights: legs,
+	}, nil
+}
+
+// searchSegments runs one search per segment in order and merges their metadata. A segment whose
+// providers all failed is left empty, the search only fails when no segment could be searched.
+func (s *FlightAggregator) searchSegments(ctx context.Context, criteria service.SearchCriteria, report reportFunc) ([][]service.UnifiedFlight, service.Metadata, error) {
+	var results [][]service.UnifiedFlight
+	var lastErr error
+	segmentsFailed := 0
+
+	totalQueried := 0
 	totalSucceeded := 0
 	totalFailed := 0
+	totalRemoved := 0
+	totalMerged := 0
 	totalResults := 0
 
 	for i, seg := range criteria.Segments {
@@ -202,30 +631,36 @@ func (s *FlightAggregator) searchMultiCity(ctx context.Context, criteria service
 		segCriteria.Destination = seg.Destination
 		segCriteria.DepartureDate = seg.DepartureDate
 
-		flights, meta, err := s.executeSearch(ctx, segCriteria)
+		flights, meta, err := s.executeSearch(ctx, segCriteria, report.forLeg(i))
 		if err != nil {
 			slog.Error(fmt.Sprintf("Segment %d failed: %v", i+1, err))
-			multiResults = append(multiResults, []service.UnifiedFlight{})
+			results = append(results, []service.UnifiedFlight{})
+			totalQueried += meta.ProvidersQueried
+			totalFailed += meta.ProvidersFailed
+			segmentsFailed++
+			lastErr = err
 			continue
 		}
 
-		multiResults = append(multiResults, flights)
+		results = append(results, flights)
+		totalQueried += meta.ProvidersQueried
 		totalSucceeded += meta.ProvidersSucceeded
 		totalFailed += meta.ProvidersFailed
+		totalRemoved += meta.DominatedRemoved
+		totalMerged += meta.DuplicatesMerged
 		totalResults += len(flights)
 	}
 
-	finalMeta := service.Metadata{
+	meta := service.Metadata{
 		TotalResults:       totalResults,
-		ProvidersQueried:   len(s.providers) * len(criteria.Segments),
+		ProvidersQueried:   totalQueried,
 		ProvidersSucceeded: totalSucceeded,
 		ProvidersFailed:    totalFailed,
-		SearchTimeMs:       time.Since(startTime).Milliseconds(),
+		DominatedRemoved:   totalRemoved,
+		DuplicatesMerged:   totalMerged,
 	}
-
-	return service.SearchResponse{
-		Criteria:         criteria,
-		Metadata:         finalMeta,
-		MultiCityFlights: multiResults,
-	}, nil
+	if segmentsFailed == len(criteria.Segments) {
+		return nil, meta, lastErr
+	}
+	return results, meta, nil
 }
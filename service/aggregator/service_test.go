@@ -6,8 +6,11 @@ import (
 	"testing"
 	"time"
 
+	"github.com/elkoshar/bookcabin/api"
 	"github.com/elkoshar/bookcabin/service"
 	"github.com/elkoshar/bookcabin/service/aggregator"
+	"github.com/elkoshar/bookcabin/service/farerules"
+	"github.com/elkoshar/bookcabin/service/storage"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -32,7 +35,7 @@ func TestNewAggregator(t *testing.T) {
 	provider2 := &MockProvider{}
 	timeout := 5 * time.Second
 
-	agg := aggregator.NewAggregator(timeout, provider1, provider2)
+	agg := aggregator.NewAggregator(timeout, storage.NewMemory(), nil, provider1, provider2)
 
 	assert.NotNil(t, agg)
 	// Note: cannot test private fields directly from external package
@@ -40,7 +43,7 @@ func TestNewAggregator(t *testing.T) {
 
 func TestFlightAggregator_SearchAll_SameOriginDestination(t *testing.T) {
 	provider := &MockProvider{}
-	agg := aggregator.NewAggregator(5*time.Second, provider)
+	agg := aggregator.NewAggregator(5*time.Second, storage.NewMemory(), nil, provider)
 
 	criteria := service.SearchCriteria{
 		Origin:        "CGK",
@@ -54,6 +57,7 @@ func TestFlightAggregator_SearchAll_SameOriginDestination(t *testing.T) {
 	_, err := agg.SearchAll(ctx, criteria)
 
 	assert.Error(t, err)
+	assert.ErrorIs(t, err, service.ErrInvalidItinerary)
 	assert.Contains(t, err.Error(), "origin and destination cannot be the same")
 }
 
@@ -85,7 +89,7 @@ func TestFlightAggregator_SearchAll_DepartOnly_Success(t *testing.T) {
 		},
 	}, nil)
 
-	aggregator := aggregator.NewAggregator(5*time.Second, provider1, provider2)
+	aggregator := aggregator.NewAggregator(5*time.Second, storage.NewMemory(), nil, provider1, provider2)
 
 	criteria := service.SearchCriteria{
 		Origin:        "CGK",
@@ -143,7 +147,7 @@ func TestFlightAggregator_SearchAll_RoundTrip_Success(t *testing.T) {
 		},
 	}, nil)
 
-	agg := aggregator.NewAggregator(5*time.Second, provider)
+	agg := aggregator.NewAggregator(5*time.Second, storage.NewMemory(), nil, provider)
 
 	criteria := service.SearchCriteria{
 		Origin:        "CGK",
@@ -186,7 +190,7 @@ func TestFlightAggregator_SearchAll_ProviderFailure(t *testing.T) {
 	provider2.On("Name").Return("Test Provider 2").Maybe()
 	provider2.On("Search", mock.Anything, mock.Anything).Return([]service.UnifiedFlight(nil), errors.New("provider error"))
 
-	agg := aggregator.NewAggregator(5*time.Second, provider1, provider2)
+	agg := aggregator.NewAggregator(5*time.Second, storage.NewMemory(), nil, provider1, provider2)
 
 	criteria := service.SearchCriteria{
 		Origin:        "CGK",
@@ -214,7 +218,7 @@ func TestFlightAggregator_SearchAll_ContextTimeout(t *testing.T) {
 	provider.On("Name").Return("Slow Provider")
 	provider.On("Search", mock.Anything, mock.Anything).Return([]service.UnifiedFlight(nil), context.DeadlineExceeded)
 
-	agg := aggregator.NewAggregator(100*time.Millisecond, provider) // Very short timeout
+	agg := aggregator.NewAggregator(100*time.Millisecond, storage.NewMemory(), nil, provider) // Very short timeout
 
 	criteria := service.SearchCriteria{
 		Origin:        "CGK",
@@ -225,12 +229,9 @@ func TestFlightAggregator_SearchAll_ContextTimeout(t *testing.T) {
 	}
 
 	ctx := context.Background()
-	result, err := agg.SearchAll(ctx, criteria)
+	_, err := agg.SearchAll(ctx, criteria)
 
-	assert.NoError(t, err) // Should still return results (even if empty)
-	assert.Empty(t, result.Flights)
-	assert.Equal(t, 0, result.Metadata.ProvidersSucceeded)
-	assert.Equal(t, 1, result.Metadata.ProvidersFailed)
+	assert.ErrorIs(t, err, service.ErrProvidersTimeout)
 
 	provider.AssertExpectations(t)
 }
@@ -278,7 +279,7 @@ func TestFlightAggregator_SearchMultiCity_Success(t *testing.T) {
 	provider1.On("Search", mock.Anything, segment2Criteria).Return([]service.UnifiedFlight{}, nil)
 	provider2.On("Search", mock.Anything, segment2Criteria).Return(segment2Flights, nil)
 
-	agg := aggregator.NewAggregator(5*time.Second, provider1, provider2)
+	agg := aggregator.NewAggregator(5*time.Second, storage.NewMemory(), nil, provider1, provider2)
 
 	criteria := service.SearchCriteria{
 		Passengers: 1,
@@ -352,7 +353,7 @@ func TestFlightAggregator_SearchMultiCity_PartialFailure(t *testing.T) {
 
 	provider.On("Search", mock.Anything, segment2Criteria).Return([]service.UnifiedFlight(nil), errors.New("provider error"))
 
-	agg := aggregator.NewAggregator(5*time.Second, provider)
+	agg := aggregator.NewAggregator(5*time.Second, storage.NewMemory(), nil, provider)
 
 	criteria := service.SearchCriteria{
 		Passengers: 1,
@@ -396,7 +397,7 @@ func TestFlightAggregator_SearchMultiCity_AllSegmentsFail(t *testing.T) {
 	// Mock all segments to fail
 	provider.On("Search", mock.Anything, mock.Anything).Return([]service.UnifiedFlight(nil), errors.New("provider error"))
 
-	agg := aggregator.NewAggregator(5*time.Second, provider)
+	agg := aggregator.NewAggregator(5*time.Second, storage.NewMemory(), nil, provider)
 
 	criteria := service.SearchCriteria{
 		Passengers: 1,
@@ -408,17 +409,9 @@ func TestFlightAggregator_SearchMultiCity_AllSegmentsFail(t *testing.T) {
 	}
 
 	ctx := context.Background()
-	result, err := agg.SearchAll(ctx, criteria)
+	_, err := agg.SearchAll(ctx, criteria)
 
-	// Should handle all failures gracefully
-	assert.NoError(t, err)
-	assert.Len(t, result.MultiCityFlights, 2) // Should have 2 empty arrays
-	assert.Empty(t, result.MultiCityFlights[0])
-	assert.Empty(t, result.MultiCityFlights[1])
-	assert.Equal(t, 0, result.Metadata.TotalResults)
-	assert.Equal(t, 2, result.Metadata.ProvidersQueried) // 1 provider × 2 segments
-	assert.Equal(t, 0, result.Metadata.ProvidersSucceeded)
-	assert.Equal(t, 2, result.Metadata.ProvidersFailed)
+	assert.ErrorIs(t, err, service.ErrProvidersUnavailable)
 }
 
 func TestFlightAggregator_SearchMultiCity_SingleSegment(t *testing.T) {
@@ -438,7 +431,7 @@ func TestFlightAggregator_SearchMultiCity_SingleSegment(t *testing.T) {
 
 	provider.On("Search", mock.Anything, mock.Anything).Return(segmentFlights, nil)
 
-	agg := aggregator.NewAggregator(5*time.Second, provider)
+	agg := aggregator.NewAggregator(5*time.Second, storage.NewMemory(), nil, provider)
 
 	criteria := service.SearchCriteria{
 		Passengers: 1,
@@ -499,7 +492,7 @@ func TestFlightAggregator_SearchMultiCity_ThreeSegments(t *testing.T) {
 		return criteria.Origin == "SIN" && criteria.Destination == "NRT"
 	})).Return(segment3Flights, nil)
 
-	agg := aggregator.NewAggregator(5*time.Second, provider)
+	agg := aggregator.NewAggregator(5*time.Second, storage.NewMemory(), nil, provider)
 
 	criteria := service.SearchCriteria{
 		Passengers: 1,
@@ -525,3 +518,654 @@ func TestFlightAggregator_SearchMultiCity_ThreeSegments(t *testing.T) {
 
 	provider.AssertExpectations(t)
 }
+
+func TestFlightAggregator_SearchAll_MetroCode(t *testing.T) {
+	provider := &MockProvider{}
+	provider.On("Name").Return("Metro Provider").Maybe()
+
+	provider.On("Search", mock.Anything, mock.MatchedBy(func(criteria service.SearchCriteria) bool {
+		return criteria.Origin == "CGK" && criteria.Destination == "DPS"
+	})).Return([]service.UnifiedFlight{
+		{ID: "CGK1", FlightNumber: "GA400", Price: service.PriceInfo{Amount: 1000000, Currency: "IDR"}},
+	}, nil)
+
+	provider.On("Search", mock.Anything, mock.MatchedBy(func(criteria service.SearchCriteria) bool {
+		return criteria.Origin == "HLP" && criteria.Destination == "DPS"
+	})).Return([]service.UnifiedFlight{
+		{ID: "HLP1", FlightNumber: "ID7510", Price: service.PriceInfo{Amount: 900000, Currency: "IDR"}},
+	}, nil)
+
+	agg := aggregator.NewAggregator(5*time.Second, storage.NewMemory(), nil, provider)
+
+	criteria := service.SearchCriteria{
+		Origin:        "JKT",
+		Destination:   "DPS",
+		DepartureDate: "2025-12-15",
+		Passengers:    1,
+		CabinClass:    "economy",
+	}
+
+	result, err := agg.SearchAll(context.Background(), criteria)
+
+	assert.NoError(t, err)
+	assert.Len(t, result.Flights, 2)
+	assert.Equal(t, 2, result.Metadata.ProvidersQueried) // 1 provider × 2 airport pairs
+
+	for _, f := range result.Flights {
+		assert.NotNil(t, f.AirportMatch)
+		assert.Equal(t, "JKT", f.AirportMatch.RequestedOrigin)
+		assert.Equal(t, "DPS", f.AirportMatch.Destination)
+	}
+
+	provider.AssertExpectations(t)
+}
+
+func TestFlightAggregator_SearchAll_LowercaseCodesAreNotExpanded(t *testing.T) {
+	provider := &MockProvider{}
+	provider.On("Name").Return("Lowercase Provider").Maybe()
+	provider.On("Search", mock.Anything, mock.Anything).Return([]service.UnifiedFlight{
+		{ID: "CGK1", FlightNumber: "GA400", Price: service.PriceInfo{Amount: 1000000, Currency: "IDR"}},
+	}, nil)
+
+	agg := aggregator.NewAggregator(5*time.Second, storage.NewMemory(), nil, provider)
+
+	criteria := service.SearchCriteria{
+		Origin:        "cgk",
+		Destination:   "dps",
+		DepartureDate: "2025-12-15",
+		Passengers:    1,
+		CabinClass:    "economy",
+	}
+
+	result, err := agg.SearchAll(context.Background(), criteria)
+
+	assert.NoError(t, err)
+	assert.Len(t, result.Flights, 1)
+	assert.Nil(t, result.Flights[0].AirportMatch)
+}
+
+func TestFlightAggregator_SearchAll_NearbyAirports(t *testing.T) {
+	provider := &MockProvider{}
+	provider.On("Name").Return("Nearby Provider").Maybe()
+
+	provider.On("Search", mock.Anything, mock.MatchedBy(func(criteria service.SearchCriteria) bool {
+		return criteria.Origin == "CGK" && criteria.Destination == "LOP"
+	})).Return([]service.UnifiedFlight{
+		{ID: "LOP1", FlightNumber: "JT650", Price: service.PriceInfo{Amount: 800000, Currency: "IDR"}},
+	}, nil)
+	provider.On("Search", mock.Anything, mock.Anything).Return([]service.UnifiedFlight{}, nil)
+
+	agg := aggregator.NewAggregator(5*time.Second, storage.NewMemory(), nil, provider)
+
+	criteria := service.SearchCriteria{
+		Origin:         "CGK",
+		Destination:    "DPS",
+		DepartureDate:  "2025-12-15",
+		Passengers:     1,
+		CabinClass:     "economy",
+		NearbyRadiusKm: 150,
+	}
+
+	result, err := agg.SearchAll(context.Background(), criteria)
+
+	assert.NoError(t, err)
+	assert.Len(t, result.Flights, 1)
+	assert.Equal(t, "LOP", result.Flights[0].AirportMatch.Destination)
+	assert.Greater(t, result.Flights[0].AirportMatch.DestinationDistanceKm, 0.0)
+}
+
+func TestFlightAggregator_SearchAll_OpenJaw(t *testing.T) {
+	provider := &MockProvider{}
+	provider.On("Name").Return("Open Jaw Provider").Maybe()
+
+	provider.On("Search", mock.Anything, mock.MatchedBy(func(criteria service.SearchCriteria) bool {
+		return criteria.Origin == "CGK" && criteria.Destination == "DPS"
+	})).Return([]service.UnifiedFlight{
+		{ID: "OUT1", FlightNumber: "GA400", Arrival: service.LocationInfo{Timestamp: 1000}},
+	}, nil)
+
+	provider.On("Search", mock.Anything, mock.MatchedBy(func(criteria service.SearchCriteria) bool {
+		return criteria.Origin == "LOP" && criteria.Destination == "CGK"
+	})).Return([]service.UnifiedFlight{
+		{ID: "IN_EARLY", FlightNumber: "GA431", Departure: service.LocationInfo{Timestamp: 500}},
+		{ID: "IN_LATE", FlightNumber: "GA433", Departure: service.LocationInfo{Timestamp: 5000}},
+	}, nil)
+
+	agg := aggregator.NewAggregator(5*time.Second, storage.NewMemory(), nil, provider)
+
+	criteria := service.SearchCriteria{
+		TripType:   service.TripTypeOpenJaw,
+		Passengers: 1,
+		CabinClass: "economy",
+		Segments: []service.RouteSegment{
+			{Origin: "CGK", Destination: "DPS", DepartureDate: "2025-12-15"},
+			{Origin: "LOP", Destination: "CGK", DepartureDate: "2025-12-20"},
+		},
+	}
+
+	result, err := agg.SearchAll(context.Background(), criteria)
+
+	assert.NoError(t, err)
+	assert.Len(t, result.Flights, 1)
+	assert.Len(t, result.ReturnFlights, 1)
+	assert.Equal(t, "IN_LATE", result.ReturnFlights[0].ID)
+	assert.Equal(t, 2, result.Metadata.TotalResults)
+	assert.Empty(t, result.MultiCityFlights)
+}
+
+func TestFlightAggregator_SearchAll_Stopover(t *testing.T) {
+	provider := &MockProvider{}
+	provider.On("Name").Return("Stopover Provider").Maybe()
+
+	provider.On("Search", mock.Anything, mock.MatchedBy(func(criteria service.SearchCriteria) bool {
+		return criteria.Origin == "CGK" && criteria.Destination == "SUB"
+	})).Return([]service.UnifiedFlight{
+		{ID: "LEG1", FlightNumber: "GA312", Arrival: service.LocationInfo{Timestamp: 1000}},
+	}, nil)
+
+	provider.On("Search", mock.Anything, mock.MatchedBy(func(criteria service.SearchCriteria) bool {
+		return criteria.Origin == "SUB" && criteria.Destination == "DPS"
+	})).Return([]service.UnifiedFlight{
+		{ID: "LEG2_CONNECTION", FlightNumber: "GA340", Departure: service.LocationInfo{Timestamp: 1000 + 3*3600}},
+		{ID: "LEG2_STOPOVER", FlightNumber: "GA342", Departure: service.LocationInfo{Timestamp: 1000 + 48*3600}},
+	}, nil)
+
+	agg := aggregator.NewAggregator(5*time.Second, storage.NewMemory(), nil, provider)
+
+	criteria := service.SearchCriteria{
+		TripType:   service.TripTypeStopover,
+		Passengers: 1,
+		CabinClass: "economy",
+		Segments: []service.RouteSegment{
+			{Origin: "CGK", Destination: "SUB", DepartureDate: "2025-12-15"},
+			{Origin: "SUB", Destination: "DPS", DepartureDate: "2025-12-17"},
+		},
+	}
+
+	result, err := agg.SearchAll(context.Background(), criteria)
+
+	assert.NoError(t, err)
+	assert.Len(t, result.MultiCityFlights, 2)
+	assert.Len(t, result.MultiCityFlights[1], 1)
+	assert.Equal(t, "LEG2_STOPOVER", result.MultiCityFlights[1][0].ID)
+	assert.Equal(t, 2, result.Metadata.TotalResults)
+}
+
+func TestFlightAggregator_SearchAll_InvalidTripType(t *testing.T) {
+	agg := aggregator.NewAggregator(5*time.Second, storage.NewMemory(), nil, &MockProvider{})
+
+	tests := []struct {
+		name     string
+		criteria service.SearchCriteria
+		errMsg   string
+	}{
+		{
+			name:     "round trip without return date",
+			criteria: service.SearchCriteria{TripType: service.TripTypeRoundTrip, Origin: "CGK", Destination: "DPS", DepartureDate: "2025-12-15"},
+			errMsg:   "round_trip requires a return date",
+		},
+		{
+			name:     "return before departure",
+			criteria: service.SearchCriteria{Origin: "CGK", Destination: "DPS", DepartureDate: "2025-12-15", ReturnDate: "2025-12-10"},
+			errMsg:   "return date cannot be before departure date",
+		},
+		{
+			name: "open jaw that is a round trip",
+			criteria: service.SearchCriteria{TripType: service.TripTypeOpenJaw, Segments: []service.RouteSegment{
+				{Origin: "CGK", Destination: "DPS", DepartureDate: "2025-12-15"},
+				{Origin: "DPS", Destination: "CGK", DepartureDate: "2025-12-20"},
+			}},
+			errMsg: "use round_trip instead",
+		},
+		{
+			name: "stopover with disconnected segments",
+			criteria: service.SearchCriteria{TripType: service.TripTypeStopover, Segments: []service.RouteSegment{
+				{Origin: "CGK", Destination: "SUB", DepartureDate: "2025-12-15"},
+				{Origin: "DPS", Destination: "LOP", DepartureDate: "2025-12-17"},
+			}},
+			errMsg: "must depart from SUB",
+		},
+		{
+			name: "stopover shorter than a day",
+			criteria: service.SearchCriteria{TripType: service.TripTypeStopover, Segments: []service.RouteSegment{
+				{Origin: "CGK", Destination: "SUB", DepartureDate: "2025-12-15"},
+				{Origin: "SUB", Destination: "DPS", DepartureDate: "2025-12-15"},
+			}},
+			errMsg: "departs too soon",
+		},
+		{
+			name:     "unknown trip type",
+			criteria: service.SearchCriteria{TripType: "circle", Origin: "CGK", Destination: "DPS"},
+			errMsg:   "unsupported trip type",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := agg.SearchAll(context.Background(), tt.criteria)
+
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errMsg)
+		})
+	}
+}
+
+func TestFlightAggregator_SearchAll_Labels(t *testing.T) {
+	provider := &MockProvider{}
+	provider.On("Name").Return("Label Provider").Maybe()
+
+	provider.On("Search", mock.Anything, mock.Anything).Return([]service.UnifiedFlight{
+		{ID: "CHEAP", Price: service.PriceInfo{Amount: 500000}, Duration: service.DurationInfo{TotalMinutes: 300}, Stops: 1, Departure: service.LocationInfo{Timestamp: 300}},
+		{ID: "FAST", Price: service.PriceInfo{Amount: 1500000}, Duration: service.DurationInfo{TotalMinutes: 90}, Departure: service.LocationInfo{Timestamp: 200}},
+		{ID: "BALANCED", Price: service.PriceInfo{Amount: 700000}, Duration: service.DurationInfo{TotalMinutes: 110}, Departure: service.LocationInfo{Timestamp: 100}},
+		{ID: "DOMINATED", Price: service.PriceInfo{Amount: 900000}, Duration: service.DurationInfo{TotalMinutes: 120}, Departure: service.LocationInfo{Timestamp: 400}},
+	}, nil)
+
+	agg := aggregator.NewAggregator(5*time.Second, storage.NewMemory(), nil, provider)
+
+	criteria := service.SearchCriteria{
+		Origin:        "CGK",
+		Destination:   "DPS",
+		DepartureDate: "2025-12-15",
+		Passengers:    1,
+		CabinClass:    "economy",
+	}
+
+	result, err := agg.SearchAll(context.Background(), criteria)
+	assert.NoError(t, err)
+	assert.Len(t, result.Flights, 4)
+
+	labels := map[string][]string{}
+	pareto := map[string]bool{}
+	for _, f := range result.Flights {
+		labels[f.ID] = f.Labels
+		pareto[f.ID] = f.ParetoOptimal
+	}
+
+	assert.Contains(t, labels["CHEAP"], service.LabelCheapest)
+	assert.Contains(t, labels["FAST"], service.LabelFastest)
+	assert.Contains(t, labels["BALANCED"], service.LabelBestValue)
+	assert.Contains(t, labels["BALANCED"], service.LabelEarliest)
+	assert.Contains(t, labels["BALANCED"], service.LabelDirectCheapest)
+	assert.Empty(t, labels["DOMINATED"])
+	assert.False(t, pareto["DOMINATED"])
+	assert.True(t, pareto["CHEAP"])
+
+	criteria.ExcludeDominated = true
+	result, err = agg.SearchAll(context.Background(), criteria)
+	assert.NoError(t, err)
+	assert.Len(t, result.Flights, 3)
+	assert.Equal(t, 1, result.Metadata.DominatedRemoved)
+}
+
+func TestFlightAggregator_SearchAll_Deduplication(t *testing.T) {
+	lionProvider := &MockProvider{}
+	batikProvider := &MockProvider{}
+	lionProvider.On("Name").Return("Lion Air").Maybe()
+	batikProvider.On("Name").Return("Batik Air").Maybe()
+
+	// Lion Group resells the Batik flight under its own marketing number
+	lionProvider.On("Search", mock.Anything, mock.Anything).Return([]service.UnifiedFlight{
+		{
+			ID:                    "JT7514_Lion",
+			Provider:              "Lion Air",
+			Airline:               service.AirlineInfo{Name: "Lion Air", Code: "JT"},
+			FlightNumber:          "JT7514",
+			OperatingCarrier:      "ID",
+			OperatingFlightNumber: "ID 6514",
+			Departure:             service.LocationInfo{Timestamp: 1765757700},
+			Price:                 service.PriceInfo{Amount: 1050000, Currency: "IDR"},
+		},
+	}, nil)
+
+	batikProvider.On("Search", mock.Anything, mock.Anything).Return([]service.UnifiedFlight{
+		{
+			ID:           "ID6514_Batik",
+			Provider:     "Batik Air",
+			Airline:      service.AirlineInfo{Name: "Batik Air", Code: "ID"},
+			FlightNumber: "ID6514",
+			Departure:    service.LocationInfo{Timestamp: 1765757700},
+			Price:        service.PriceInfo{Amount: 1100000, Currency: "IDR"},
+		},
+		{
+			ID:           "ID6520_Batik",
+			Provider:     "Batik Air",
+			Airline:      service.AirlineInfo{Name: "Batik Air", Code: "ID"},
+			FlightNumber: "ID6520",
+			Departure:    service.LocationInfo{Timestamp: 1765780200},
+			Price:        service.PriceInfo{Amount: 1180000, Currency: "IDR"},
+		},
+	}, nil)
+
+	agg := aggregator.NewAggregator(5*time.Second, storage.NewMemory(), nil, lionProvider, batikProvider)
+
+	criteria := service.SearchCriteria{
+		Origin:        "CGK",
+		Destination:   "DPS",
+		DepartureDate: "2025-12-15",
+		Passengers:    1,
+		CabinClass:    "economy",
+	}
+
+	result, err := agg.SearchAll(context.Background(), criteria)
+
+	assert.NoError(t, err)
+	assert.Len(t, result.Flights, 2)
+	assert.Equal(t, 1, result.Metadata.DuplicatesMerged)
+	assert.Equal(t, 2, result.Metadata.TotalResults)
+
+	var merged service.UnifiedFlight
+	for _, f := range result.Flights {
+		if len(f.AlternativeOffers) > 0 {
+			merged = f
+		}
+	}
+	assert.Equal(t, "JT7514_Lion", merged.ID)
+	assert.Equal(t, "ID6514_Batik", merged.AlternativeOffers[0].ID)
+	assert.Equal(t, float64(1100000), merged.AlternativeOffers[0].Price.Amount)
+}
+
+func TestFlightAggregator_GetFlight(t *testing.T) {
+	key := service.OfferKey{Provider: "Garuda Indonesia", FlightNumber: "GA400", Origin: "CGK", Destination: "DPS", DepartureDate: "2025-12-15", CabinClass: "economy"}
+	offerID := service.NewOfferID(key)
+
+	garuda := &MockProvider{}
+	garuda.On("Name").Return("Garuda Indonesia")
+	garuda.On("Search", mock.Anything, key.Criteria()).Return([]service.UnifiedFlight{
+		{ID: offerID, Provider: "Garuda Indonesia", FlightNumber: "GA400", Price: service.PriceInfo{Amount: 1250000, Currency: "IDR"}},
+	}, nil)
+
+	lion := &MockProvider{}
+	lion.On("Name").Return("Lion Air")
+
+	agg := aggregator.NewAggregator(5*time.Second, storage.NewMemory(), nil, lion, garuda)
+
+	flight, err := agg.GetFlight(context.Background(), offerID)
+	assert.NoError(t, err)
+	assert.Equal(t, "GA400", flight.FlightNumber)
+	assert.Equal(t, "IDR 1.250.000", flight.Price.Formatted)
+
+	_, err = agg.GetFlight(context.Background(), "not-an-offer")
+	assert.ErrorIs(t, err, service.ErrInvalidOfferID)
+
+	missing := service.NewOfferID(service.OfferKey{Provider: "Garuda Indonesia", FlightNumber: "GA999", Origin: "CGK", Destination: "DPS", DepartureDate: "2025-12-15", CabinClass: "economy"})
+	_, err = agg.GetFlight(context.Background(), missing)
+	assert.ErrorIs(t, err, service.ErrOfferNotFound)
+
+	unknownProvider := service.NewOfferID(service.OfferKey{Provider: "Citilink", FlightNumber: "QG100", Origin: "CGK", Destination: "DPS", DepartureDate: "2025-12-15", CabinClass: "economy"})
+	_, err = agg.GetFlight(context.Background(), unknownProvider)
+	assert.ErrorIs(t, err, service.ErrOfferNotFound)
+
+	lion.AssertNotCalled(t, "Search", mock.Anything, mock.Anything)
+}
+
+func TestFlightAggregator_Reprice(t *testing.T) {
+	key := service.OfferKey{Provider: "Garuda Indonesia", FlightNumber: "GA400", Origin: "CGK", Destination: "DPS", DepartureDate: "2025-12-15", CabinClass: "economy"}
+	offerID := service.NewOfferID(key)
+
+	provider := &MockProvider{}
+	provider.On("Name").Return("Garuda Indonesia")
+
+	// first call is the search, the second is the revalidation after a fare increase
+	provider.On("Search", mock.Anything, mock.Anything).Return([]service.UnifiedFlight{
+		{ID: offerID, Provider: "Garuda Indonesia", FlightNumber: "GA400", Price: service.PriceInfo{Amount: 1250000, Currency: "IDR"}, AvailableSeats: 3},
+	}, nil).Once()
+	provider.On("Search", mock.Anything, mock.Anything).Return([]service.UnifiedFlight{
+		{ID: offerID, Provider: "Garuda Indonesia", FlightNumber: "GA400", Price: service.PriceInfo{Amount: 1400000, Currency: "IDR"}, AvailableSeats: 3},
+	}, nil)
+
+	agg := aggregator.NewAggregator(5*time.Second, storage.NewMemory(), nil, provider)
+	ctx := context.Background()
+
+	_, err := agg.SearchAll(ctx, key.Criteria())
+	assert.NoError(t, err)
+
+	result, err := agg.Reprice(ctx, service.RepriceRequest{ID: offerID})
+	assert.NoError(t, err)
+	assert.Equal(t, service.RepriceStatusPriceChanged, result.Status)
+	assert.Equal(t, float64(1250000), result.OldAmount)
+	assert.Equal(t, float64(1400000), result.NewAmount)
+
+	result, err = agg.Reprice(ctx, service.RepriceRequest{ID: offerID})
+	assert.NoError(t, err)
+	assert.Equal(t, service.RepriceStatusUnchanged, result.Status)
+
+	result, err = agg.Reprice(ctx, service.RepriceRequest{ID: offerID, Passengers: 4})
+	assert.NoError(t, err)
+	assert.Equal(t, service.RepriceStatusSoldOut, result.Status)
+
+	gone := service.NewOfferID(service.OfferKey{Provider: "Garuda Indonesia", FlightNumber: "GA999", Origin: "CGK", Destination: "DPS", DepartureDate: "2025-12-15", CabinClass: "economy"})
+	result, err = agg.Reprice(ctx, service.RepriceRequest{ID: gone})
+	assert.NoError(t, err)
+	assert.Equal(t, service.RepriceStatusSoldOut, result.Status)
+
+	_, err = agg.Reprice(ctx, service.RepriceRequest{ID: "bad"})
+	assert.ErrorIs(t, err, service.ErrInvalidOfferID)
+}
+
+func TestFlightAggregator_SearchAll_PersistsSearch(t *testing.T) {
+	key := service.OfferKey{Provider: "Garuda Indonesia", FlightNumber: "GA400", Origin: "CGK", Destination: "DPS", DepartureDate: "2025-12-15", CabinClass: "economy"}
+	offerID := service.NewOfferID(key)
+
+	provider := &MockProvider{}
+	provider.On("Name").Return("Garuda Indonesia")
+	provider.On("Search", mock.Anything, mock.Anything).Return([]service.UnifiedFlight{
+		{ID: offerID, Provider: "Garuda Indonesia", FlightNumber: "GA400", Price: service.PriceInfo{Amount: 1250000, Currency: "IDR"}, AvailableSeats: 3},
+	}, nil)
+
+	store := storage.NewMemory()
+	agg := aggregator.NewAggregator(5*time.Second, store, nil, provider)
+	ctx := context.Background()
+
+	resp, err := agg.SearchAll(ctx, key.Criteria())
+	assert.NoError(t, err)
+	assert.NotEmpty(t, resp.SearchID)
+
+	session, err := store.GetSearchSession(ctx, resp.SearchID)
+	assert.NoError(t, err)
+	assert.Equal(t, service.TripTypeOneWay, session.TripType)
+	assert.Equal(t, 1, session.TotalResults)
+
+	snapshot, err := store.GetSnapshot(ctx, resp.SearchID)
+	assert.NoError(t, err)
+	assert.Len(t, snapshot.Response.Flights, 1)
+
+	// prices are written in the background, closing flushes them
+	agg.Close()
+	history, err := store.PriceHistory(ctx, offerID)
+	assert.NoError(t, err)
+	assert.Len(t, history, 1)
+	assert.Equal(t, float64(1250000), history[0].Amount)
+}
+
+func TestFlightAggregator_SearchAll_RefundableOnly(t *testing.T) {
+	provider := &MockProvider{}
+	provider.On("Name").Return("Garuda Indonesia")
+	provider.On("Search", mock.Anything, mock.Anything).Return([]service.UnifiedFlight{
+		{ID: "lite", FlightNumber: "GA1", Price: service.PriceInfo{Amount: 900000}, FareRules: farerules.Summary(service.FareFamilyLite)},
+		{ID: "flex", FlightNumber: "GA2", Price: service.PriceInfo{Amount: 1200000}, FareRules: farerules.Summary(service.FareFamilyEconomyFlex)},
+		{ID: "unknown", FlightNumber: "GA3", Price: service.PriceInfo{Amount: 1000000}},
+	}, nil)
+
+	agg := aggregator.NewAggregator(5*time.Second, storage.NewMemory(), nil, provider)
+
+	resp, err := agg.SearchAll(context.Background(), service.SearchCriteria{Origin: "CGK", Destination: "DPS", DepartureDate: "2025-12-15", Passengers: 1, RefundableOnly: true})
+	assert.NoError(t, err)
+	assert.Len(t, resp.Flights, 1)
+	assert.Equal(t, "flex", resp.Flights[0].ID)
+	assert.True(t, resp.Flights[0].FareRules.Refundable)
+}
+
+// staticRates implements FXRateProvider with fixed rates
+type staticRates struct {
+	rates service.FXRates
+	err   error
+}
+
+func (r staticRates) Rates(ctx context.Context) (service.FXRates, error) {
+	return r.rates, r.err
+}
+
+func TestFlightAggregator_SearchAll_Currency(t *testing.T) {
+	rates := staticRates{rates: service.FXRates{Base: "USD", Rates: map[string]float64{"IDR": 16000, "SGD": 1.35}}}
+
+	tests := []struct {
+		name     string
+		rates    api.FXRateProvider
+		currency string
+		amount   float64
+		wantErr  error
+	}{
+		{name: "rupiah without rates", currency: "IDR", amount: 1200000},
+		{name: "dollar", rates: rates, currency: "USD", amount: 75},
+		{name: "singapore dollar", rates: rates, currency: "SGD", amount: 101.25},
+		{name: "no rate source", currency: "USD", wantErr: service.ErrRatesUnavailable},
+		{name: "rate source failed", rates: staticRates{err: errors.New("stale")}, currency: "USD", wantErr: service.ErrRatesUnavailable},
+		{name: "currency without a rate", rates: rates, currency: "MYR", wantErr: service.ErrUnsupportedCurrency},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := &MockProvider{}
+			provider.On("Name").Return("Garuda Indonesia")
+			provider.On("Search", mock.Anything, mock.Anything).Return([]service.UnifiedFlight{
+				{ID: "GA1", FlightNumber: "GA1", Price: service.PriceInfo{Amount: 1200000, Currency: "IDR"}},
+			}, nil)
+
+			store := storage.NewMemory()
+			agg := aggregator.NewAggregator(5*time.Second, store, tt.rates, provider)
+
+			resp, err := agg.SearchAll(context.Background(), service.SearchCriteria{Origin: "CGK", Destination: "DPS", DepartureDate: "2025-12-15", Passengers: 1, Currency: tt.currency})
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.currency, resp.Flights[0].Price.Currency)
+			assert.Equal(t, tt.amount, resp.Flights[0].Price.Amount)
+			if tt.currency != "IDR" {
+				assert.Equal(t, float64(1200000), resp.Flights[0].Price.OriginalAmount)
+				assert.Equal(t, "IDR", resp.Flights[0].Price.OriginalCurrency)
+			}
+
+			// the stored search keeps the provider prices
+			snapshot, err := store.GetSnapshot(context.Background(), resp.SearchID)
+			assert.NoError(t, err)
+			assert.Equal(t, "IDR", snapshot.Response.Flights[0].Price.Currency)
+		})
+	}
+}
+
+func TestFlightAggregator_SearchStream(t *testing.T) {
+	provider1 := &MockProvider{}
+	provider2 := &MockProvider{}
+
+	provider1.On("Name").Return("Provider 1")
+	provider1.On("Search", mock.Anything, mock.Anything).Return([]service.UnifiedFlight{
+		{ID: "P1_EXPENSIVE", Provider: "Provider 1", Price: service.PriceInfo{Amount: 900000, Currency: "IDR"}, Duration: service.DurationInfo{TotalMinutes: 120}},
+		{ID: "P1_CHEAP", Provider: "Provider 1", Price: service.PriceInfo{Amount: 500000, Currency: "IDR"}, Duration: service.DurationInfo{TotalMinutes: 120}},
+	}, nil)
+	provider2.On("Name").Return("Provider 2")
+	provider2.On("Search", mock.Anything, mock.Anything).Return([]service.UnifiedFlight(nil), errors.New("provider error"))
+
+	agg := aggregator.NewAggregator(5*time.Second, storage.NewMemory(), nil, provider1, provider2)
+
+	events, err := agg.SearchStream(context.Background(), service.SearchCriteria{
+		Origin:        "CGK",
+		Destination:   "DPS",
+		DepartureDate: "2025-12-15",
+		Passengers:    1,
+		CabinClass:    "economy",
+	})
+	assert.NoError(t, err)
+
+	byType := map[string][]service.SearchEvent{}
+	var last service.SearchEvent
+	for event := range events {
+		byType[event.Type] = append(byType[event.Type], event)
+		last = event
+	}
+
+	assert.Len(t, byType[service.SearchEventProviderResults], 1)
+	results := byType[service.SearchEventProviderResults][0]
+	assert.Equal(t, "Provider 1", results.Provider)
+	assert.Equal(t, "P1_CHEAP", results.Flights[0].ID)
+	assert.NotEmpty(t, results.Flights[0].Price.Formatted)
+
+	assert.Len(t, byType[service.SearchEventProviderError], 1)
+	assert.Equal(t, "Provider 2", byType[service.SearchEventProviderError][0].Provider)
+
+	assert.Equal(t, service.SearchEventSummary, last.Type)
+	assert.NotEmpty(t, last.Summary.SearchID)
+	assert.Len(t, last.Summary.Flights, 2)
+	assert.Equal(t, 1, last.Summary.Metadata.ProvidersSucceeded)
+	assert.Equal(t, 1, last.Summary.Metadata.ProvidersFailed)
+}
+
+func TestFlightAggregator_SearchStream_AllProvidersFail(t *testing.T) {
+	provider := &MockProvider{}
+	provider.On("Name").Return("Failing Provider")
+	provider.On("Search", mock.Anything, mock.Anything).Return([]service.UnifiedFlight(nil), errors.New("provider error"))
+
+	agg := aggregator.NewAggregator(5*time.Second, storage.NewMemory(), nil, provider)
+
+	events, err := agg.SearchStream(context.Background(), service.SearchCriteria{
+		Origin:        "CGK",
+		Destination:   "DPS",
+		DepartureDate: "2025-12-15",
+		Passengers:    1,
+		CabinClass:    "economy",
+	})
+	assert.NoError(t, err)
+
+	var last service.SearchEvent
+	for event := range events {
+		last = event
+	}
+
+	assert.Equal(t, service.SearchEventError, last.Type)
+	assert.NotEmpty(t, last.Error)
+	assert.ErrorIs(t, last.Err, service.ErrProvidersUnavailable)
+}
+
+func TestFlightAggregator_SearchStream_RoundTripLegs(t *testing.T) {
+	provider := &MockProvider{}
+	provider.On("Name").Return("Provider 1")
+	provider.On("Search", mock.Anything, mock.Anything).Return([]service.UnifiedFlight{
+		{ID: "F1", Provider: "Provider 1", Price: service.PriceInfo{Amount: 500000, Currency: "IDR"}},
+	}, nil)
+
+	agg := aggregator.NewAggregator(5*time.Second, storage.NewMemory(), nil, provider)
+
+	events, err := agg.SearchStream(context.Background(), service.SearchCriteria{
+		Origin:        "CGK",
+		Destination:   "DPS",
+		DepartureDate: "2025-12-15",
+		ReturnDate:    "2025-12-20",
+		Passengers:    1,
+		CabinClass:    "economy",
+	})
+	assert.NoError(t, err)
+
+	var legs []int
+	for event := range events {
+		if event.Type == service.SearchEventProviderResults {
+			legs = append(legs, event.Leg)
+		}
+	}
+	assert.Equal(t, []int{0, 1}, legs)
+}
+
+func TestFlightAggregator_SearchStream_InvalidItinerary(t *testing.T) {
+	agg := aggregator.NewAggregator(5*time.Second, storage.NewMemory(), nil, &MockProvider{})
+
+	events, err := agg.SearchStream(context.Background(), service.SearchCriteria{
+		Origin:        "CGK",
+		Destination:   "CGK",
+		DepartureDate: "2025-12-15",
+		Passengers:    1,
+	})
+
+	assert.Error(t, err)
+	assert.Nil(t, events)
+}
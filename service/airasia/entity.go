@@ -15,4 +15,5 @@ type flight struct {
 	DirectFlight bool    `json:"direct_flight"`
 	Seats        int     `json:"seats"`
 	CabinClass   string  `json:"cabin_class"`
+	BaggageNote  string  `json:"baggage_note"`
 }
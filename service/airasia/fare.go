@@ -0,0 +1,15 @@
+package airasia
+
+import (
+	"strings"
+
+	entity "github.com/elkoshar/bookcabin/service"
+)
+
+// fareFamily maps an AirAsia cabin onto a fare family, economy seats are sold as lite fares
+func fareFamily(cabinClass string) string {
+	if strings.EqualFold(cabinClass, "business") {
+		return entity.FareFamilyBusiness
+	}
+	return entity.FareFamilyLite
+}
@@ -10,14 +10,20 @@ import (
 
 	"github.com/elkoshar/bookcabin/pkg/helpers"
 	entity "github.com/elkoshar/bookcabin/service"
+	"github.com/elkoshar/bookcabin/service/farerules"
+	"github.com/elkoshar/bookcabin/service/simulator"
 )
 
 type Provider struct {
 	dataPath string
+	booking  *simulator.BookingBackend
 }
 
 func New(path string) *Provider {
-	return &Provider{dataPath: path}
+	return &Provider{
+		dataPath: path,
+		booking:  simulator.NewBookingBackend(),
+	}
 }
 
 func (p *Provider) Name() string { return "AirAsia" }
@@ -64,8 +70,10 @@ func (p *Provider) Search(ctx context.Context, c entity.SearchCriteria) ([]entit
 		originCity := helpers.GetCityName(f.FromAirport)
 		destinationCity := helpers.GetCityName(f.ToAirport)
 
+		offerID := entity.NewOfferID(entity.OfferKey{Provider: p.Name(), FlightNumber: f.FlightCode, Origin: f.FromAirport, Destination: f.ToAirport, DepartureDate: c.DepartureDate, CabinClass: f.CabinClass})
+
 		results = append(results, entity.UnifiedFlight{
-			ID:             fmt.Sprintf("%s_AirAsia", f.FlightCode),
+			ID:             offerID,
 			Provider:       p.Name(),
 			Airline:        entity.AirlineInfo{Name: f.Airline, Code: "QZ"},
 			FlightNumber:   f.FlightCode,
@@ -74,8 +82,10 @@ func (p *Provider) Search(ctx context.Context, c entity.SearchCriteria) ([]entit
 			Duration:       entity.DurationInfo{TotalMinutes: durationMins, Formatted: fmt.Sprintf("%dh %dm", durationMins/60, durationMins%60)},
 			Stops:          stops,
 			Price:          entity.PriceInfo{Amount: f.PriceIDR, Currency: "IDR"},
-			AvailableSeats: f.Seats,
+			AvailableSeats: f.Seats - p.booking.HeldSeats(offerID),
 			CabinClass:     f.CabinClass,
+			Baggage:        &entity.BaggageInfo{CabinPieces: 1, Note: f.BaggageNote},
+			FareRules:      farerules.Summary(fareFamily(f.CabinClass)),
 		})
 	}
 	return results, nil
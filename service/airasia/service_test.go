@@ -84,7 +84,7 @@ func TestProvider_Search_Success(t *testing.T) {
 
 	// Check first flight
 	flight1 := flights[0]
-	assert.Equal(t, "QZ520_AirAsia", flight1.ID)
+	assert.Equal(t, service.NewOfferID(service.OfferKey{Provider: "AirAsia", FlightNumber: "QZ520", Origin: "CGK", Destination: "DPS", DepartureDate: "2025-12-15", CabinClass: "economy"}), flight1.ID)
 	assert.Equal(t, "AirAsia", flight1.Provider)
 	assert.Equal(t, "AirAsia", flight1.Airline.Name)
 	assert.Equal(t, "QZ", flight1.Airline.Code)
@@ -99,7 +99,7 @@ func TestProvider_Search_Success(t *testing.T) {
 
 	// Check second flight (with stop)
 	flight2 := flights[1]
-	assert.Equal(t, "QZ521_AirAsia", flight2.ID)
+	assert.Equal(t, service.NewOfferID(service.OfferKey{Provider: "AirAsia", FlightNumber: "QZ521", Origin: "CGK", Destination: "DPS", DepartureDate: "2025-12-15", CabinClass: "economy"}), flight2.ID)
 	assert.Equal(t, 1, flight2.Stops)
 	assert.Equal(t, float64(1800000), flight2.Price.Amount)
 }
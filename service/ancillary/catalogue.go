@@ -0,0 +1,115 @@
+package ancillary
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/elkoshar/bookcabin/pkg/helpers"
+	"github.com/elkoshar/bookcabin/service"
+)
+
+type product struct {
+	code        string
+	kind        string
+	name        string
+	description string
+	amount      float64
+}
+
+// baggageTiers are sold when the fare has no checked baggage
+var baggageTiers = []product{
+	{code: "BAG15", kind: service.AncillaryBaggage, name: "Checked baggage 15 kg", amount: 250000},
+	{code: "BAG20", kind: service.AncillaryBaggage, name: "Checked baggage 20 kg", amount: 320000},
+	{code: "BAG30", kind: service.AncillaryBaggage, name: "Checked baggage 30 kg", amount: 450000},
+}
+
+// extraBaggageTiers top up a fare that already includes checked baggage
+var extraBaggageTiers = []product{
+	{code: "XBAG5", kind: service.AncillaryBaggage, name: "Extra baggage +5 kg", amount: 150000},
+	{code: "XBAG10", kind: service.AncillaryBaggage, name: "Extra baggage +10 kg", amount: 280000},
+	{code: "XBAG20", kind: service.AncillaryBaggage, name: "Extra baggage +20 kg", amount: 520000},
+}
+
+var meals = []product{
+	{code: "MEAL", kind: service.AncillaryMeal, name: "Hot meal", description: "Nasi goreng or chicken rice with a drink", amount: 65000},
+	{code: "VGML", kind: service.AncillaryMeal, name: "Vegetarian meal", amount: 65000},
+}
+
+var priorityBoarding = product{code: "PRIO", kind: service.AncillaryPriorityBoarding, name: "Priority boarding", description: "Board first and get overhead space near your seat", amount: 50000}
+
+var insurance = []product{
+	{code: "INS", kind: service.AncillaryInsurance, name: "Travel insurance", description: "Medical cover and lost baggage", amount: 45000},
+	{code: "INSPLUS", kind: service.AncillaryInsurance, name: "Travel insurance plus", description: "Travel insurance with trip cancellation and delay cover", amount: 95000},
+}
+
+// Catalogue returns the ancillaries that can be added to a flight. Checked baggage is sold
+// as full allowances when the fare has none and as top-ups otherwise, meals only when the
+// fare has no meal and priority boarding only in economy.
+func Catalogue(flight service.UnifiedFlight) []service.Ancillary {
+	var products []product
+
+	if flight.Baggage.HasChecked() {
+		products = append(products, extraBaggageTiers...)
+	} else {
+		products = append(products, baggageTiers...)
+	}
+
+	if !flight.MealIncluded {
+		products = append(products, meals...)
+	}
+
+	if strings.EqualFold(flight.CabinClass, "economy") || flight.CabinClass == "" {
+		products = append(products, priorityBoarding)
+	}
+
+	products = append(products, insurance...)
+
+	ancillaries := make([]service.Ancillary, len(products))
+	for i, p := range products {
+		ancillaries[i] = service.Ancillary{
+			Code:        p.code,
+			Type:        p.kind,
+			Name:        p.name,
+			Description: p.description,
+			Price:       service.PriceInfo{Amount: p.amount, Currency: "IDR", Formatted: helpers.FormatIDR(p.amount)},
+		}
+	}
+	return ancillaries
+}
+
+// Book prices a selection against the catalogue of a flight. Every passenger may take at most
+// one product of each type.
+func Book(flight service.UnifiedFlight, passengers int, selections []service.AncillarySelection) ([]service.BookedAncillary, error) {
+	byCode := make(map[string]service.Ancillary)
+	for _, a := range Catalogue(flight) {
+		byCode[a.Code] = a
+	}
+
+	taken := make(map[string]bool)
+	booked := make([]service.BookedAncillary, 0, len(selections))
+	for _, sel := range selections {
+		if sel.PassengerIndex < 0 || sel.PassengerIndex >= passengers {
+			return nil, fmt.Errorf("%w: booking has no passenger %d", service.ErrInvalidAncillarySelection, sel.PassengerIndex)
+		}
+
+		a, ok := byCode[strings.ToUpper(sel.Code)]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s is not offered on this flight", service.ErrInvalidAncillarySelection, sel.Code)
+		}
+
+		key := fmt.Sprintf("%d|%s", sel.PassengerIndex, a.Type)
+		if taken[key] {
+			return nil, fmt.Errorf("%w: passenger %d has more than one %s product", service.ErrInvalidAncillarySelection, sel.PassengerIndex, a.Type)
+		}
+		taken[key] = true
+
+		booked = append(booked, service.BookedAncillary{
+			PassengerIndex: sel.PassengerIndex,
+			Code:           a.Code,
+			Type:           a.Type,
+			Name:           a.Name,
+			Price:          a.Price,
+		})
+	}
+	return booked, nil
+}
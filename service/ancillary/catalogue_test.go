@@ -0,0 +1,76 @@
+package ancillary_test
+
+import (
+	"testing"
+
+	"github.com/elkoshar/bookcabin/service"
+	"github.com/elkoshar/bookcabin/service/ancillary"
+	"github.com/stretchr/testify/assert"
+)
+
+func codes(ancillaries []service.Ancillary) []string {
+	result := make([]string, len(ancillaries))
+	for i, a := range ancillaries {
+		result[i] = a.Code
+	}
+	return result
+}
+
+func TestCatalogue(t *testing.T) {
+	tests := []struct {
+		name   string
+		flight service.UnifiedFlight
+		want   []string
+	}{
+		{
+			name:   "cabin baggage only fare",
+			flight: service.UnifiedFlight{CabinClass: "economy", Baggage: &service.BaggageInfo{CabinPieces: 1, Note: "Cabin baggage only, checked bags additional fee"}},
+			want:   []string{"BAG15", "BAG20", "BAG30", "MEAL", "VGML", "PRIO", "INS", "INSPLUS"},
+		},
+		{
+			name:   "checked baggage and meal included",
+			flight: service.UnifiedFlight{CabinClass: "economy", MealIncluded: true, Baggage: &service.BaggageInfo{CabinKg: 7, CheckedKg: 20}},
+			want:   []string{"XBAG5", "XBAG10", "XBAG20", "PRIO", "INS", "INSPLUS"},
+		},
+		{
+			name:   "business cabin",
+			flight: service.UnifiedFlight{CabinClass: "business", MealIncluded: true, Baggage: &service.BaggageInfo{CheckedPieces: 2}},
+			want:   []string{"XBAG5", "XBAG10", "XBAG20", "INS", "INSPLUS"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			catalogue := ancillary.Catalogue(tt.flight)
+			assert.Equal(t, tt.want, codes(catalogue))
+			for _, a := range catalogue {
+				assert.Equal(t, "IDR", a.Price.Currency)
+				assert.Greater(t, a.Price.Amount, float64(0))
+			}
+		})
+	}
+}
+
+func TestBook(t *testing.T) {
+	flight := service.UnifiedFlight{CabinClass: "economy", Baggage: &service.BaggageInfo{CabinPieces: 1}}
+
+	booked, err := ancillary.Book(flight, 2, []service.AncillarySelection{
+		{PassengerIndex: 0, Code: "bag20"},
+		{PassengerIndex: 0, Code: "MEAL"},
+		{PassengerIndex: 1, Code: "BAG20"},
+	})
+	assert.NoError(t, err)
+	assert.Len(t, booked, 3)
+	assert.Equal(t, "BAG20", booked[0].Code)
+	assert.Equal(t, service.AncillaryBaggage, booked[0].Type)
+	assert.Equal(t, float64(320000), booked[0].Price.Amount)
+
+	_, err = ancillary.Book(flight, 2, []service.AncillarySelection{{PassengerIndex: 0, Code: "BAG15"}, {PassengerIndex: 0, Code: "BAG20"}})
+	assert.ErrorIs(t, err, service.ErrInvalidAncillarySelection)
+
+	_, err = ancillary.Book(flight, 2, []service.AncillarySelection{{PassengerIndex: 2, Code: "BAG15"}})
+	assert.ErrorIs(t, err, service.ErrInvalidAncillarySelection)
+
+	_, err = ancillary.Book(flight, 2, []service.AncillarySelection{{PassengerIndex: 0, Code: "XBAG5"}})
+	assert.ErrorIs(t, err, service.ErrInvalidAncillarySelection)
+}
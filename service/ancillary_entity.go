@@ -0,0 +1,55 @@
+package service
+
+import "errors"
+
+// Ancillary product types
+const (
+	AncillaryBaggage          = "baggage"
+	AncillaryMeal             = "meal"
+	AncillaryPriorityBoarding = "priority_boarding"
+	AncillaryInsurance        = "insurance"
+)
+
+var ErrInvalidAncillarySelection = errors.New("invalid ancillary selection")
+
+// Ancillary is an optional product sold with an offer, priced per passenger
+type Ancillary struct {
+	Code        string    `json:"code"`
+	Type        string    `json:"type"`
+	Name        string    `json:"name"`
+	Description string    `json:"description,omitempty"`
+	Price       PriceInfo `json:"price"`
+}
+
+type AncillaryCatalogue struct {
+	OfferID     string      `json:"offer_id"`
+	Ancillaries []Ancillary `json:"ancillaries"`
+}
+
+// AncillarySelectionRequest replaces the ancillaries attached to a held booking
+type AncillarySelectionRequest struct {
+	Ancillaries []AncillarySelection `json:"ancillaries" validate:"max=36,dive"`
+}
+
+type AncillarySelection struct {
+	// PassengerIndex is the position of the passenger in the booking, starting at 0
+	PassengerIndex int    `json:"passenger_index" validate:"min=0,max=8"`
+	Code           string `json:"code" validate:"required"`
+}
+
+// BookedAncillary is an ancillary attached to a passenger of a booking
+type BookedAncillary struct {
+	PassengerIndex int       `json:"passenger_index"`
+	Code           string    `json:"code"`
+	Type           string    `json:"type"`
+	Name           string    `json:"name"`
+	Price          PriceInfo `json:"price"`
+}
+
+// FareBreakdown splits a booking total into its components
+type FareBreakdown struct {
+	BaseFare    PriceInfo `json:"base_fare"`
+	Seats       PriceInfo `json:"seats"`
+	Ancillaries PriceInfo `json:"ancillaries"`
+	Total       PriceInfo `json:"total"`
+}
@@ -1,35 +1,155 @@
 package service
 
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidItinerary     = errors.New("invalid itinerary")
+	ErrProvidersUnavailable = errors.New("all flight providers failed")
+	ErrProvidersTimeout     = errors.New("all flight providers timed out")
+)
+
+// Supported values for SearchCriteria.TripType
+const (
+	TripTypeOneWay    = "one_way"
+	TripTypeRoundTrip = "round_trip"
+	TripTypeMultiCity = "multi_city"
+	TripTypeOpenJaw   = "open_jaw"
+	TripTypeStopover  = "stopover"
+)
+
 type SearchCriteria struct {
-	Origin        string
-	Destination   string
-	DepartureDate string
-	ReturnDate    string
-	Passengers    int
-	CabinClass    string
-	Segments      []RouteSegment //for multi-city searches
+	Origin        string         `validate:"required_without=Segments,omitempty,iata,airport"`
+	Destination   string         `validate:"required_without=Segments,omitempty,iata,airport,nefield=Origin"`
+	DepartureDate string         `validate:"required_without=Segments,omitempty,datetime=2006-01-02,not_past"`
+	ReturnDate    string         `validate:"omitempty,datetime=2006-01-02,date_gtefield=DepartureDate"`
+	Passengers    int            `validate:"min=1,max=9"`
+	CabinClass    string         `validate:"required,oneof=economy premium_economy business first"`
+	Segments      []RouteSegment `validate:"omitempty,max=6,chronological=DepartureDate,dive"` //for multi-city, open-jaw and stopover searches
+
+	// ExcludeDominated drops flights that are both pricier and slower than another result
+	ExcludeDominated bool `json:"exclude_dominated,omitempty"`
+
+	// TripType is inferred from ReturnDate and Segments when empty
+	TripType string `json:"trip_type,omitempty"`
+
+	// RefundableOnly keeps only offers whose fare can be refunded
+	RefundableOnly bool `json:"refundable_only,omitempty"`
+
+	// NearbyRadiusKm also searches every airport within this distance of the origin and destination
+	NearbyRadiusKm float64 `json:"nearby_radius_km,omitempty"`
+
+	// Currency converts every price of the results, they stay in IDR when empty
+	Currency string `json:"currency,omitempty" validate:"omitempty,oneof=IDR USD SGD MYR"`
+}
+
+// Normalize uppercases the airport and currency codes, so "cgk" passes the iata rule on every
+// transport. Call it before validating.
+func (c *SearchCriteria) Normalize() {
+	c.Origin = normalizeCode(c.Origin)
+	c.Destination = normalizeCode(c.Destination)
+	c.Currency = normalizeCode(c.Currency)
+	for i := range c.Segments {
+		c.Segments[i].Origin = normalizeCode(c.Segments[i].Origin)
+		c.Segments[i].Destination = normalizeCode(c.Segments[i].Destination)
+	}
+}
+
+func normalizeCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
 }
 
 type RouteSegment struct {
-	Origin        string
-	Destination   string
-	DepartureDate string
+	Origin        string `validate:"required,iata,airport"`
+	Destination   string `validate:"required,iata,airport,nefield=Origin"`
+	DepartureDate string `validate:"required,datetime=2006-01-02,not_past"`
 }
 
 type UnifiedFlight struct {
-	ID             string       `json:"id"`
-	Provider       string       `json:"provider"`
-	Airline        AirlineInfo  `json:"airline"`
-	FlightNumber   string       `json:"flight_number"`
-	Departure      LocationInfo `json:"departure"`
-	Arrival        LocationInfo `json:"arrival"`
-	Duration       DurationInfo `json:"duration"`
-	Stops          int          `json:"stops"`
-	Price          PriceInfo    `json:"price"`
-	AvailableSeats int          `json:"available_seats"`
-	CabinClass     string       `json:"cabin_class"`
-	Amenities      []string     `json:"amenities"`
-	Score          float64      `json:"-"`
+	ID             string            `json:"id"`
+	Provider       string            `json:"provider"`
+	Airline        AirlineInfo       `json:"airline"`
+	FlightNumber   string            `json:"flight_number"`
+	Departure      LocationInfo      `json:"departure"`
+	Arrival        LocationInfo      `json:"arrival"`
+	Duration       DurationInfo      `json:"duration"`
+	Stops          int               `json:"stops"`
+	Segments       []FlightSegment   `json:"segments,omitempty"`
+	Price          PriceInfo         `json:"price"`
+	AvailableSeats int               `json:"available_seats"`
+	CabinClass     string            `json:"cabin_class"`
+	Amenities      []string          `json:"amenities"`
+	Aircraft       string            `json:"aircraft,omitempty"`
+	Baggage        *BaggageInfo      `json:"baggage,omitempty"`
+	MealIncluded   bool              `json:"meal_included"`
+	FareRules      *FareRulesSummary `json:"fare_rules,omitempty"`
+	AirportMatch   *AirportMatch     `json:"airport_match,omitempty"`
+	Labels         []string          `json:"labels,omitempty"`
+	ParetoOptimal  bool              `json:"pareto_optimal"`
+
+	// OperatingCarrier and OperatingFlightNumber identify the physical flight behind a codeshare. None
+	// of the current provider payloads carry codeshare data, so the adapters leave them empty and the
+	// marketing carrier and flight number stand in for the operating ones
+	OperatingCarrier      string `json:"operating_carrier,omitempty"`
+	OperatingFlightNumber string `json:"operating_flight_number,omitempty"`
+
+	// AlternativeOffers lists pricier offers for the same physical flight merged into this one
+	AlternativeOffers []AlternativeOffer `json:"alternative_offers,omitempty"`
+
+	Score float64 `json:"-"`
+}
+
+// Labels attached to the standout flights of a result set
+const (
+	LabelCheapest       = "cheapest"
+	LabelFastest        = "fastest"
+	LabelBestValue      = "best_value"
+	LabelEarliest       = "earliest"
+	LabelDirectCheapest = "direct_cheapest"
+)
+
+// BaggageInfo is the baggage allowance included in the fare, zero values mean none or unknown
+type BaggageInfo struct {
+	CabinPieces   int    `json:"cabin_pieces,omitempty"`
+	CabinKg       int    `json:"cabin_kg,omitempty"`
+	CheckedPieces int    `json:"checked_pieces,omitempty"`
+	CheckedKg     int    `json:"checked_kg,omitempty"`
+	Note          string `json:"note,omitempty"`
+}
+
+// HasChecked reports whether the fare includes checked baggage
+func (b *BaggageInfo) HasChecked() bool {
+	return b != nil && (b.CheckedPieces > 0 || b.CheckedKg > 0)
+}
+
+// FlightSegment is one leg of a connecting flight flown under its own flight number
+type FlightSegment struct {
+	FlightNumber string       `json:"flight_number"`
+	Departure    LocationInfo `json:"departure"`
+	Arrival      LocationInfo `json:"arrival"`
+	Duration     DurationInfo `json:"duration"`
+}
+
+// AlternativeOffer is another provider's offer for a de-duplicated flight
+type AlternativeOffer struct {
+	ID           string    `json:"id"`
+	Provider     string    `json:"provider"`
+	FlightNumber string    `json:"flight_number"`
+	Price        PriceInfo `json:"price"`
+}
+
+// AirportMatch records which airports were actually searched when the requested
+// origin or destination was a metro code or nearby airports were included
+type AirportMatch struct {
+	RequestedOrigin       string  `json:"requested_origin"`
+	RequestedDestination  string  `json:"requested_destination"`
+	Origin                string  `json:"origin"`
+	Destination           string  `json:"destination"`
+	OriginDistanceKm      float64 `json:"origin_distance_km"`
+	DestinationDistanceKm float64 `json:"destination_distance_km"`
 }
 
 type AirlineInfo struct {
@@ -40,6 +160,7 @@ type AirlineInfo struct {
 type LocationInfo struct {
 	Airport   string `json:"airport"`
 	City      string `json:"city"`
+	Terminal  string `json:"terminal,omitempty"`
 	DateTime  string `json:"datetime"`
 	Timestamp int64  `json:"timestamp"`
 }
@@ -53,14 +174,24 @@ type PriceInfo struct {
 	Amount    float64 `json:"amount"`
 	Currency  string  `json:"currency"`
 	Formatted string  `json:"formatted,omitempty"`
+
+	// OriginalAmount and OriginalCurrency are what the provider sold the fare for when it was
+	// converted into another currency at ExchangeRate
+	OriginalAmount   float64 `json:"original_amount,omitempty"`
+	OriginalCurrency string  `json:"original_currency,omitempty"`
+	ExchangeRate     float64 `json:"exchange_rate,omitempty"`
 }
 
 type SearchResponse struct {
+	SearchID         string            `json:"search_id,omitempty"`
 	Criteria         SearchCriteria    `json:"search_criteria"`
 	Metadata         Metadata          `json:"metadata"`
 	Flights          []UnifiedFlight   `json:"flights"`
 	ReturnFlights    []UnifiedFlight   `json:"return_flights"`
 	MultiCityFlights [][]UnifiedFlight `json:"multi_city_flights,omitempty"`
+
+	// GeneratedAt is when the results were gathered, the creation time of the stored search snapshot
+	GeneratedAt time.Time `json:"-"`
 }
 
 type Metadata struct {
@@ -68,5 +199,7 @@ type Metadata struct {
 	ProvidersQueried   int   `json:"providers_queried"`
 	ProvidersSucceeded int   `json:"providers_succeeded"`
 	ProvidersFailed    int   `json:"providers_failed"`
+	DominatedRemoved   int   `json:"dominated_removed,omitempty"`
+	DuplicatesMerged   int   `json:"duplicates_merged"`
 	SearchTimeMs       int64 `json:"search_time_ms"`
 }
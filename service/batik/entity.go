@@ -5,14 +5,17 @@ type response struct {
 }
 
 type result struct {
-	FlightNumber      string `json:"flightNumber"`
-	AirlineName       string `json:"airlineName"`
-	Origin            string `json:"origin"`
-	Destination       string `json:"destination"`
-	DepartureDateTime string `json:"departureDateTime"` // Format: 2025-12-15T07:15:00+0700
-	ArrivalDateTime   string `json:"arrivalDateTime"`
-	Fare              fare   `json:"fare"`
-	SeatsAvailable    int    `json:"seatsAvailable"`
+	FlightNumber      string   `json:"flightNumber"`
+	AirlineName       string   `json:"airlineName"`
+	Origin            string   `json:"origin"`
+	Destination       string   `json:"destination"`
+	DepartureDateTime string   `json:"departureDateTime"` // Format: 2025-12-15T07:15:00+0700
+	ArrivalDateTime   string   `json:"arrivalDateTime"`
+	Fare              fare     `json:"fare"`
+	SeatsAvailable    int      `json:"seatsAvailable"`
+	AircraftModel     string   `json:"aircraftModel"`
+	BaggageInfo       string   `json:"baggageInfo"`
+	OnboardServices   []string `json:"onboardServices"`
 }
 
 type fare struct {
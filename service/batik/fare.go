@@ -0,0 +1,21 @@
+package batik
+
+import (
+	"strings"
+
+	entity "github.com/elkoshar/bookcabin/service"
+)
+
+// fareFamily maps a Batik Air booking class onto a fare family and its cabin. Y, B and M are
+// full economy, the discounted economy classes are lite, C, D, I and J are business.
+func fareFamily(class string) (family, cabin string) {
+	switch strings.ToUpper(class) {
+	case "V", "T", "Q", "N", "L":
+		return entity.FareFamilyLite, "economy"
+	case "C", "D", "I", "J", "BUSINESS":
+		return entity.FareFamilyBusiness, "business"
+	case "F", "FIRST":
+		return entity.FareFamilyFirst, "first"
+	}
+	return entity.FareFamilyEconomy, "economy"
+}
@@ -10,14 +10,20 @@ import (
 
 	"github.com/elkoshar/bookcabin/pkg/helpers"
 	entity "github.com/elkoshar/bookcabin/service"
+	"github.com/elkoshar/bookcabin/service/farerules"
+	"github.com/elkoshar/bookcabin/service/simulator"
 )
 
 type Provider struct {
 	dataPath string
+	booking  *simulator.BookingBackend
 }
 
 func New(path string) *Provider {
-	return &Provider{dataPath: path}
+	return &Provider{
+		dataPath: path,
+		booking:  simulator.NewBookingBackend(),
+	}
 }
 
 func (p *Provider) Name() string { return "Batik Air" }
@@ -39,17 +45,8 @@ func (p *Provider) Search(ctx context.Context, c entity.SearchCriteria) ([]entit
 		if f.Origin != c.Origin || f.Destination != c.Destination {
 			continue
 		}
-		targetClass := "Y" // Default Economy
-		switch strings.ToLower(c.CabinClass) {
-		case "business":
-			targetClass = "C"
-		case "first":
-			targetClass = "F"
-		case "economy":
-			targetClass = "Y"
-		}
-
-		if f.Fare.Class != targetClass {
+		family, cabin := fareFamily(f.Fare.Class)
+		if cabin != strings.ToLower(c.CabinClass) {
 			continue
 		}
 		layout := "2006-01-02T15:04:05-0700"
@@ -68,8 +65,10 @@ func (p *Provider) Search(ctx context.Context, c entity.SearchCriteria) ([]entit
 		originCity := helpers.GetCityName(f.Origin)
 		destinationCity := helpers.GetCityName(f.Destination)
 
+		offerID := entity.NewOfferID(entity.OfferKey{Provider: p.Name(), FlightNumber: f.FlightNumber, Origin: f.Origin, Destination: f.Destination, DepartureDate: c.DepartureDate, CabinClass: cabin})
+
 		results = append(results, entity.UnifiedFlight{
-			ID:             fmt.Sprintf("%s_Batik", f.FlightNumber),
+			ID:             offerID,
 			Provider:       p.Name(),
 			Airline:        entity.AirlineInfo{Name: f.AirlineName, Code: "ID"},
 			FlightNumber:   f.FlightNumber,
@@ -77,9 +76,28 @@ func (p *Provider) Search(ctx context.Context, c entity.SearchCriteria) ([]entit
 			Arrival:        entity.LocationInfo{Airport: f.Destination, City: destinationCity, DateTime: arrTime.Format(time.RFC3339), Timestamp: arrTime.Unix()},
 			Duration:       entity.DurationInfo{TotalMinutes: durationMins, Formatted: fmt.Sprintf("%dh %dm", durationMins/60, durationMins%60)},
 			Price:          entity.PriceInfo{Amount: f.Fare.TotalPrice, Currency: "IDR"},
-			AvailableSeats: f.SeatsAvailable,
-			CabinClass:     "economy",
+			AvailableSeats: f.SeatsAvailable - p.booking.HeldSeats(offerID),
+			CabinClass:     cabin,
+			Aircraft:       f.AircraftModel,
+			Baggage:        parseBaggage(f.BaggageInfo),
+			MealIncluded:   helpers.StringExists(f.OnboardServices, "Meal"),
+			FareRules:      farerules.Summary(family),
 		})
 	}
 	return results, nil
 }
+
+// parseBaggage reads allowances such as "7kg cabin, 20kg checked"
+func parseBaggage(info string) *entity.BaggageInfo {
+	baggage := &entity.BaggageInfo{Note: info}
+	for _, part := range strings.Split(info, ",") {
+		part = strings.ToLower(part)
+		switch {
+		case strings.Contains(part, "cabin"):
+			baggage.CabinKg = helpers.ParseWeightKg(part)
+		case strings.Contains(part, "checked"):
+			baggage.CheckedKg = helpers.ParseWeightKg(part)
+		}
+	}
+	return baggage
+}
@@ -70,7 +70,7 @@ func TestProvider_Search_Success(t *testing.T) {
 	assert.Len(t, flights, 1)
 
 	flight := flights[0]
-	assert.Equal(t, "ID6420_Batik", flight.ID)
+	assert.Equal(t, service.NewOfferID(service.OfferKey{Provider: "Batik Air", FlightNumber: "ID6420", Origin: "CGK", Destination: "DPS", DepartureDate: "2025-12-15", CabinClass: "economy"}), flight.ID)
 	assert.Equal(t, "Batik Air", flight.Provider)
 	assert.Equal(t, "Batik Air", flight.Airline.Name)
 	assert.Equal(t, "ID", flight.Airline.Code)
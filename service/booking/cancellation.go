@@ -0,0 +1,166 @@
+package booking
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/elkoshar/bookcabin/pkg/helpers"
+	"github.com/elkoshar/bookcabin/service"
+	"github.com/elkoshar/bookcabin/service/farerules"
+)
+
+// QuoteCancellation returns the refund a held or ticketed booking gets when it is cancelled now
+func (s *FlightBooking) QuoteCancellation(ctx context.Context, reference string) (service.CancellationQuote, error) {
+	booking, err := s.GetBooking(ctx, reference)
+	if err != nil {
+		return service.CancellationQuote{}, err
+	}
+
+	if !canTransition(booking.Status, service.BookingStatusCancelled) {
+		return service.CancellationQuote{}, service.ErrBookingNotCancellable
+	}
+
+	return quote(booking, time.Now()), nil
+}
+
+// ConfirmCancellation cancels a booking for the refund of a quote shown earlier. The quote is only
+// honoured while the booking and its refund are unchanged. Ticketed bookings with a refund move
+// on to refunded.
+func (s *FlightBooking) ConfirmCancellation(ctx context.Context, reference, quoteID string) (service.Booking, error) {
+	unlock := s.locks.lock(reference)
+	defer unlock()
+
+	booking, err := s.getBooking(ctx, reference)
+	if err != nil {
+		return service.Booking{}, err
+	}
+
+	if !canTransition(booking.Status, service.BookingStatusCancelled) {
+		return service.Booking{}, service.ErrBookingNotCancellable
+	}
+
+	current := quote(booking, time.Now())
+	if current.QuoteID != quoteID {
+		return service.Booking{}, service.ErrQuoteExpired
+	}
+
+	provider, ok := s.providers[booking.Flight.Provider]
+	if !ok {
+		return service.Booking{}, fmt.Errorf("provider %s does not support booking", booking.Flight.Provider)
+	}
+
+	// a ticketed booking was confirmed at the provider, a held one still has its hold
+	cancel := provider.CancelHold
+	if booking.Status == service.BookingStatusTicketed {
+		cancel = provider.CancelConfirmed
+	}
+	if err := cancel(ctx, booking.ProviderLocator); err != nil {
+		return service.Booking{}, err
+	}
+	if err := s.releasePayment(ctx, &booking); err != nil {
+		return service.Booking{}, err
+	}
+
+	events := make([]service.BookingEvent, 0, 2)
+	event, err := transition(&booking, service.BookingStatusCancelled, "cancelled by request")
+	if err != nil {
+		return service.Booking{}, err
+	}
+	events = append(events, event)
+
+	if current.RefundAmount.Amount > 0 {
+		if booking.Payment != nil {
+			payment, err := s.payments.Refund(ctx, booking.Payment.ID, current.RefundAmount.Amount)
+			if err != nil {
+				return service.Booking{}, err
+			}
+			booking.Payment = &payment
+		}
+
+		event, err = transition(&booking, service.BookingStatusRefunded, "refund of "+current.RefundAmount.Formatted)
+		if err != nil {
+			return service.Booking{}, err
+		}
+		events = append(events, event)
+	}
+	booking.Cancellation = &current
+
+	if err := s.store.SaveBooking(ctx, booking); err != nil {
+		return service.Booking{}, err
+	}
+	s.publish(ctx, events...)
+
+	slog.Info(fmt.Sprintf("[Booking] Cancelled %s with a refund of %s", booking.Reference, current.RefundAmount.Formatted))
+
+	return booking, nil
+}
+
+// quote computes the refund of a booking. Nothing has been paid for a held booking, a ticketed
+// booking gets back its base fare minus the fees of its fare family; seats and ancillaries are
+// not refunded.
+func quote(booking service.Booking, now time.Time) service.CancellationQuote {
+	currency := booking.Flight.Price.Currency
+	amount := func(v float64) service.PriceInfo {
+		return service.PriceInfo{Amount: v, Currency: currency, Formatted: helpers.FormatIDR(v)}
+	}
+
+	family := service.FareFamilyEconomy
+	if booking.Flight.FareRules != nil {
+		family = booking.Flight.FareRules.Family
+	}
+	summary := farerules.Summary(family)
+
+	// cancelling after departure is a no-show, flights without a departure time never are
+	var toDeparture time.Duration
+	if booking.Flight.Departure.Timestamp > 0 {
+		toDeparture = time.Unix(booking.Flight.Departure.Timestamp, 0).Sub(now)
+	}
+	noShow := booking.Flight.Departure.Timestamp > 0 && toDeparture <= 0
+
+	var paid float64
+	var refund farerules.Refund
+	if booking.Status == service.BookingStatusTicketed {
+		paid = booking.TotalPrice.Amount
+		refund = farerules.RefundAmount(summary.Family, booking.FareBreakdown.BaseFare.Amount, len(booking.Passengers), noShow)
+	}
+
+	q := service.CancellationQuote{
+		Reference:        booking.Reference,
+		Status:           booking.Status,
+		FareFamily:       summary.Family,
+		Refundable:       summary.Refundable,
+		NoShow:           noShow,
+		PaidAmount:       amount(paid),
+		CancellationFee:  amount(refund.CancellationFee),
+		NoShowFee:        amount(refund.NoShowFee),
+		NonRefundable:    amount(paid - refund.Amount),
+		RefundAmount:     amount(refund.Amount),
+		HoursToDeparture: float64(toDeparture.Round(time.Minute)) / float64(time.Hour),
+		QuotedAt:         now,
+	}
+	q.QuoteID = quoteID(booking, q)
+
+	return q
+}
+
+// quoteID identifies a quote by the booking version and the refund it offers, so a quote stays
+// valid until either of them changes
+func quoteID(booking service.Booking, q service.CancellationQuote) string {
+	h := sha256.New()
+	for _, part := range []string{
+		booking.Reference,
+		booking.Status,
+		booking.UpdatedAt.UTC().Format(time.RFC3339Nano),
+		strconv.FormatFloat(q.RefundAmount.Amount, 'f', 2, 64),
+		strconv.FormatBool(q.NoShow),
+	} {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))[:16]
+}
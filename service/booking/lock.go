@@ -0,0 +1,44 @@
+package booking
+
+import "sync"
+
+// bookingLocks serialises the changes to each booking, so two requests on the same reference can
+// neither overwrite each other's changes nor both move the booking on from the same status
+type bookingLocks struct {
+	mu    sync.Mutex
+	locks map[string]*bookingLock
+}
+
+type bookingLock struct {
+	mu sync.Mutex
+
+	// users counts the callers holding or waiting for mu, the lock is dropped when none are left
+	users int
+}
+
+// lock blocks until reference is free and returns the function that releases it
+func (l *bookingLocks) lock(reference string) func() {
+	l.mu.Lock()
+	if l.locks == nil {
+		l.locks = make(map[string]*bookingLock)
+	}
+	lk, ok := l.locks[reference]
+	if !ok {
+		lk = &bookingLock{}
+		l.locks[reference] = lk
+	}
+	lk.users++
+	l.mu.Unlock()
+
+	lk.mu.Lock()
+	return func() {
+		lk.mu.Unlock()
+
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		lk.users--
+		if lk.users == 0 {
+			delete(l.locks, reference)
+		}
+	}
+}
@@ -0,0 +1,182 @@
+package booking
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/elkoshar/bookcabin/service"
+	"github.com/elkoshar/bookcabin/service/ticket"
+)
+
+// Pay authorizes the total of a held booking on a card. An authorized payment is captured right
+// away and the booking is ticketed; a payment that needs a 3DS challenge leaves the booking held
+// until the gateway's webhook reports the outcome.
+func (s *FlightBooking) Pay(ctx context.Context, reference string, req service.PaymentRequest) (service.Booking, error) {
+	unlock := s.locks.lock(reference)
+	defer unlock()
+
+	booking, err := s.getBooking(ctx, reference)
+	if err != nil {
+		return service.Booking{}, err
+	}
+
+	if booking.Status != service.BookingStatusHeld {
+		return service.Booking{}, service.ErrBookingNotHeld
+	}
+	if booking.Payment != nil && booking.Payment.Status == service.PaymentStatusRequiresAction {
+		return service.Booking{}, service.ErrPaymentPending
+	}
+
+	payment, err := s.payments.Authorize(ctx, service.AuthorizeRequest{
+		Reference: booking.Reference,
+		Amount:    booking.TotalPrice,
+		Card:      req,
+	})
+	if payment.ID == "" {
+		return service.Booking{}, err
+	}
+
+	booking.Payment = &payment
+	if err != nil || payment.Status != service.PaymentStatusAuthorized {
+		if saveErr := s.store.SaveBooking(ctx, booking); saveErr != nil {
+			return service.Booking{}, saveErr
+		}
+		slog.Info(fmt.Sprintf("[Booking] Payment %s for %s is %s", payment.ID, booking.Reference, payment.Status))
+		return booking, err
+	}
+
+	return s.confirm(ctx, booking)
+}
+
+// HandlePaymentEvent applies a verified gateway notification to the booking it belongs to.
+// Repeated notifications are ignored, and payments authorized after the hold ran out are voided.
+func (s *FlightBooking) HandlePaymentEvent(ctx context.Context, event service.PaymentEvent) error {
+	unlock := s.locks.lock(event.Reference)
+	defer unlock()
+
+	booking, err := s.getBooking(ctx, event.Reference)
+	if err != nil {
+		return err
+	}
+
+	if booking.Payment == nil || booking.Payment.ID != event.PaymentID {
+		return service.ErrPaymentNotFound
+	}
+	if booking.Payment.Status != service.PaymentStatusRequiresAction {
+		return nil
+	}
+
+	switch event.Type {
+	case service.PaymentEventDeclined:
+		booking.Payment.Status = service.PaymentStatusDeclined
+	case service.PaymentEventAuthorized:
+		if booking.Status == service.BookingStatusHeld {
+			booking.Payment.Status = service.PaymentStatusAuthorized
+			_, err := s.confirm(ctx, booking)
+			return err
+		}
+
+		payment, err := s.payments.Void(ctx, booking.Payment.ID)
+		if err != nil {
+			return err
+		}
+		booking.Payment = &payment
+	default:
+		return nil
+	}
+
+	if err := s.store.SaveBooking(ctx, booking); err != nil {
+		return err
+	}
+	slog.Info(fmt.Sprintf("[Booking] Payment %s for %s is %s", booking.Payment.ID, booking.Reference, booking.Payment.Status))
+
+	return nil
+}
+
+// confirm captures the authorized payment of a held booking, confirms its hold at the provider
+// and issues its e-tickets
+func (s *FlightBooking) confirm(ctx context.Context, booking service.Booking) (service.Booking, error) {
+	provider, ok := s.providers[booking.Flight.Provider]
+	if !ok {
+		return service.Booking{}, fmt.Errorf("provider %s does not support booking", booking.Flight.Provider)
+	}
+
+	payment, err := s.payments.Capture(ctx, booking.Payment.ID, booking.TotalPrice.Amount)
+	if err != nil {
+		return service.Booking{}, err
+	}
+	booking.Payment = &payment
+
+	// an unconfirmed hold gives its seats back once it expires, so the booking cannot be ticketed
+	if err := provider.ConfirmHold(ctx, booking.ProviderLocator); err != nil {
+		return service.Booking{}, s.reverseCapture(ctx, booking, err)
+	}
+
+	booking.Tickets, err = ticket.Issue(booking)
+	if err != nil {
+		return service.Booking{}, err
+	}
+
+	event, err := transition(&booking, service.BookingStatusTicketed, "payment "+payment.ID+" captured")
+	if err != nil {
+		return service.Booking{}, err
+	}
+
+	if err := s.store.SaveBooking(ctx, booking); err != nil {
+		return service.Booking{}, err
+	}
+	s.publish(ctx, event)
+
+	slog.Info(fmt.Sprintf("[Booking] Captured %s for %s, issued %d e-ticket(s)", payment.CapturedAmount.Formatted, booking.Reference, len(booking.Tickets)))
+
+	return booking, nil
+}
+
+// reverseCapture refunds the whole captured payment of a booking whose hold could not be confirmed,
+// a captured payment can no longer be voided. It returns cause, or the refund error when that fails too.
+func (s *FlightBooking) reverseCapture(ctx context.Context, booking service.Booking, cause error) error {
+	payment, err := s.payments.Refund(ctx, booking.Payment.ID, booking.Payment.CapturedAmount.Amount)
+	if err != nil {
+		return fmt.Errorf("refund payment %s after %w: %w", booking.Payment.ID, cause, err)
+	}
+	booking.Payment = &payment
+
+	if err := s.store.SaveBooking(ctx, booking); err != nil {
+		return err
+	}
+	slog.Info(fmt.Sprintf("[Booking] Refunded payment %s for %s, hold %s could not be confirmed: %v", payment.ID, booking.Reference, booking.ProviderLocator, cause))
+	return cause
+}
+
+// releasePayment voids a payment that was authorized or awaits authentication for a booking
+// that is cancelled before it was confirmed
+func (s *FlightBooking) releasePayment(ctx context.Context, booking *service.Booking) error {
+	if booking.Payment == nil {
+		return nil
+	}
+	if booking.Payment.Status != service.PaymentStatusAuthorized && booking.Payment.Status != service.PaymentStatusRequiresAction {
+		return nil
+	}
+
+	payment, err := s.payments.Void(ctx, booking.Payment.ID)
+	if err != nil {
+		return err
+	}
+	booking.Payment = &payment
+	return nil
+}
+
+// TicketDocument renders the itinerary receipt of a ticketed booking as a PDF or HTML document
+func (s *FlightBooking) TicketDocument(ctx context.Context, reference, format string) (service.Document, error) {
+	booking, err := s.GetBooking(ctx, reference)
+	if err != nil {
+		return service.Document{}, err
+	}
+
+	if booking.Status != service.BookingStatusTicketed {
+		return service.Document{}, service.ErrBookingNotTicketed
+	}
+
+	return ticket.Render(booking, format)
+}
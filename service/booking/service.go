@@ -0,0 +1,369 @@
+package booking
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/elkoshar/bookcabin/api"
+	"github.com/elkoshar/bookcabin/pkg/helpers"
+	"github.com/elkoshar/bookcabin/service"
+	"github.com/elkoshar/bookcabin/service/ancillary"
+)
+
+type FlightBooking struct {
+	holdTTL    time.Duration
+	tolerance  float64
+	aggregator api.FlightAggregator
+	providers  map[string]api.BookingProvider
+	store      api.Storage
+	events     api.EventPublisher
+	payments   api.PaymentGateway
+
+	// mu makes picking an unused reference and saving the booking atomic
+	mu sync.Mutex
+
+	// locks serialises the changes to an existing booking
+	locks bookingLocks
+}
+
+// NewBooking creates the booking service. priceTolerance is the fraction (0.02 = 2%) by which an
+// offer's price may move between search and booking before the booking is rejected. Status
+// changes are published to events and bookings are paid through payments.
+func NewBooking(holdTTL time.Duration, priceTolerance float64, aggregator api.FlightAggregator, store api.Storage, events api.EventPublisher, payments api.PaymentGateway, providers ...api.BookingProvider) *FlightBooking {
+	byName := make(map[string]api.BookingProvider, len(providers))
+	for _, p := range providers {
+		byName[p.Name()] = p
+	}
+
+	return &FlightBooking{
+		holdTTL:    holdTTL,
+		tolerance:  priceTolerance,
+		aggregator: aggregator,
+		providers:  byName,
+		store:      store,
+		events:     events,
+		payments:   payments,
+	}
+}
+
+// CreateBooking revalidates the offer, holds seats at its provider and returns a booking reference
+func (s *FlightBooking) CreateBooking(ctx context.Context, req service.BookingRequest) (service.Booking, error) {
+	flight, err := s.revalidate(ctx, req)
+	if err != nil {
+		return service.Booking{}, err
+	}
+
+	provider, ok := s.providers[flight.Provider]
+	if !ok {
+		return service.Booking{}, fmt.Errorf("provider %s does not support booking", flight.Provider)
+	}
+
+	ancillaries, err := ancillary.Book(flight, len(req.Passengers), req.Ancillaries)
+	if err != nil {
+		return service.Booking{}, err
+	}
+
+	now := time.Now()
+	expiresAt := now.Add(s.holdTTL)
+
+	hold, err := provider.CreateHold(ctx, service.HoldRequest{
+		Flight:     flight,
+		Passengers: req.Passengers,
+		Contact:    req.Contact,
+		ExpiresAt:  expiresAt,
+	})
+	if err != nil {
+		return service.Booking{}, err
+	}
+
+	booking := service.Booking{
+		Status:          service.BookingStatusHeld,
+		OfferID:         req.OfferID,
+		Flight:          flight,
+		Passengers:      req.Passengers,
+		Contact:         req.Contact,
+		ProviderLocator: hold.Locator,
+		Ancillaries:     ancillaries,
+		HoldExpiresAt:   hold.ExpiresAt,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+	s.price(&booking)
+
+	s.mu.Lock()
+	booking.Reference, err = s.newReference(ctx)
+	if err == nil {
+		err = s.store.SaveBooking(ctx, booking)
+	}
+	s.mu.Unlock()
+
+	if err != nil {
+		if cancelErr := provider.CancelHold(ctx, hold.Locator); cancelErr != nil {
+			slog.Error(fmt.Sprintf("[Booking] Failed to release hold %s after storage error: %v", hold.Locator, cancelErr))
+		}
+		return service.Booking{}, err
+	}
+
+	slog.Info(fmt.Sprintf("[Booking] Held %s on %s %s for %d passenger(s), provider locator %s", booking.Reference, flight.Provider, flight.FlightNumber, len(req.Passengers), hold.Locator))
+
+	return booking, nil
+}
+
+// revalidate reprices the offer and rejects it when sold out or when its price moved beyond the tolerance
+func (s *FlightBooking) revalidate(ctx context.Context, req service.BookingRequest) (service.UnifiedFlight, error) {
+	result, err := s.aggregator.Reprice(ctx, service.RepriceRequest{
+		ID:             req.OfferID,
+		Passengers:     len(req.Passengers),
+		ExpectedAmount: req.ExpectedAmount,
+	})
+	if err != nil {
+		return service.UnifiedFlight{}, err
+	}
+
+	switch result.Status {
+	case service.RepriceStatusSoldOut:
+		return service.UnifiedFlight{}, service.ErrOfferSoldOut
+	case service.RepriceStatusPriceChanged:
+		if math.Abs(result.NewAmount-result.OldAmount) > result.OldAmount*s.tolerance {
+			return service.UnifiedFlight{}, fmt.Errorf("%w: %s to %s", service.ErrPriceChanged, helpers.FormatIDR(result.OldAmount), helpers.FormatIDR(result.NewAmount))
+		}
+	}
+
+	return *result.Flight, nil
+}
+
+// GetBooking returns a booking by its reference. Held bookings are checked against the
+// provider and marked expired once their hold has been released or ran out of time.
+func (s *FlightBooking) GetBooking(ctx context.Context, reference string) (service.Booking, error) {
+	unlock := s.locks.lock(reference)
+	defer unlock()
+
+	return s.getBooking(ctx, reference)
+}
+
+// getBooking is GetBooking for callers that already hold the lock of reference
+func (s *FlightBooking) getBooking(ctx context.Context, reference string) (service.Booking, error) {
+	booking, err := s.store.GetBooking(ctx, reference)
+	if err != nil {
+		return service.Booking{}, err
+	}
+
+	if booking.Status != service.BookingStatusHeld {
+		return booking, nil
+	}
+
+	expired := time.Now().After(booking.HoldExpiresAt)
+	if provider, ok := s.providers[booking.Flight.Provider]; ok && !expired {
+		// a hold the provider no longer knows about, e.g. after it restarted, has lapsed
+		hold, err := provider.RetrieveHold(ctx, booking.ProviderLocator)
+		if err != nil && !errors.Is(err, service.ErrHoldNotFound) {
+			return service.Booking{}, err
+		}
+		expired = err != nil || hold.Status != service.HoldStatusActive
+	}
+
+	if expired {
+		event, err := transition(&booking, service.BookingStatusExpired, "hold expired")
+		if err != nil {
+			return service.Booking{}, err
+		}
+
+		if err := s.store.SaveBooking(ctx, booking); err != nil {
+			return service.Booking{}, err
+		}
+		s.publish(ctx, event)
+	}
+
+	return booking, nil
+}
+
+// CancelBooking releases the provider hold of a held booking, ticketed bookings are cancelled
+// through a cancellation quote
+func (s *FlightBooking) CancelBooking(ctx context.Context, reference string) (service.Booking, error) {
+	unlock := s.locks.lock(reference)
+	defer unlock()
+
+	booking, err := s.getBooking(ctx, reference)
+	if err != nil {
+		return service.Booking{}, err
+	}
+
+	if booking.Status != service.BookingStatusHeld {
+		return service.Booking{}, service.ErrBookingNotCancellable
+	}
+
+	provider, ok := s.providers[booking.Flight.Provider]
+	if !ok {
+		return service.Booking{}, fmt.Errorf("provider %s does not support booking", booking.Flight.Provider)
+	}
+
+	if err := provider.CancelHold(ctx, booking.ProviderLocator); err != nil {
+		return service.Booking{}, err
+	}
+	if err := s.releasePayment(ctx, &booking); err != nil {
+		return service.Booking{}, err
+	}
+
+	event, err := transition(&booking, service.BookingStatusCancelled, "hold released")
+	if err != nil {
+		return service.Booking{}, err
+	}
+
+	if err := s.store.SaveBooking(ctx, booking); err != nil {
+		return service.Booking{}, err
+	}
+	s.publish(ctx, event)
+
+	slog.Info(fmt.Sprintf("[Booking] Cancelled %s, provider locator %s", booking.Reference, booking.ProviderLocator))
+
+	return booking, nil
+}
+
+// SelectSeats reserves seats for passengers of a held booking. Passengers that already have a
+// seat and are not part of the request keep it. Seat prices are added to the booking total.
+func (s *FlightBooking) SelectSeats(ctx context.Context, reference string, req service.SeatSelectionRequest) (service.Booking, error) {
+	unlock := s.locks.lock(reference)
+	defer unlock()
+
+	booking, err := s.getBooking(ctx, reference)
+	if err != nil {
+		return service.Booking{}, err
+	}
+
+	if booking.Status != service.BookingStatusHeld {
+		return service.Booking{}, service.ErrBookingNotHeld
+	}
+
+	provider, ok := s.providers[booking.Flight.Provider].(api.SeatMapProvider)
+	if !ok {
+		return service.Booking{}, service.ErrSeatMapUnavailable
+	}
+
+	byPassenger := make(map[int]string)
+	for _, assigned := range booking.Seats {
+		byPassenger[assigned.PassengerIndex] = assigned.SeatNumber
+	}
+
+	requested := make(map[int]bool)
+	for _, assignment := range req.Seats {
+		if assignment.PassengerIndex < 0 || assignment.PassengerIndex >= len(booking.Passengers) {
+			return service.Booking{}, fmt.Errorf("%w: booking has no passenger %d", service.ErrInvalidSeatSelection, assignment.PassengerIndex)
+		}
+		if requested[assignment.PassengerIndex] {
+			return service.Booking{}, fmt.Errorf("%w: passenger %d has more than one seat", service.ErrInvalidSeatSelection, assignment.PassengerIndex)
+		}
+		requested[assignment.PassengerIndex] = true
+		byPassenger[assignment.PassengerIndex] = strings.ToUpper(assignment.SeatNumber)
+	}
+
+	passengers := make([]int, 0, len(byPassenger))
+	seatNumbers := make([]string, 0, len(byPassenger))
+	seen := make(map[string]bool)
+	for i := range booking.Passengers {
+		number, ok := byPassenger[i]
+		if !ok {
+			continue
+		}
+		if seen[number] {
+			return service.Booking{}, fmt.Errorf("%w: seat %s selected twice", service.ErrInvalidSeatSelection, number)
+		}
+		seen[number] = true
+		passengers = append(passengers, i)
+		seatNumbers = append(seatNumbers, number)
+	}
+
+	seats, err := provider.ReserveSeats(ctx, booking.ProviderLocator, seatNumbers)
+	if err != nil {
+		return service.Booking{}, err
+	}
+
+	booking.Seats = make([]service.SeatAssignment, len(seats))
+	for i, seat := range seats {
+		booking.Seats[i] = service.SeatAssignment{PassengerIndex: passengers[i], SeatNumber: seat.Number, Price: seat.Price}
+	}
+	s.price(&booking)
+	booking.UpdatedAt = time.Now()
+
+	if err := s.store.SaveBooking(ctx, booking); err != nil {
+		return service.Booking{}, err
+	}
+
+	slog.Info(fmt.Sprintf("[Booking] Reserved seats %s on %s", strings.Join(seatNumbers, ","), booking.Reference))
+
+	return booking, nil
+}
+
+// SelectAncillaries replaces the ancillaries attached to a held booking and reprices it
+func (s *FlightBooking) SelectAncillaries(ctx context.Context, reference string, req service.AncillarySelectionRequest) (service.Booking, error) {
+	unlock := s.locks.lock(reference)
+	defer unlock()
+
+	booking, err := s.getBooking(ctx, reference)
+	if err != nil {
+		return service.Booking{}, err
+	}
+
+	if booking.Status != service.BookingStatusHeld {
+		return service.Booking{}, service.ErrBookingNotHeld
+	}
+
+	booking.Ancillaries, err = ancillary.Book(booking.Flight, len(booking.Passengers), req.Ancillaries)
+	if err != nil {
+		return service.Booking{}, err
+	}
+	s.price(&booking)
+	booking.UpdatedAt = time.Now()
+
+	if err := s.store.SaveBooking(ctx, booking); err != nil {
+		return service.Booking{}, err
+	}
+
+	slog.Info(fmt.Sprintf("[Booking] Attached %d ancillaries to %s", len(booking.Ancillaries), booking.Reference))
+
+	return booking, nil
+}
+
+// price fills the fare breakdown and total: the fare of every passenger, selected seats and ancillaries
+func (s *FlightBooking) price(booking *service.Booking) {
+	currency := booking.Flight.Price.Currency
+	amount := func(v float64) service.PriceInfo {
+		return service.PriceInfo{Amount: v, Currency: currency, Formatted: helpers.FormatIDR(v)}
+	}
+
+	var seats, ancillaries float64
+	for _, seat := range booking.Seats {
+		seats += seat.Price.Amount
+	}
+	for _, a := range booking.Ancillaries {
+		ancillaries += a.Price.Amount
+	}
+	base := booking.Flight.Price.Amount * float64(len(booking.Passengers))
+
+	booking.FareBreakdown = service.FareBreakdown{
+		BaseFare:    amount(base),
+		Seats:       amount(seats),
+		Ancillaries: amount(ancillaries),
+		Total:       amount(base + seats + ancillaries),
+	}
+	booking.TotalPrice = booking.FareBreakdown.Total
+}
+
+// newReference returns an unused booking reference, the caller must hold the lock
+func (s *FlightBooking) newReference(ctx context.Context) (string, error) {
+	for {
+		ref := helpers.GenerateLocator(6)
+		_, err := s.store.GetBooking(ctx, ref)
+		if errors.Is(err, service.ErrBookingNotFound) {
+			return ref, nil
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+}
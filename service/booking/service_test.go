@@ -0,0 +1,695 @@
+package booking_test
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/elkoshar/bookcabin/service"
+	"github.com/elkoshar/bookcabin/service/booking"
+	"github.com/elkoshar/bookcabin/service/events"
+	"github.com/elkoshar/bookcabin/service/farerules"
+	"github.com/elkoshar/bookcabin/service/payment"
+	"github.com/elkoshar/bookcabin/service/simulator"
+	"github.com/elkoshar/bookcabin/service/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockAggregator implements api.FlightAggregator for testing
+type MockAggregator struct {
+	mock.Mock
+}
+
+func (m *MockAggregator) SearchAll(ctx context.Context, criteria service.SearchCriteria) (service.SearchResponse, error) {
+	args := m.Called(ctx, criteria)
+	return args.Get(0).(service.SearchResponse), args.Error(1)
+}
+
+func (m *MockAggregator) SearchStream(ctx context.Context, criteria service.SearchCriteria) (<-chan service.SearchEvent, error) {
+	args := m.Called(ctx, criteria)
+	events, _ := args.Get(0).(<-chan service.SearchEvent)
+	return events, args.Error(1)
+}
+
+func (m *MockAggregator) GetFlight(ctx context.Context, id string) (service.UnifiedFlight, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(service.UnifiedFlight), args.Error(1)
+}
+
+func (m *MockAggregator) Reprice(ctx context.Context, req service.RepriceRequest) (service.RepriceResult, error) {
+	args := m.Called(ctx, req)
+	return args.Get(0).(service.RepriceResult), args.Error(1)
+}
+
+func (m *MockAggregator) SeatMap(ctx context.Context, offerID string) (service.SeatMap, error) {
+	args := m.Called(ctx, offerID)
+	return args.Get(0).(service.SeatMap), args.Error(1)
+}
+
+func (m *MockAggregator) Ancillaries(ctx context.Context, offerID string) (service.AncillaryCatalogue, error) {
+	args := m.Called(ctx, offerID)
+	return args.Get(0).(service.AncillaryCatalogue), args.Error(1)
+}
+
+func (m *MockAggregator) FareRules(ctx context.Context, offerID string) (service.FareRules, error) {
+	args := m.Called(ctx, offerID)
+	return args.Get(0).(service.FareRules), args.Error(1)
+}
+
+// SimulatedProvider is a booking provider backed by the offline simulator
+type SimulatedProvider struct {
+	*simulator.BookingBackend
+	name string
+}
+
+func (p *SimulatedProvider) Name() string { return p.name }
+
+func newProvider() *SimulatedProvider {
+	return &SimulatedProvider{BookingBackend: simulator.NewBookingBackend(), name: "Garuda Indonesia"}
+}
+
+var (
+	testFlight = service.UnifiedFlight{
+		ID:             "offer-1",
+		Provider:       "Garuda Indonesia",
+		FlightNumber:   "GA400",
+		Price:          service.PriceInfo{Amount: 1250000, Currency: "IDR"},
+		AvailableSeats: 2,
+	}
+	testPassengers = []service.Passenger{
+		{Title: "MR", FirstName: "Budi", LastName: "Santoso", DateOfBirth: "1990-01-01"},
+		{Title: "MRS", FirstName: "Siti", LastName: "Santoso", DateOfBirth: "1991-02-02"},
+	}
+	testContact = service.ContactInfo{Email: "budi@example.com", Phone: "+628123456789"}
+)
+
+const webhookSecret = "test-secret"
+
+func unchanged(flight service.UnifiedFlight) service.RepriceResult {
+	return service.RepriceResult{
+		ID:        flight.ID,
+		Status:    service.RepriceStatusUnchanged,
+		OldAmount: flight.Price.Amount,
+		NewAmount: flight.Price.Amount,
+		Flight:    &flight,
+	}
+}
+
+func TestFlightBooking_CreateRetrieveCancel(t *testing.T) {
+	agg := &MockAggregator{}
+	agg.On("Reprice", mock.Anything, mock.Anything).Return(unchanged(testFlight), nil)
+
+	svc := booking.NewBooking(15*time.Minute, 0.02, agg, storage.NewMemory(), events.NewBus(), payment.NewFake(webhookSecret), newProvider())
+	ctx := context.Background()
+
+	created, err := svc.CreateBooking(ctx, service.BookingRequest{OfferID: "offer-1", Passengers: testPassengers, Contact: testContact})
+	assert.NoError(t, err)
+	assert.Len(t, created.Reference, 6)
+	assert.Len(t, created.ProviderLocator, 6)
+	assert.Equal(t, service.BookingStatusHeld, created.Status)
+	assert.Equal(t, float64(2500000), created.TotalPrice.Amount)
+	assert.WithinDuration(t, time.Now().Add(15*time.Minute), created.HoldExpiresAt, time.Minute)
+
+	retrieved, err := svc.GetBooking(ctx, created.Reference)
+	assert.NoError(t, err)
+	assert.Equal(t, created.Reference, retrieved.Reference)
+	assert.Equal(t, service.BookingStatusHeld, retrieved.Status)
+
+	cancelled, err := svc.CancelBooking(ctx, created.Reference)
+	assert.NoError(t, err)
+	assert.Equal(t, service.BookingStatusCancelled, cancelled.Status)
+
+	_, err = svc.CancelBooking(ctx, created.Reference)
+	assert.ErrorIs(t, err, service.ErrBookingNotCancellable)
+}
+
+// SlowCancelProvider takes a while to release a hold, so concurrent cancellations overlap
+type SlowCancelProvider struct {
+	*SimulatedProvider
+}
+
+func (p *SlowCancelProvider) CancelHold(ctx context.Context, locator string) error {
+	time.Sleep(10 * time.Millisecond)
+	return p.SimulatedProvider.CancelHold(ctx, locator)
+}
+
+func TestFlightBooking_CancelBooking_Concurrent(t *testing.T) {
+	agg := &MockAggregator{}
+	agg.On("Reprice", mock.Anything, mock.Anything).Return(unchanged(testFlight), nil)
+
+	svc := booking.NewBooking(15*time.Minute, 0.02, agg, storage.NewMemory(), events.NewBus(), payment.NewFake(webhookSecret), &SlowCancelProvider{newProvider()})
+	ctx := context.Background()
+
+	created, err := svc.CreateBooking(ctx, service.BookingRequest{OfferID: "offer-1", Passengers: testPassengers, Contact: testContact})
+	assert.NoError(t, err)
+
+	const requests = 10
+	errs := make(chan error, requests)
+	var wg sync.WaitGroup
+	for i := 0; i < requests; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := svc.CancelBooking(ctx, created.Reference)
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	// only one request sees the booking held, the others see it cancelled
+	cancelled := 0
+	for err := range errs {
+		if err == nil {
+			cancelled++
+			continue
+		}
+		assert.ErrorIs(t, err, service.ErrBookingNotCancellable)
+	}
+	assert.Equal(t, 1, cancelled)
+
+	retrieved, err := svc.GetBooking(ctx, created.Reference)
+	assert.NoError(t, err)
+	assert.Equal(t, service.BookingStatusCancelled, retrieved.Status)
+	assert.Len(t, retrieved.History, len(created.History)+1)
+}
+
+func TestFlightBooking_CreateBooking_InsufficientSeats(t *testing.T) {
+	flight := testFlight
+	flight.AvailableSeats = 1
+
+	agg := &MockAggregator{}
+	agg.On("Reprice", mock.Anything, mock.Anything).Return(unchanged(flight), nil)
+
+	svc := booking.NewBooking(15*time.Minute, 0.02, agg, storage.NewMemory(), events.NewBus(), payment.NewFake(webhookSecret), newProvider())
+
+	_, err := svc.CreateBooking(context.Background(), service.BookingRequest{OfferID: "offer-1", Passengers: testPassengers, Contact: testContact})
+	assert.ErrorIs(t, err, service.ErrInsufficientSeats)
+}
+
+func TestFlightBooking_CreateBooking_SoldOut(t *testing.T) {
+	agg := &MockAggregator{}
+	agg.On("Reprice", mock.Anything, mock.Anything).Return(service.RepriceResult{ID: "gone", Status: service.RepriceStatusSoldOut}, nil)
+
+	svc := booking.NewBooking(15*time.Minute, 0.02, agg, storage.NewMemory(), events.NewBus(), payment.NewFake(webhookSecret), newProvider())
+
+	_, err := svc.CreateBooking(context.Background(), service.BookingRequest{OfferID: "gone", Passengers: testPassengers, Contact: testContact})
+	assert.ErrorIs(t, err, service.ErrOfferSoldOut)
+}
+
+func TestFlightBooking_CreateBooking_PriceTolerance(t *testing.T) {
+	tests := []struct {
+		name      string
+		newAmount float64
+		wantErr   error
+	}{
+		{name: "within tolerance", newAmount: 1270000},
+		{name: "beyond tolerance", newAmount: 1300000, wantErr: service.ErrPriceChanged},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			flight := testFlight
+			flight.Price.Amount = tt.newAmount
+
+			agg := &MockAggregator{}
+			agg.On("Reprice", mock.Anything, service.RepriceRequest{ID: "offer-1", Passengers: 1, ExpectedAmount: 1250000}).Return(service.RepriceResult{
+				ID:        "offer-1",
+				Status:    service.RepriceStatusPriceChanged,
+				OldAmount: 1250000,
+				NewAmount: tt.newAmount,
+				Flight:    &flight,
+			}, nil)
+
+			svc := booking.NewBooking(15*time.Minute, 0.02, agg, storage.NewMemory(), events.NewBus(), payment.NewFake(webhookSecret), newProvider())
+
+			created, err := svc.CreateBooking(context.Background(), service.BookingRequest{
+				OfferID:        "offer-1",
+				Passengers:     testPassengers[:1],
+				Contact:        testContact,
+				ExpectedAmount: 1250000,
+			})
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.newAmount, created.TotalPrice.Amount)
+		})
+	}
+}
+
+func TestFlightBooking_GetBooking_Expired(t *testing.T) {
+	agg := &MockAggregator{}
+	agg.On("Reprice", mock.Anything, mock.Anything).Return(unchanged(testFlight), nil)
+
+	svc := booking.NewBooking(time.Millisecond, 0.02, agg, storage.NewMemory(), events.NewBus(), payment.NewFake(webhookSecret), newProvider())
+	ctx := context.Background()
+
+	created, err := svc.CreateBooking(ctx, service.BookingRequest{OfferID: "offer-1", Passengers: testPassengers[:1], Contact: testContact})
+	assert.NoError(t, err)
+
+	time.Sleep(5 * time.Millisecond)
+
+	retrieved, err := svc.GetBooking(ctx, created.Reference)
+	assert.NoError(t, err)
+	assert.Equal(t, service.BookingStatusExpired, retrieved.Status)
+
+	_, err = svc.GetBooking(ctx, "NOPE00")
+	assert.ErrorIs(t, err, service.ErrBookingNotFound)
+}
+
+// SeatingProvider adds seat selection to the simulated provider
+type SeatingProvider struct {
+	*SimulatedProvider
+}
+
+func (p *SeatingProvider) SeatMap(ctx context.Context, offerID string) (service.SeatMap, error) {
+	return service.SeatMap{}, service.ErrSeatMapUnavailable
+}
+
+// availableSeats returns the numbers of the first n free seats of a flight
+func availableSeats(t *testing.T, flight service.UnifiedFlight, n int) []service.Seat {
+	seatMap, err := simulator.GenerateSeatMap(flight, nil)
+	assert.NoError(t, err)
+
+	var seats []service.Seat
+	for _, row := range seatMap.Cabins[0].Rows {
+		for _, seat := range row.Seats {
+			if seat.Available && len(seats) < n {
+				seats = append(seats, seat)
+			}
+		}
+	}
+	return seats
+}
+
+func TestFlightBooking_SelectSeats(t *testing.T) {
+	flight := testFlight
+	flight.Aircraft = "Airbus A320"
+	flight.CabinClass = "economy"
+
+	agg := &MockAggregator{}
+	agg.On("Reprice", mock.Anything, mock.Anything).Return(unchanged(flight), nil)
+
+	svc := booking.NewBooking(15*time.Minute, 0.02, agg, storage.NewMemory(), events.NewBus(), payment.NewFake(webhookSecret), &SeatingProvider{newProvider()})
+	ctx := context.Background()
+
+	created, err := svc.CreateBooking(ctx, service.BookingRequest{OfferID: "offer-1", Passengers: testPassengers, Contact: testContact})
+	assert.NoError(t, err)
+
+	seats := availableSeats(t, flight, 3)
+
+	updated, err := svc.SelectSeats(ctx, created.Reference, service.SeatSelectionRequest{Seats: []service.SeatAssignment{
+		{PassengerIndex: 0, SeatNumber: seats[0].Number},
+		{PassengerIndex: 1, SeatNumber: seats[1].Number},
+	}})
+	assert.NoError(t, err)
+	assert.Len(t, updated.Seats, 2)
+	assert.Equal(t, created.TotalPrice.Amount+seats[0].Price.Amount+seats[1].Price.Amount, updated.TotalPrice.Amount)
+
+	// changing one passenger's seat keeps the other
+	updated, err = svc.SelectSeats(ctx, created.Reference, service.SeatSelectionRequest{Seats: []service.SeatAssignment{
+		{PassengerIndex: 0, SeatNumber: seats[2].Number},
+	}})
+	assert.NoError(t, err)
+	assert.Equal(t, seats[2].Number, updated.Seats[0].SeatNumber)
+	assert.Equal(t, seats[1].Number, updated.Seats[1].SeatNumber)
+
+	_, err = svc.SelectSeats(ctx, created.Reference, service.SeatSelectionRequest{Seats: []service.SeatAssignment{
+		{PassengerIndex: 0, SeatNumber: seats[1].Number},
+	}})
+	assert.ErrorIs(t, err, service.ErrInvalidSeatSelection)
+
+	_, err = svc.SelectSeats(ctx, created.Reference, service.SeatSelectionRequest{Seats: []service.SeatAssignment{
+		{PassengerIndex: 5, SeatNumber: seats[0].Number},
+	}})
+	assert.ErrorIs(t, err, service.ErrInvalidSeatSelection)
+
+	_, err = svc.SelectSeats(ctx, created.Reference, service.SeatSelectionRequest{Seats: []service.SeatAssignment{
+		{PassengerIndex: -1, SeatNumber: seats[0].Number},
+	}})
+	assert.ErrorIs(t, err, service.ErrInvalidSeatSelection)
+
+	_, err = svc.CancelBooking(ctx, created.Reference)
+	assert.NoError(t, err)
+
+	_, err = svc.SelectSeats(ctx, created.Reference, service.SeatSelectionRequest{Seats: []service.SeatAssignment{
+		{PassengerIndex: 0, SeatNumber: seats[0].Number},
+	}})
+	assert.ErrorIs(t, err, service.ErrBookingNotHeld)
+}
+
+func TestFlightBooking_SelectSeats_NotSupported(t *testing.T) {
+	agg := &MockAggregator{}
+	agg.On("Reprice", mock.Anything, mock.Anything).Return(unchanged(testFlight), nil)
+
+	svc := booking.NewBooking(15*time.Minute, 0.02, agg, storage.NewMemory(), events.NewBus(), payment.NewFake(webhookSecret), newProvider())
+	ctx := context.Background()
+
+	created, err := svc.CreateBooking(ctx, service.BookingRequest{OfferID: "offer-1", Passengers: testPassengers, Contact: testContact})
+	assert.NoError(t, err)
+
+	_, err = svc.SelectSeats(ctx, created.Reference, service.SeatSelectionRequest{Seats: []service.SeatAssignment{{PassengerIndex: 0, SeatNumber: "12A"}}})
+	assert.ErrorIs(t, err, service.ErrSeatMapUnavailable)
+}
+
+func TestFlightBooking_Ancillaries(t *testing.T) {
+	flight := testFlight
+	flight.CabinClass = "economy"
+	flight.Baggage = &service.BaggageInfo{CabinPieces: 1}
+
+	agg := &MockAggregator{}
+	agg.On("Reprice", mock.Anything, mock.Anything).Return(unchanged(flight), nil)
+
+	svc := booking.NewBooking(15*time.Minute, 0.02, agg, storage.NewMemory(), events.NewBus(), payment.NewFake(webhookSecret), newProvider())
+	ctx := context.Background()
+
+	created, err := svc.CreateBooking(ctx, service.BookingRequest{
+		OfferID:     "offer-1",
+		Passengers:  testPassengers,
+		Contact:     testContact,
+		Ancillaries: []service.AncillarySelection{{PassengerIndex: 0, Code: "BAG20"}},
+	})
+	assert.NoError(t, err)
+	assert.Len(t, created.Ancillaries, 1)
+	assert.Equal(t, float64(2500000), created.FareBreakdown.BaseFare.Amount)
+	assert.Equal(t, float64(320000), created.FareBreakdown.Ancillaries.Amount)
+	assert.Equal(t, float64(2820000), created.TotalPrice.Amount)
+
+	updated, err := svc.SelectAncillaries(ctx, created.Reference, service.AncillarySelectionRequest{Ancillaries: []service.AncillarySelection{
+		{PassengerIndex: 0, Code: "INS"},
+		{PassengerIndex: 1, Code: "MEAL"},
+	}})
+	assert.NoError(t, err)
+	assert.Len(t, updated.Ancillaries, 2)
+	assert.Equal(t, float64(110000), updated.FareBreakdown.Ancillaries.Amount)
+	assert.Equal(t, float64(2610000), updated.TotalPrice.Amount)
+
+	_, err = svc.SelectAncillaries(ctx, created.Reference, service.AncillarySelectionRequest{Ancillaries: []service.AncillarySelection{{PassengerIndex: 0, Code: "CAVIAR"}}})
+	assert.ErrorIs(t, err, service.ErrInvalidAncillarySelection)
+
+	_, err = svc.CreateBooking(ctx, service.BookingRequest{
+		OfferID:     "offer-1",
+		Passengers:  testPassengers[:1],
+		Contact:     testContact,
+		Ancillaries: []service.AncillarySelection{{PassengerIndex: 1, Code: "BAG20"}},
+	})
+	assert.ErrorIs(t, err, service.ErrInvalidAncillarySelection)
+}
+
+func TestFlightBooking_Cancellation_Held(t *testing.T) {
+	agg := &MockAggregator{}
+	agg.On("Reprice", mock.Anything, mock.Anything).Return(unchanged(testFlight), nil)
+
+	bus := events.NewBus()
+	var published []service.BookingEvent
+	bus.Subscribe(func(ctx context.Context, event service.BookingEvent) {
+		published = append(published, event)
+	})
+
+	svc := booking.NewBooking(15*time.Minute, 0.02, agg, storage.NewMemory(), bus, payment.NewFake(webhookSecret), newProvider())
+	ctx := context.Background()
+
+	created, err := svc.CreateBooking(ctx, service.BookingRequest{OfferID: "offer-1", Passengers: testPassengers, Contact: testContact})
+	assert.NoError(t, err)
+
+	quote, err := svc.QuoteCancellation(ctx, created.Reference)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, quote.QuoteID)
+	assert.Equal(t, float64(0), quote.PaidAmount.Amount)
+	assert.Equal(t, float64(0), quote.RefundAmount.Amount)
+
+	cancelled, err := svc.ConfirmCancellation(ctx, created.Reference, quote.QuoteID)
+	assert.NoError(t, err)
+	assert.Equal(t, service.BookingStatusCancelled, cancelled.Status)
+	assert.Equal(t, quote.QuoteID, cancelled.Cancellation.QuoteID)
+	assert.Len(t, cancelled.History, 1)
+
+	assert.Len(t, published, 1)
+	assert.Equal(t, service.BookingStatusHeld, published[0].From)
+	assert.Equal(t, service.BookingStatusCancelled, published[0].To)
+
+	_, err = svc.QuoteCancellation(ctx, created.Reference)
+	assert.ErrorIs(t, err, service.ErrBookingNotCancellable)
+}
+
+func TestFlightBooking_Cancellation_Ticketed(t *testing.T) {
+	departure := time.Now().Add(72 * time.Hour)
+	flight := testFlight
+	flight.Departure = service.LocationInfo{Airport: "CGK", Timestamp: departure.Unix()}
+
+	tests := []struct {
+		name       string
+		family     string
+		departure  time.Time
+		wantStatus string
+		wantRefund float64
+		wantNoShow bool
+	}{
+		{name: "refundable before departure", family: service.FareFamilyEconomy, departure: departure, wantStatus: service.BookingStatusRefunded, wantRefund: 1500000},
+		{name: "refundable no-show", family: service.FareFamilyEconomy, departure: time.Now().Add(-time.Hour), wantStatus: service.BookingStatusRefunded, wantRefund: 1000000, wantNoShow: true},
+		{name: "non-refundable", family: service.FareFamilyLite, departure: departure, wantStatus: service.BookingStatusCancelled},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := storage.NewMemory()
+			provider := newProvider()
+			svc := booking.NewBooking(15*time.Minute, 0.02, &MockAggregator{}, store, events.NewBus(), payment.NewFake(webhookSecret), provider)
+			ctx := context.Background()
+
+			f := flight
+			f.Departure.Timestamp = tt.departure.Unix()
+			f.FareRules = farerules.Summary(tt.family)
+
+			// the booking was paid, so the provider holds its seats as sold
+			hold, err := provider.CreateHold(ctx, service.HoldRequest{Flight: f, Passengers: testPassengers, ExpiresAt: time.Now().Add(15 * time.Minute)})
+			assert.NoError(t, err)
+			assert.NoError(t, provider.ConfirmHold(ctx, hold.Locator))
+
+			ticketed := service.Booking{
+				Reference:       "TKT234",
+				Status:          service.BookingStatusTicketed,
+				Flight:          f,
+				Passengers:      testPassengers,
+				ProviderLocator: hold.Locator,
+				FareBreakdown:   service.FareBreakdown{BaseFare: service.PriceInfo{Amount: 2500000}},
+				TotalPrice:      service.PriceInfo{Amount: 2650000},
+				UpdatedAt:       time.Now(),
+			}
+			assert.NoError(t, store.SaveBooking(ctx, ticketed))
+
+			quote, err := svc.QuoteCancellation(ctx, "TKT234")
+			assert.NoError(t, err)
+			assert.Equal(t, float64(2650000), quote.PaidAmount.Amount)
+			assert.Equal(t, tt.wantRefund, quote.RefundAmount.Amount)
+			assert.Equal(t, tt.wantNoShow, quote.NoShow)
+
+			_, err = svc.ConfirmCancellation(ctx, "TKT234", "stale")
+			assert.ErrorIs(t, err, service.ErrQuoteExpired)
+
+			cancelled, err := svc.ConfirmCancellation(ctx, "TKT234", quote.QuoteID)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, cancelled.Status)
+			assert.Equal(t, service.BookingStatusCancelled, cancelled.History[0].To)
+
+			stored, err := svc.GetBooking(ctx, "TKT234")
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, stored.Status)
+			assert.Equal(t, tt.wantRefund, stored.Cancellation.RefundAmount.Amount)
+			assert.Equal(t, 0, provider.HeldSeats(f.ID))
+		})
+	}
+}
+
+func testCard(number string) service.PaymentRequest {
+	return service.PaymentRequest{CardNumber: number, HolderName: "Budi Santoso", ExpiryMonth: 12, ExpiryYear: 2030, CVV: "123"}
+}
+
+func TestFlightBooking_Pay(t *testing.T) {
+	agg := &MockAggregator{}
+	agg.On("Reprice", mock.Anything, mock.Anything).Return(unchanged(testFlight), nil)
+
+	gateway := payment.NewFake(webhookSecret)
+	svc := booking.NewBooking(15*time.Minute, 0.02, agg, storage.NewMemory(), events.NewBus(), gateway, newProvider())
+	ctx := context.Background()
+
+	created, err := svc.CreateBooking(ctx, service.BookingRequest{OfferID: "offer-1", Passengers: testPassengers, Contact: testContact})
+	assert.NoError(t, err)
+
+	declined, err := svc.Pay(ctx, created.Reference, testCard(payment.CardDecline))
+	assert.ErrorIs(t, err, service.ErrPaymentDeclined)
+	assert.Equal(t, service.BookingStatusHeld, declined.Status)
+
+	paid, err := svc.Pay(ctx, created.Reference, testCard(payment.CardSuccess))
+	assert.NoError(t, err)
+	assert.Equal(t, service.BookingStatusTicketed, paid.Status)
+	assert.Equal(t, service.PaymentStatusCaptured, paid.Payment.Status)
+	assert.Equal(t, float64(2500000), paid.Payment.CapturedAmount.Amount)
+
+	_, err = svc.Pay(ctx, created.Reference, testCard(payment.CardSuccess))
+	assert.ErrorIs(t, err, service.ErrBookingNotHeld)
+
+	quote, err := svc.QuoteCancellation(ctx, created.Reference)
+	assert.NoError(t, err)
+	assert.Equal(t, float64(1500000), quote.RefundAmount.Amount)
+
+	refunded, err := svc.ConfirmCancellation(ctx, created.Reference, quote.QuoteID)
+	assert.NoError(t, err)
+	assert.Equal(t, service.BookingStatusRefunded, refunded.Status)
+	assert.Equal(t, float64(1500000), refunded.Payment.RefundedAmount.Amount)
+}
+
+func TestFlightBooking_Pay_SeatsStaySoldAfterHoldTTL(t *testing.T) {
+	agg := &MockAggregator{}
+	agg.On("Reprice", mock.Anything, mock.Anything).Return(unchanged(testFlight), nil)
+
+	provider := newProvider()
+	svc := booking.NewBooking(50*time.Millisecond, 0.02, agg, storage.NewMemory(), events.NewBus(), payment.NewFake(webhookSecret), provider)
+	ctx := context.Background()
+
+	created, err := svc.CreateBooking(ctx, service.BookingRequest{OfferID: "offer-1", Passengers: testPassengers, Contact: testContact})
+	assert.NoError(t, err)
+
+	paid, err := svc.Pay(ctx, created.Reference, testCard(payment.CardSuccess))
+	assert.NoError(t, err)
+	assert.Equal(t, service.BookingStatusTicketed, paid.Status)
+
+	time.Sleep(100 * time.Millisecond)
+	assert.Equal(t, len(testPassengers), provider.HeldSeats("offer-1"))
+
+	hold, err := provider.RetrieveHold(ctx, paid.ProviderLocator)
+	assert.NoError(t, err)
+	assert.Equal(t, service.HoldStatusConfirmed, hold.Status)
+}
+
+// UnconfirmableProvider loses every hold before it can be confirmed
+type UnconfirmableProvider struct {
+	*SimulatedProvider
+}
+
+func (p *UnconfirmableProvider) ConfirmHold(ctx context.Context, locator string) error {
+	return service.ErrHoldNotFound
+}
+
+func TestFlightBooking_Pay_ConfirmHoldFails(t *testing.T) {
+	agg := &MockAggregator{}
+	agg.On("Reprice", mock.Anything, mock.Anything).Return(unchanged(testFlight), nil)
+
+	store := storage.NewMemory()
+	svc := booking.NewBooking(15*time.Minute, 0.02, agg, store, events.NewBus(), payment.NewFake(webhookSecret), &UnconfirmableProvider{newProvider()})
+	ctx := context.Background()
+
+	created, err := svc.CreateBooking(ctx, service.BookingRequest{OfferID: "offer-1", Passengers: testPassengers, Contact: testContact})
+	assert.NoError(t, err)
+
+	_, err = svc.Pay(ctx, created.Reference, testCard(payment.CardSuccess))
+	assert.ErrorIs(t, err, service.ErrHoldNotFound)
+
+	// the captured payment is given back and nothing is ticketed
+	stored, err := store.GetBooking(ctx, created.Reference)
+	assert.NoError(t, err)
+	assert.Equal(t, service.BookingStatusHeld, stored.Status)
+	assert.Empty(t, stored.Tickets)
+	assert.Equal(t, service.PaymentStatusRefunded, stored.Payment.Status)
+	assert.Equal(t, stored.Payment.CapturedAmount.Amount, stored.Payment.RefundedAmount.Amount)
+}
+
+func TestFlightBooking_Pay_Challenge(t *testing.T) {
+	tests := []struct {
+		name          string
+		approved      bool
+		wantStatus    string
+		wantPayStatus string
+	}{
+		{name: "approved", approved: true, wantStatus: service.BookingStatusTicketed, wantPayStatus: service.PaymentStatusCaptured},
+		{name: "rejected", approved: false, wantStatus: service.BookingStatusHeld, wantPayStatus: service.PaymentStatusDeclined},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			agg := &MockAggregator{}
+			agg.On("Reprice", mock.Anything, mock.Anything).Return(unchanged(testFlight), nil)
+
+			gateway := payment.NewFake(webhookSecret)
+			svc := booking.NewBooking(15*time.Minute, 0.02, agg, storage.NewMemory(), events.NewBus(), gateway, newProvider())
+			ctx := context.Background()
+
+			created, err := svc.CreateBooking(ctx, service.BookingRequest{OfferID: "offer-1", Passengers: testPassengers, Contact: testContact})
+			assert.NoError(t, err)
+
+			pending, err := svc.Pay(ctx, created.Reference, testCard(payment.CardChallenge))
+			assert.NoError(t, err)
+			assert.Equal(t, service.BookingStatusHeld, pending.Status)
+			assert.Equal(t, service.PaymentStatusRequiresAction, pending.Payment.Status)
+
+			_, err = svc.Pay(ctx, created.Reference, testCard(payment.CardSuccess))
+			assert.ErrorIs(t, err, service.ErrPaymentPending)
+
+			body, _, err := gateway.ResolveChallenge(ctx, pending.Payment.ID, tt.approved)
+			assert.NoError(t, err)
+			var event service.PaymentEvent
+			assert.NoError(t, json.Unmarshal(body, &event))
+
+			assert.NoError(t, svc.HandlePaymentEvent(ctx, event))
+			// repeated notifications are ignored
+			assert.NoError(t, svc.HandlePaymentEvent(ctx, event))
+
+			result, err := svc.GetBooking(ctx, created.Reference)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, result.Status)
+			assert.Equal(t, tt.wantPayStatus, result.Payment.Status)
+
+			event.PaymentID = "pay_other"
+			assert.ErrorIs(t, svc.HandlePaymentEvent(ctx, event), service.ErrPaymentNotFound)
+		})
+	}
+}
+
+func TestFlightBooking_Pay_ChallengeAfterCancel(t *testing.T) {
+	agg := &MockAggregator{}
+	agg.On("Reprice", mock.Anything, mock.Anything).Return(unchanged(testFlight), nil)
+
+	gateway := payment.NewFake(webhookSecret)
+	svc := booking.NewBooking(15*time.Minute, 0.02, agg, storage.NewMemory(), events.NewBus(), gateway, newProvider())
+	ctx := context.Background()
+
+	created, err := svc.CreateBooking(ctx, service.BookingRequest{OfferID: "offer-1", Passengers: testPassengers, Contact: testContact})
+	assert.NoError(t, err)
+
+	pending, err := svc.Pay(ctx, created.Reference, testCard(payment.CardChallenge))
+	assert.NoError(t, err)
+
+	cancelled, err := svc.CancelBooking(ctx, created.Reference)
+	assert.NoError(t, err)
+	assert.Equal(t, service.PaymentStatusVoided, cancelled.Payment.Status)
+
+	_, _, err = gateway.ResolveChallenge(ctx, pending.Payment.ID, true)
+	assert.ErrorIs(t, err, service.ErrInvalidPaymentState)
+}
+
+func TestFlightBooking_TicketDocument(t *testing.T) {
+	agg := &MockAggregator{}
+	agg.On("Reprice", mock.Anything, mock.Anything).Return(unchanged(testFlight), nil)
+
+	svc := booking.NewBooking(15*time.Minute, 0.02, agg, storage.NewMemory(), events.NewBus(), payment.NewFake(webhookSecret), newProvider())
+	ctx := context.Background()
+
+	created, err := svc.CreateBooking(ctx, service.BookingRequest{OfferID: "offer-1", Passengers: testPassengers, Contact: testContact})
+	assert.NoError(t, err)
+
+	_, err = svc.TicketDocument(ctx, created.Reference, service.TicketFormatPDF)
+	assert.ErrorIs(t, err, service.ErrBookingNotTicketed)
+
+	paid, err := svc.Pay(ctx, created.Reference, testCard(payment.CardSuccess))
+	assert.NoError(t, err)
+	assert.Len(t, paid.Tickets, len(testPassengers))
+
+	doc, err := svc.TicketDocument(ctx, created.Reference, service.TicketFormatHTML)
+	assert.NoError(t, err)
+	assert.Contains(t, string(doc.Body), paid.Tickets[1].Number)
+}
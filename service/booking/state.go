@@ -0,0 +1,56 @@
+package booking
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/elkoshar/bookcabin/service"
+)
+
+// transitions lists the statuses a booking may move to from each status
+var transitions = map[string][]string{
+	service.BookingStatusHeld:      {service.BookingStatusTicketed, service.BookingStatusExpired, service.BookingStatusCancelled},
+	service.BookingStatusTicketed:  {service.BookingStatusCancelled},
+	service.BookingStatusCancelled: {service.BookingStatusRefunded},
+}
+
+func canTransition(from, to string) bool {
+	for _, next := range transitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// transition moves a booking to a new status and records the change in its history. The caller
+// saves the booking and publishes the returned event once it is stored.
+func transition(booking *service.Booking, to, reason string) (service.BookingEvent, error) {
+	if !canTransition(booking.Status, to) {
+		return service.BookingEvent{}, fmt.Errorf("%w: %s to %s", service.ErrInvalidTransition, booking.Status, to)
+	}
+
+	event := service.BookingEvent{
+		Reference: booking.Reference,
+		From:      booking.Status,
+		To:        to,
+		Reason:    reason,
+		At:        time.Now(),
+	}
+	booking.Status = to
+	booking.UpdatedAt = event.At
+	booking.History = append(booking.History, event)
+
+	return event, nil
+}
+
+// publish emits events of a stored booking, a failing publisher does not undo the change
+func (s *FlightBooking) publish(ctx context.Context, events ...service.BookingEvent) {
+	for _, event := range events {
+		if err := s.events.Publish(ctx, event); err != nil {
+			slog.Error(fmt.Sprintf("[Booking] Failed to publish %s event for %s: %v", event.To, event.Reference, err))
+		}
+	}
+}
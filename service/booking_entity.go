@@ -0,0 +1,128 @@
+package service
+
+import (
+	"errors"
+	"time"
+)
+
+// Booking lifecycle states
+const (
+	BookingStatusHeld      = "held"
+	BookingStatusExpired   = "expired"
+	BookingStatusTicketed  = "ticketed"
+	BookingStatusCancelled = "cancelled"
+	BookingStatusRefunded  = "refunded"
+)
+
+// Provider hold states
+const (
+	HoldStatusActive    = "active"
+	HoldStatusConfirmed = "confirmed"
+	HoldStatusReleased  = "released"
+)
+
+var (
+	ErrBookingNotFound       = errors.New("booking not found")
+	ErrBookingNotCancellable = errors.New("booking cannot be cancelled in its current state")
+	ErrBookingNotHeld        = errors.New("booking is no longer held")
+	ErrHoldNotFound          = errors.New("hold not found")
+	ErrInsufficientSeats     = errors.New("not enough seats available")
+	ErrInvalidTransition     = errors.New("booking status transition is not allowed")
+	ErrQuoteExpired          = errors.New("cancellation quote is no longer valid")
+)
+
+type Passenger struct {
+	Title          string `json:"title" validate:"required,oneof=MR MRS MS MSTR MISS"`
+	FirstName      string `json:"first_name" validate:"required"`
+	LastName       string `json:"last_name" validate:"required"`
+	DateOfBirth    string `json:"date_of_birth" validate:"required,datetime=2006-01-02"`
+	Nationality    string `json:"nationality,omitempty" validate:"omitempty,len=2"`
+	DocumentNumber string `json:"document_number,omitempty"`
+}
+
+type ContactInfo struct {
+	Email string `json:"email" validate:"required,email"`
+	Phone string `json:"phone" validate:"required"`
+}
+
+type BookingRequest struct {
+	OfferID    string      `json:"offer_id" validate:"required"`
+	Passengers []Passenger `json:"passengers" validate:"required,min=1,max=9,dive"`
+	Contact    ContactInfo `json:"contact" validate:"required"`
+
+	// ExpectedAmount is the per-passenger price shown at checkout, the last quoted price is used when empty
+	ExpectedAmount float64 `json:"expected_amount,omitempty" validate:"omitempty,gt=0"`
+
+	Ancillaries []AncillarySelection `json:"ancillaries,omitempty" validate:"omitempty,max=36,dive"`
+}
+
+type Booking struct {
+	Reference       string             `json:"reference"`
+	Status          string             `json:"status"`
+	OfferID         string             `json:"offer_id"`
+	Flight          UnifiedFlight      `json:"flight"`
+	Passengers      []Passenger        `json:"passengers"`
+	Contact         ContactInfo        `json:"contact"`
+	ProviderLocator string             `json:"provider_locator"`
+	Seats           []SeatAssignment   `json:"seats,omitempty"`
+	Ancillaries     []BookedAncillary  `json:"ancillaries,omitempty"`
+	FareBreakdown   FareBreakdown      `json:"fare_breakdown"`
+	TotalPrice      PriceInfo          `json:"total_price"`
+	Payment         *Payment           `json:"payment,omitempty"`
+	Tickets         []ETicket          `json:"tickets,omitempty"`
+	Cancellation    *CancellationQuote `json:"cancellation,omitempty"`
+	History         []BookingEvent     `json:"history,omitempty"`
+	HoldExpiresAt   time.Time          `json:"hold_expires_at"`
+	CreatedAt       time.Time          `json:"created_at"`
+	UpdatedAt       time.Time          `json:"updated_at"`
+}
+
+// BookingEvent records a status change of a booking
+type BookingEvent struct {
+	Reference string    `json:"reference"`
+	From      string    `json:"from"`
+	To        string    `json:"to"`
+	Reason    string    `json:"reason,omitempty"`
+	At        time.Time `json:"at"`
+}
+
+// CancellationRequest asks for a refund quote, or confirms a quote shown earlier
+type CancellationRequest struct {
+	Confirm bool   `json:"confirm"`
+	QuoteID string `json:"quote_id,omitempty" validate:"required_if=Confirm true"`
+}
+
+// CancellationQuote is the refund a booking gets when it is cancelled now
+type CancellationQuote struct {
+	QuoteID          string    `json:"quote_id"`
+	Reference        string    `json:"reference"`
+	Status           string    `json:"status"`
+	FareFamily       string    `json:"fare_family"`
+	Refundable       bool      `json:"refundable"`
+	NoShow           bool      `json:"no_show"`
+	PaidAmount       PriceInfo `json:"paid_amount"`
+	CancellationFee  PriceInfo `json:"cancellation_fee"`
+	NoShowFee        PriceInfo `json:"no_show_fee"`
+	NonRefundable    PriceInfo `json:"non_refundable"`
+	RefundAmount     PriceInfo `json:"refund_amount"`
+	HoursToDeparture float64   `json:"hours_to_departure"`
+	QuotedAt         time.Time `json:"quoted_at"`
+}
+
+// HoldRequest is sent to the provider that sells the flight
+type HoldRequest struct {
+	Flight     UnifiedFlight
+	Passengers []Passenger
+	Contact    ContactInfo
+	ExpiresAt  time.Time
+}
+
+// ProviderHold is the provider's view of a held reservation
+type ProviderHold struct {
+	Locator    string
+	OfferID    string
+	Passengers int
+	Seats      []string
+	Status     string
+	ExpiresAt  time.Time
+}
@@ -0,0 +1,45 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+
+	"github.com/elkoshar/bookcabin/service"
+)
+
+// Handler receives published booking events
+type Handler func(ctx context.Context, event service.BookingEvent)
+
+// Bus delivers booking events to its subscribers in the order they are published
+type Bus struct {
+	mu       sync.RWMutex
+	handlers []Handler
+}
+
+func NewBus() *Bus {
+	return &Bus{}
+}
+
+// Subscribe registers a handler for every event published after the call
+func (b *Bus) Subscribe(h Handler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.handlers = append(b.handlers, h)
+}
+
+// Publish logs the event and hands it to every subscriber synchronously
+func (b *Bus) Publish(ctx context.Context, event service.BookingEvent) error {
+	slog.Info(fmt.Sprintf("[Events] Booking %s moved from %s to %s", event.Reference, event.From, event.To))
+
+	b.mu.RLock()
+	handlers := b.handlers
+	b.mu.RUnlock()
+
+	for _, h := range handlers {
+		h(ctx, event)
+	}
+	return nil
+}
@@ -0,0 +1,25 @@
+package events_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/elkoshar/bookcabin/service"
+	"github.com/elkoshar/bookcabin/service/events"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBus_Publish(t *testing.T) {
+	bus := events.NewBus()
+
+	var received []service.BookingEvent
+	bus.Subscribe(func(ctx context.Context, event service.BookingEvent) {
+		received = append(received, event)
+	})
+
+	assert.NoError(t, bus.Publish(context.Background(), service.BookingEvent{Reference: "ABC234", From: service.BookingStatusHeld, To: service.BookingStatusCancelled}))
+	assert.NoError(t, bus.Publish(context.Background(), service.BookingEvent{Reference: "ABC234", From: service.BookingStatusCancelled, To: service.BookingStatusRefunded}))
+
+	assert.Len(t, received, 2)
+	assert.Equal(t, service.BookingStatusRefunded, received[1].To)
+}
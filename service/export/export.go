@@ -0,0 +1,126 @@
+// Package export writes flight results in the file formats travel desks paste into spreadsheets and
+// calendars.
+package export
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	_ "time/tzdata" // airport time zones must resolve on hosts without a zoneinfo database
+
+	"github.com/elkoshar/bookcabin/pkg/helpers"
+	"github.com/elkoshar/bookcabin/service"
+)
+
+// Names of the legs of a search
+const (
+	LegOutbound = "outbound"
+	LegReturn   = "return"
+)
+
+// Leg is the list of flights offered for one leg of a trip
+type Leg struct {
+	Name    string
+	Flights []service.UnifiedFlight
+}
+
+// Legs splits a search response into its outbound and return legs, or into "leg 1", "leg 2", ... for
+// multi-city searches
+func Legs(resp service.SearchResponse) []Leg {
+	if len(resp.MultiCityFlights) > 0 {
+		legs := make([]Leg, len(resp.MultiCityFlights))
+		for i, flights := range resp.MultiCityFlights {
+			legs[i] = Leg{Name: fmt.Sprintf("leg %d", i+1), Flights: flights}
+		}
+		return legs
+	}
+
+	legs := []Leg{{Name: LegOutbound, Flights: resp.Flights}}
+	if len(resp.ReturnFlights) > 0 {
+		legs = append(legs, Leg{Name: LegReturn, Flights: resp.ReturnFlights})
+	}
+	return legs
+}
+
+var csvHeader = []string{
+	"leg", "id", "provider", "airline", "flight_number",
+	"origin", "origin_city", "departure_time", "destination", "destination_city", "arrival_time",
+	"duration_minutes", "stops", "cabin_class", "price", "currency", "available_seats", "refundable", "labels",
+}
+
+// CSV writes one row per flight, times are in the local time of their airport
+func CSV(w io.Writer, legs []Leg) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvHeader); err != nil {
+		return err
+	}
+
+	for _, leg := range legs {
+		for _, f := range leg.Flights {
+			refundable := ""
+			if f.FareRules != nil {
+				refundable = strconv.FormatBool(f.FareRules.Refundable)
+			}
+
+			row := []string{
+				leg.Name, f.ID, f.Provider, f.Airline.Name, f.FlightNumber,
+				f.Departure.Airport, f.Departure.City, LocalTime(f.Departure).Format(time.RFC3339),
+				f.Arrival.Airport, f.Arrival.City, LocalTime(f.Arrival).Format(time.RFC3339),
+				strconv.Itoa(f.Duration.TotalMinutes), strconv.Itoa(f.Stops), f.CabinClass,
+				strconv.FormatFloat(f.Price.Amount, 'f', -1, 64), f.Price.Currency,
+				strconv.Itoa(f.AvailableSeats), refundable, strings.Join(f.Labels, ";"),
+			}
+			if err := cw.Write(row); err != nil {
+				return err
+			}
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// NDJSON writes one flight per line as the JSON of service.UnifiedFlight
+func NDJSON(w io.Writer, legs []Leg) error {
+	enc := json.NewEncoder(w)
+	for _, leg := range legs {
+		for _, f := range leg.Flights {
+			if err := enc.Encode(f); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// LocalTime returns the time of a departure or arrival in the time zone of its airport. Airports missing
+// from the airport database keep the UTC offset the provider sent, or UTC when there is none.
+func LocalTime(l service.LocationInfo) time.Time {
+	t := time.Unix(l.Timestamp, 0).UTC()
+
+	if loc, ok := airportLocation(l.Airport); ok {
+		return t.In(loc)
+	}
+	if sent, err := time.Parse(time.RFC3339, l.DateTime); err == nil {
+		return t.In(sent.Location())
+	}
+	return t
+}
+
+// airportLocation returns the IANA time zone of an airport
+func airportLocation(airport string) (*time.Location, bool) {
+	info, ok := helpers.AirportMap[airport]
+	if !ok || info.Location == "" {
+		return nil, false
+	}
+
+	loc, err := time.LoadLocation(info.Location)
+	if err != nil {
+		return nil, false
+	}
+	return loc, true
+}
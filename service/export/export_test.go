@@ -0,0 +1,116 @@
+package export_test
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elkoshar/bookcabin/service"
+	"github.com/elkoshar/bookcabin/service/export"
+)
+
+// CGK 06:00 WIB to DPS 08:50 WITA on 15 December 2025
+var outbound = service.UnifiedFlight{
+	ID:           "offer-ga400",
+	Provider:     "Garuda Indonesia",
+	Airline:      service.AirlineInfo{Name: "Garuda Indonesia", Code: "GA"},
+	FlightNumber: "GA400",
+	Departure:    service.LocationInfo{Airport: "CGK", City: "Jakarta", Terminal: "3", DateTime: "2025-12-15T06:00:00+07:00", Timestamp: 1765753200},
+	Arrival:      service.LocationInfo{Airport: "DPS", City: "Denpasar", DateTime: "2025-12-15T08:50:00+08:00", Timestamp: 1765759800},
+	Duration:     service.DurationInfo{TotalMinutes: 110, Formatted: "1h 50m"},
+	Price:        service.PriceInfo{Amount: 1250000, Currency: "IDR", Formatted: "Rp 1.250.000"},
+	CabinClass:   "economy",
+	FareRules:    &service.FareRulesSummary{Refundable: true},
+	Labels:       []string{service.LabelCheapest, service.LabelFastest},
+}
+
+// DPS 17:00 WITA to CGK 17:45 WIB on 18 December 2025, flown from an airport missing from the database
+var inbound = service.UnifiedFlight{
+	ID:           "offer-jt43",
+	Provider:     "Lion Air",
+	Airline:      service.AirlineInfo{Name: "Lion Air", Code: "JT"},
+	FlightNumber: "JT43",
+	Departure:    service.LocationInfo{Airport: "XXX", City: "Nowhere", DateTime: "2025-12-18T17:00:00+08:00", Timestamp: 1766048400},
+	Arrival:      service.LocationInfo{Airport: "CGK", City: "Jakarta", DateTime: "2025-12-18T17:45:00+07:00", Timestamp: 1766054700},
+	Price:        service.PriceInfo{Amount: 950000, Currency: "IDR"},
+}
+
+func TestLegs(t *testing.T) {
+	legs := export.Legs(service.SearchResponse{Flights: []service.UnifiedFlight{outbound}, ReturnFlights: []service.UnifiedFlight{inbound}})
+	assert.Equal(t, []export.Leg{
+		{Name: export.LegOutbound, Flights: []service.UnifiedFlight{outbound}},
+		{Name: export.LegReturn, Flights: []service.UnifiedFlight{inbound}},
+	}, legs)
+
+	legs = export.Legs(service.SearchResponse{MultiCityFlights: [][]service.UnifiedFlight{{outbound}, {inbound}}})
+	assert.Equal(t, "leg 1", legs[0].Name)
+	assert.Equal(t, "leg 2", legs[1].Name)
+
+	legs = export.Legs(service.SearchResponse{})
+	assert.Equal(t, []export.Leg{{Name: export.LegOutbound}}, legs)
+}
+
+func TestCSV(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, export.CSV(&buf, export.Legs(service.SearchResponse{Flights: []service.UnifiedFlight{outbound}, ReturnFlights: []service.UnifiedFlight{inbound}})))
+
+	rows, err := csv.NewReader(&buf).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, rows, 3)
+
+	assert.Equal(t, "leg", rows[0][0])
+	assert.Equal(t, []string{
+		"outbound", "offer-ga400", "Garuda Indonesia", "Garuda Indonesia", "GA400",
+		"CGK", "Jakarta", "2025-12-15T06:00:00+07:00", "DPS", "Denpasar", "2025-12-15T08:50:00+08:00",
+		"110", "0", "economy", "1250000", "IDR", "0", "true", "cheapest;fastest",
+	}, rows[1])
+
+	// unknown airports keep the offset the provider sent
+	assert.Equal(t, "2025-12-18T17:00:00+08:00", rows[2][7])
+	assert.Equal(t, "", rows[2][17])
+}
+
+func TestNDJSON(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, export.NDJSON(&buf, []export.Leg{{Name: export.LegOutbound, Flights: []service.UnifiedFlight{outbound, inbound}}}))
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	require.Len(t, lines, 2)
+
+	var flight service.UnifiedFlight
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &flight))
+	assert.Equal(t, "JT43", flight.FlightNumber)
+}
+
+func TestICS(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, export.ICS(&buf, []export.Leg{{Name: export.LegOutbound, Flights: []service.UnifiedFlight{outbound, inbound}}}))
+	ics := buf.String()
+
+	assert.True(t, strings.HasPrefix(ics, "BEGIN:VCALENDAR\r\nVERSION:2.0\r\n"))
+	assert.True(t, strings.HasSuffix(ics, "END:VCALENDAR\r\n"))
+	assert.Equal(t, 2, strings.Count(ics, "BEGIN:VEVENT\r\n"))
+
+	// each zone in use is described once
+	assert.Equal(t, 1, strings.Count(ics, "TZID:Asia/Jakarta\r\n"))
+	assert.Contains(t, ics, "TZID:Asia/Makassar\r\nBEGIN:STANDARD\r\nDTSTART:19700101T000000\r\nTZOFFSETFROM:+0800\r\nTZOFFSETTO:+0800\r\nTZNAME:WITA\r\n")
+
+	assert.Contains(t, ics, "DTSTART;TZID=Asia/Jakarta:20251215T060000\r\n")
+	assert.Contains(t, ics, "DTEND;TZID=Asia/Makassar:20251215T085000\r\n")
+	assert.Contains(t, ics, "DTSTART:20251218T090000Z\r\n")
+	assert.Contains(t, ics, "SUMMARY:Garuda Indonesia GA400 CGK-DPS\r\n")
+	assert.Contains(t, ics, "LOCATION:Soekarno-Hatta International Airport (CGK)\\, Terminal 3\r\n")
+
+	for _, line := range strings.Split(strings.TrimSuffix(ics, "\r\n"), "\r\n") {
+		assert.LessOrEqual(t, len(line), 75, line)
+	}
+
+	// folded lines unfold back to the escaped description
+	unfolded := strings.ReplaceAll(ics, "\r\n ", "")
+	assert.Contains(t, unfolded, `DESCRIPTION:outbound: Jakarta to Denpasar\nProvider: Garuda Indonesia\nDuration: 1h 50m\, stops: 0\nPrice: Rp 1.250.000\nOffer: offer-ga400`+"\r\n")
+}
@@ -0,0 +1,175 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/elkoshar/bookcabin/pkg/helpers"
+	"github.com/elkoshar/bookcabin/service"
+)
+
+const (
+	icsLocalLayout = "20060102T150405"
+	icsUTCLayout   = "20060102T150405Z"
+
+	// icsLineLimit is the longest content line RFC 5545 allows, in octets, before it must be folded
+	icsLineLimit = 75
+)
+
+// ICS writes an iCalendar file with one VEVENT per flight. Departure and arrival are in the IANA time zone
+// of their airport, described by a VTIMEZONE, so calendars show the local times printed on the ticket.
+func ICS(w io.Writer, legs []Leg) error {
+	var b strings.Builder
+	line := func(format string, args ...interface{}) {
+		writeICSLine(&b, fmt.Sprintf(format, args...))
+	}
+
+	line("BEGIN:VCALENDAR")
+	line("VERSION:2.0")
+	line("PRODID:-//bookcabin//Flight Search//EN")
+	line("CALSCALE:GREGORIAN")
+	line("METHOD:PUBLISH")
+
+	for _, zone := range icsZones(legs) {
+		line("BEGIN:VTIMEZONE")
+		line("TZID:%s", zone.name)
+		line("BEGIN:STANDARD")
+		line("DTSTART:19700101T000000")
+		line("TZOFFSETFROM:%s", zone.offset)
+		line("TZOFFSETTO:%s", zone.offset)
+		line("TZNAME:%s", zone.abbreviation)
+		line("END:STANDARD")
+		line("END:VTIMEZONE")
+	}
+
+	stamp := time.Now().UTC().Format(icsUTCLayout)
+	for _, leg := range legs {
+		for _, f := range leg.Flights {
+			line("BEGIN:VEVENT")
+			line("UID:%s@bookcabin", f.ID)
+			line("DTSTAMP:%s", stamp)
+			line("DTSTART%s", icsTime(f.Departure))
+			line("DTEND%s", icsTime(f.Arrival))
+			line("SUMMARY:%s", icsEscape(fmt.Sprintf("%s %s %s-%s", f.Airline.Name, f.FlightNumber, f.Departure.Airport, f.Arrival.Airport)))
+			line("LOCATION:%s", icsEscape(icsLocation(f.Departure)))
+			line("DESCRIPTION:%s", icsEscape(icsDescription(leg.Name, f)))
+			line("END:VEVENT")
+		}
+	}
+
+	line("END:VCALENDAR")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// icsTime formats the time of a departure or arrival as a DTSTART or DTEND value, with the TZID of its
+// airport when the airport database knows it and in UTC otherwise
+func icsTime(l service.LocationInfo) string {
+	t := time.Unix(l.Timestamp, 0)
+	if loc, ok := airportLocation(l.Airport); ok {
+		return fmt.Sprintf(";TZID=%s:%s", loc.String(), t.In(loc).Format(icsLocalLayout))
+	}
+	return ":" + t.UTC().Format(icsUTCLayout)
+}
+
+type icsZone struct {
+	name         string
+	abbreviation string
+	offset       string
+}
+
+// icsZones describes the time zones of every airport in legs. The airports served have not observed
+// daylight saving time for decades, so a single STANDARD rule per zone is enough.
+func icsZones(legs []Leg) []icsZone {
+	seen := map[string]icsZone{}
+	add := func(l service.LocationInfo) {
+		loc, ok := airportLocation(l.Airport)
+		if !ok {
+			return
+		}
+		if _, done := seen[loc.String()]; done {
+			return
+		}
+
+		abbreviation, offset := time.Unix(l.Timestamp, 0).In(loc).Zone()
+		sign := '+'
+		if offset < 0 {
+			sign, offset = '-', -offset
+		}
+		seen[loc.String()] = icsZone{
+			name:         loc.String(),
+			abbreviation: abbreviation,
+			offset:       fmt.Sprintf("%c%02d%02d", sign, offset/3600, offset%3600/60),
+		}
+	}
+
+	for _, leg := range legs {
+		for _, f := range leg.Flights {
+			add(f.Departure)
+			add(f.Arrival)
+		}
+	}
+
+	zones := make([]icsZone, 0, len(seen))
+	for _, zone := range seen {
+		zones = append(zones, zone)
+	}
+	sort.Slice(zones, func(i, j int) bool { return zones[i].name < zones[j].name })
+	return zones
+}
+
+func icsLocation(l service.LocationInfo) string {
+	location := helpers.GetAirportDetail(l.Airport).Name + " (" + l.Airport + ")"
+	if l.Terminal != "" {
+		location += ", Terminal " + l.Terminal
+	}
+	return location
+}
+
+func icsDescription(leg string, f service.UnifiedFlight) string {
+	price := f.Price.Formatted
+	if price == "" {
+		price = fmt.Sprintf("%s %.0f", f.Price.Currency, f.Price.Amount)
+	}
+
+	lines := []string{
+		fmt.Sprintf("%s: %s to %s", leg, f.Departure.City, f.Arrival.City),
+		fmt.Sprintf("Provider: %s", f.Provider),
+		fmt.Sprintf("Duration: %s, stops: %d", f.Duration.Formatted, f.Stops),
+		fmt.Sprintf("Price: %s", price),
+		fmt.Sprintf("Offer: %s", f.ID),
+	}
+	return strings.Join(lines, "\n")
+}
+
+// icsEscape escapes the characters RFC 5545 reserves in TEXT values
+func icsEscape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`).Replace(s)
+}
+
+// writeICSLine writes a content line ended by CRLF, folding it every icsLineLimit octets without splitting
+// a UTF-8 character
+func writeICSLine(b *strings.Builder, s string) {
+	limit := icsLineLimit
+	for len(s) > limit {
+		cut := limit
+		for cut > 0 && !isRuneStart(s[cut]) {
+			cut--
+		}
+		b.WriteString(s[:cut])
+		b.WriteString("\r\n ")
+		s = s[cut:]
+		// continuation lines start with a space, which counts towards their limit
+		limit = icsLineLimit - 1
+	}
+	b.WriteString(s)
+	b.WriteString("\r\n")
+}
+
+func isRuneStart(c byte) bool {
+	return c&0xC0 != 0x80
+}
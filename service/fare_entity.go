@@ -0,0 +1,34 @@
+package service
+
+// Fare families that provider fare types and booking classes map into
+const (
+	FareFamilyLite        = "lite"
+	FareFamilyEconomy     = "economy"
+	FareFamilyEconomyFlex = "economy_flex"
+	FareFamilyBusiness    = "business"
+	FareFamilyFirst       = "first"
+)
+
+// FareRulesSummary is the short form of an offer's fare rules shown in search results
+type FareRulesSummary struct {
+	Family          string    `json:"family"`
+	Refundable      bool      `json:"refundable"`
+	Changeable      bool      `json:"changeable"`
+	ChangeFee       PriceInfo `json:"change_fee"`
+	CancellationFee PriceInfo `json:"cancellation_fee"`
+}
+
+// FareRules are the full change and refund conditions of an offer
+type FareRules struct {
+	OfferID string `json:"offer_id"`
+	FareRulesSummary
+	NoShow   string            `json:"no_show"`
+	Validity string            `json:"validity"`
+	Sections []FareRuleSection `json:"sections"`
+}
+
+// FareRuleSection is one paragraph of the fare rule text
+type FareRuleSection struct {
+	Title string `json:"title"`
+	Text  string `json:"text"`
+}
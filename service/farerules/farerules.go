@@ -0,0 +1,148 @@
+package farerules
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/elkoshar/bookcabin/pkg/helpers"
+	"github.com/elkoshar/bookcabin/service"
+)
+
+// rule holds the conditions of a fare family, fees are in IDR per passenger per flight
+type rule struct {
+	refundable      bool
+	changeable      bool
+	changeFee       float64
+	cancellationFee float64
+	noShowFee       float64
+	changeDeadline  string
+	validity        string
+}
+
+var families = map[string]rule{
+	service.FareFamilyLite: {
+		changeable:     true,
+		changeFee:      350000,
+		changeDeadline: "48 hours",
+		validity:       "Valid only for the booked flight.",
+	},
+	service.FareFamilyEconomy: {
+		refundable:      true,
+		changeable:      true,
+		changeFee:       250000,
+		cancellationFee: 500000,
+		noShowFee:       250000,
+		changeDeadline:  "24 hours",
+		validity:        "Valid for 1 year from the date of issue.",
+	},
+	service.FareFamilyEconomyFlex: {
+		refundable:      true,
+		changeable:      true,
+		cancellationFee: 250000,
+		noShowFee:       200000,
+		changeDeadline:  "4 hours",
+		validity:        "Valid for 1 year from the date of issue.",
+	},
+	service.FareFamilyBusiness: {
+		refundable:      true,
+		changeable:      true,
+		cancellationFee: 200000,
+		noShowFee:       500000,
+		changeDeadline:  "2 hours",
+		validity:        "Valid for 1 year from the date of issue.",
+	},
+	service.FareFamilyFirst: {
+		refundable:     true,
+		changeable:     true,
+		noShowFee:      500000,
+		changeDeadline: "2 hours",
+		validity:       "Valid for 1 year from the date of issue.",
+	},
+}
+
+func lookup(family string) (string, rule) {
+	if r, ok := families[family]; ok {
+		return family, r
+	}
+	return service.FareFamilyEconomy, families[service.FareFamilyEconomy]
+}
+
+func price(amount float64) service.PriceInfo {
+	return service.PriceInfo{Amount: amount, Currency: "IDR", Formatted: helpers.FormatIDR(amount)}
+}
+
+// Summary returns the rule summary of a fare family, unknown families get the economy rules
+func Summary(family string) *service.FareRulesSummary {
+	family, r := lookup(family)
+	return &service.FareRulesSummary{
+		Family:          family,
+		Refundable:      r.refundable,
+		Changeable:      r.changeable,
+		ChangeFee:       price(r.changeFee),
+		CancellationFee: price(r.cancellationFee),
+	}
+}
+
+// Details returns the full fare rules of an offer including the rule text
+func Details(flight service.UnifiedFlight) service.FareRules {
+	family := service.FareFamilyEconomy
+	if flight.FareRules != nil {
+		family = flight.FareRules.Family
+	}
+	family, r := lookup(family)
+
+	refund := "Non-refundable. Only airport taxes are returned when the booking is cancelled."
+	if r.refundable && r.cancellationFee == 0 {
+		refund = "Fully refundable without a cancellation fee."
+	} else if r.refundable {
+		refund = fmt.Sprintf("Refundable. A cancellation fee of %s per passenger is deducted from the refund.", helpers.FormatIDR(r.cancellationFee))
+	}
+
+	change := "Changes are not permitted."
+	if r.changeable && r.changeFee == 0 {
+		change = fmt.Sprintf("Free changes up to %s before departure, any fare difference applies.", r.changeDeadline)
+	} else if r.changeable {
+		change = fmt.Sprintf("Changes up to %s before departure for %s per passenger plus any fare difference.", r.changeDeadline, helpers.FormatIDR(r.changeFee))
+	}
+
+	noShow := "The fare is forfeited when the passenger does not show up for the flight."
+	if r.refundable {
+		noShow = fmt.Sprintf("A no-show fee of %s per passenger applies in addition to the cancellation fee, remaining flights are cancelled.", helpers.FormatIDR(r.noShowFee))
+	}
+
+	return service.FareRules{
+		OfferID:          flight.ID,
+		FareRulesSummary: *Summary(family),
+		NoShow:           noShow,
+		Validity:         r.validity,
+		Sections: []service.FareRuleSection{
+			{Title: "Refund", Text: refund},
+			{Title: "Change", Text: change},
+			{Title: "No-show", Text: noShow},
+			{Title: "Validity", Text: r.validity},
+		},
+	}
+}
+
+// Refund is the outcome of cancelling a paid fare
+type Refund struct {
+	CancellationFee float64
+	NoShowFee       float64
+	Amount          float64
+}
+
+// RefundAmount computes the refund of a fare paid for a number of passengers, noShow adds the
+// no-show fee for bookings cancelled after departure
+func RefundAmount(family string, fare float64, passengers int, noShow bool) Refund {
+	_, r := lookup(family)
+	if !r.refundable {
+		return Refund{}
+	}
+
+	refund := Refund{CancellationFee: r.cancellationFee * float64(passengers)}
+	if noShow {
+		refund.NoShowFee = r.noShowFee * float64(passengers)
+	}
+	refund.Amount = math.Max(fare-refund.CancellationFee-refund.NoShowFee, 0)
+	return refund
+}
@@ -0,0 +1,66 @@
+package farerules_test
+
+import (
+	"testing"
+
+	"github.com/elkoshar/bookcabin/service"
+	"github.com/elkoshar/bookcabin/service/farerules"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSummary(t *testing.T) {
+	lite := farerules.Summary(service.FareFamilyLite)
+	assert.Equal(t, service.FareFamilyLite, lite.Family)
+	assert.False(t, lite.Refundable)
+	assert.True(t, lite.Changeable)
+	assert.Equal(t, float64(350000), lite.ChangeFee.Amount)
+
+	flex := farerules.Summary(service.FareFamilyEconomyFlex)
+	assert.True(t, flex.Refundable)
+	assert.Equal(t, float64(0), flex.ChangeFee.Amount)
+	assert.Equal(t, float64(250000), flex.CancellationFee.Amount)
+
+	unknown := farerules.Summary("premium")
+	assert.Equal(t, service.FareFamilyEconomy, unknown.Family)
+}
+
+func TestDetails(t *testing.T) {
+	flight := service.UnifiedFlight{ID: "offer-1", FareRules: farerules.Summary(service.FareFamilyLite)}
+
+	rules := farerules.Details(flight)
+	assert.Equal(t, "offer-1", rules.OfferID)
+	assert.Equal(t, service.FareFamilyLite, rules.Family)
+	assert.Len(t, rules.Sections, 4)
+	assert.Contains(t, rules.Sections[0].Text, "Non-refundable")
+	assert.Contains(t, rules.Sections[1].Text, "IDR 350.000")
+	assert.Contains(t, rules.NoShow, "forfeited")
+
+	rules = farerules.Details(service.UnifiedFlight{ID: "offer-2", FareRules: farerules.Summary(service.FareFamilyFirst)})
+	assert.Contains(t, rules.Sections[0].Text, "Fully refundable")
+	assert.Contains(t, rules.Sections[1].Text, "Free changes")
+}
+
+func TestRefundAmount(t *testing.T) {
+	tests := []struct {
+		name   string
+		family string
+		noShow bool
+		want   farerules.Refund
+	}{
+		{name: "lite is not refundable", family: service.FareFamilyLite, noShow: false, want: farerules.Refund{}},
+		{name: "economy before departure", family: service.FareFamilyEconomy, noShow: false, want: farerules.Refund{CancellationFee: 1000000, Amount: 1500000}},
+		{name: "economy no-show", family: service.FareFamilyEconomy, noShow: true, want: farerules.Refund{CancellationFee: 1000000, NoShowFee: 500000, Amount: 1000000}},
+		{name: "first without fee", family: service.FareFamilyFirst, noShow: false, want: farerules.Refund{Amount: 2500000}},
+		{name: "fees exceed fare", family: service.FareFamilyBusiness, noShow: true, want: farerules.Refund{CancellationFee: 400000, NoShowFee: 1000000, Amount: 0}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fare := 2500000.0
+			if tt.family == service.FareFamilyBusiness {
+				fare = 1000000
+			}
+			assert.Equal(t, tt.want, farerules.RefundAmount(tt.family, fare, 2, tt.noShow))
+		})
+	}
+}
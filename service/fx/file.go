@@ -0,0 +1,101 @@
+package fx
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/elkoshar/bookcabin/service"
+)
+
+// File serves exchange rates read from a JSON file, reloading it on a schedule so the rates can be
+// updated without a restart
+type File struct {
+	path string
+
+	mu    sync.RWMutex
+	rates service.FXRates
+
+	stop context.CancelFunc
+	wg   sync.WaitGroup
+}
+
+// NewFile loads the rates in path and, when refresh is positive, reloads them every refresh. A failed
+// reload keeps the rates loaded before.
+func NewFile(path string, refresh time.Duration) (*File, error) {
+	f := &File{path: path}
+	if err := f.load(); err != nil {
+		return nil, err
+	}
+
+	ctx, stop := context.WithCancel(context.Background())
+	f.stop = stop
+
+	if refresh > 0 {
+		f.wg.Add(1)
+		go f.refresh(ctx, refresh)
+	}
+	return f, nil
+}
+
+// Close stops the refresh schedule
+func (f *File) Close() {
+	f.stop()
+	f.wg.Wait()
+}
+
+func (f *File) Rates(ctx context.Context) (service.FXRates, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	return f.rates, nil
+}
+
+func (f *File) refresh(ctx context.Context, interval time.Duration) {
+	defer f.wg.Done()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := f.load(); err != nil {
+				slog.Error(fmt.Sprintf("[FX] Failed to reload rates, keeping the previous ones: %v", err))
+			}
+		}
+	}
+}
+
+func (f *File) load() error {
+	data, err := os.ReadFile(f.path)
+	if err != nil {
+		return fmt.Errorf("fx read file: %w", err)
+	}
+
+	var rates service.FXRates
+	if err := json.Unmarshal(data, &rates); err != nil {
+		return fmt.Errorf("fx parse file: %w", err)
+	}
+	if rates.Base == "" || len(rates.Rates) == 0 {
+		return fmt.Errorf("fx parse file: %s has no base currency or rates", f.path)
+	}
+	for currency, rate := range rates.Rates {
+		if rate <= 0 {
+			return fmt.Errorf("fx parse file: rate of %s must be positive", currency)
+		}
+	}
+
+	f.mu.Lock()
+	f.rates = rates
+	f.mu.Unlock()
+
+	slog.Info(fmt.Sprintf("[FX] Loaded %d rates against %s from %s", len(rates.Rates), rates.Base, f.path))
+	return nil
+}
@@ -0,0 +1,81 @@
+package fx_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elkoshar/bookcabin/service/fx"
+)
+
+func writeRates(t *testing.T, path, content string) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+}
+
+func TestNewFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rates.json")
+	writeRates(t, path, `{"base":"USD","rates":{"IDR":16400,"SGD":1.34}}`)
+
+	f, err := fx.NewFile(path, 0)
+	require.NoError(t, err)
+	defer f.Close()
+
+	rates, err := f.Rates(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "USD", rates.Base)
+	assert.Equal(t, 16400.0, rates.Rates["IDR"])
+}
+
+func TestNewFile_Invalid(t *testing.T) {
+	dir := t.TempDir()
+
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{name: "malformed", content: `{"base":`},
+		{name: "no base", content: `{"rates":{"IDR":16400}}`},
+		{name: "no rates", content: `{"base":"USD","rates":{}}`},
+		{name: "negative rate", content: `{"base":"USD","rates":{"IDR":-1}}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(dir, tt.name+".json")
+			writeRates(t, path, tt.content)
+
+			_, err := fx.NewFile(path, 0)
+			assert.Error(t, err)
+		})
+	}
+
+	_, err := fx.NewFile(filepath.Join(dir, "missing.json"), 0)
+	assert.Error(t, err)
+}
+
+func TestFile_Refresh(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rates.json")
+	writeRates(t, path, `{"base":"USD","rates":{"IDR":16400}}`)
+
+	f, err := fx.NewFile(path, 10*time.Millisecond)
+	require.NoError(t, err)
+	defer f.Close()
+
+	// a broken file keeps the rates loaded before
+	writeRates(t, path, `not json`)
+	time.Sleep(50 * time.Millisecond)
+	rates, _ := f.Rates(context.Background())
+	assert.Equal(t, 16400.0, rates.Rates["IDR"])
+
+	writeRates(t, path, `{"base":"USD","rates":{"IDR":16500}}`)
+	assert.Eventually(t, func() bool {
+		rates, _ := f.Rates(context.Background())
+		return rates.Rates["IDR"] == 16500
+	}, time.Second, 10*time.Millisecond)
+}
@@ -0,0 +1,173 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/elkoshar/bookcabin/pkg/i18n"
+)
+
+// Currencies prices can be shown in, providers always sell in IDR
+const (
+	CurrencyIDR = "IDR"
+	CurrencyUSD = "USD"
+	CurrencySGD = "SGD"
+	CurrencyMYR = "MYR"
+)
+
+var (
+	ErrUnsupportedCurrency = errors.New("unsupported currency")
+	ErrRatesUnavailable    = errors.New("exchange rates are not available")
+)
+
+// FXRates are exchange rates relative to Base, one unit of Base is worth Rates[c] units of c
+type FXRates struct {
+	Base      string             `json:"base"`
+	Rates     map[string]float64 `json:"rates"`
+	UpdatedAt time.Time          `json:"updated_at"`
+}
+
+// Rate returns how many units of to one unit of from is worth
+func (r FXRates) Rate(from, to string) (float64, error) {
+	if from == to {
+		return 1, nil
+	}
+
+	fromRate, err := r.rate(from)
+	if err != nil {
+		return 0, err
+	}
+	toRate, err := r.rate(to)
+	if err != nil {
+		return 0, err
+	}
+	return toRate / fromRate, nil
+}
+
+func (r FXRates) rate(currency string) (float64, error) {
+	if currency == r.Base {
+		return 1, nil
+	}
+	if rate, ok := r.Rates[currency]; ok && rate > 0 {
+		return rate, nil
+	}
+	return 0, fmt.Errorf("%w: %s", ErrUnsupportedCurrency, currency)
+}
+
+// Convert returns p in currency, keeping the amount the provider sold it for in OriginalAmount
+// and OriginalCurrency. Rupiah amounts are rounded to whole rupiah, other currencies to cents.
+func (p PriceInfo) Convert(rates FXRates, currency string) (PriceInfo, error) {
+	if p.Currency == "" || p.Currency == currency {
+		return p, nil
+	}
+
+	rate, err := rates.Rate(p.Currency, currency)
+	if err != nil {
+		return PriceInfo{}, err
+	}
+
+	amount := p.Amount * rate
+	if currency == CurrencyIDR {
+		amount = math.Round(amount)
+	} else {
+		amount = math.Round(amount*100) / 100
+	}
+
+	return PriceInfo{
+		Amount:           amount,
+		Currency:         currency,
+		Formatted:        i18n.FormatPrice(i18n.Default, amount, currency),
+		OriginalAmount:   p.Amount,
+		OriginalCurrency: p.Currency,
+		ExchangeRate:     rate,
+	}, nil
+}
+
+// The Convert methods below return a copy with every price in currency, slices are copied so results
+// shared with storage are left untouched
+
+func (f UnifiedFlight) Convert(rates FXRates, currency string) (UnifiedFlight, error) {
+	var err error
+	if f.Price, err = f.Price.Convert(rates, currency); err != nil {
+		return UnifiedFlight{}, err
+	}
+
+	if f.FareRules != nil {
+		rules := *f.FareRules
+		if rules.ChangeFee, err = rules.ChangeFee.Convert(rates, currency); err != nil {
+			return UnifiedFlight{}, err
+		}
+		if rules.CancellationFee, err = rules.CancellationFee.Convert(rates, currency); err != nil {
+			return UnifiedFlight{}, err
+		}
+		f.FareRules = &rules
+	}
+
+	if f.AlternativeOffers != nil {
+		offers := make([]AlternativeOffer, len(f.AlternativeOffers))
+		for i, offer := range f.AlternativeOffers {
+			if offer.Price, err = offer.Price.Convert(rates, currency); err != nil {
+				return UnifiedFlight{}, err
+			}
+			offers[i] = offer
+		}
+		f.AlternativeOffers = offers
+	}
+	return f, nil
+}
+
+// ConvertFlights converts every flight of a result list
+func ConvertFlights(flights []UnifiedFlight, rates FXRates, currency string) ([]UnifiedFlight, error) {
+	if flights == nil {
+		return nil, nil
+	}
+
+	converted := make([]UnifiedFlight, len(flights))
+	for i, f := range flights {
+		c, err := f.Convert(rates, currency)
+		if err != nil {
+			return nil, err
+		}
+		converted[i] = c
+	}
+	return converted, nil
+}
+
+func (r SearchResponse) Convert(rates FXRates, currency string) (SearchResponse, error) {
+	var err error
+	if r.Flights, err = ConvertFlights(r.Flights, rates, currency); err != nil {
+		return SearchResponse{}, err
+	}
+	if r.ReturnFlights, err = ConvertFlights(r.ReturnFlights, rates, currency); err != nil {
+		return SearchResponse{}, err
+	}
+
+	if r.MultiCityFlights != nil {
+		legs := make([][]UnifiedFlight, len(r.MultiCityFlights))
+		for i, leg := range r.MultiCityFlights {
+			if legs[i], err = ConvertFlights(leg, rates, currency); err != nil {
+				return SearchResponse{}, err
+			}
+		}
+		r.MultiCityFlights = legs
+	}
+	return r, nil
+}
+
+func (e SearchEvent) Convert(rates FXRates, currency string) (SearchEvent, error) {
+	var err error
+	if e.Flights, err = ConvertFlights(e.Flights, rates, currency); err != nil {
+		return SearchEvent{}, err
+	}
+
+	if e.Summary != nil {
+		summary, err := e.Summary.Convert(rates, currency)
+		if err != nil {
+			return SearchEvent{}, err
+		}
+		e.Summary = &summary
+	}
+	return e, nil
+}
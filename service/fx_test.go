@@ -0,0 +1,73 @@
+package service_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elkoshar/bookcabin/service"
+)
+
+var testRates = service.FXRates{Base: "USD", Rates: map[string]float64{"IDR": 16000, "SGD": 1.35}}
+
+func TestFXRates_Rate(t *testing.T) {
+	tests := []struct {
+		from, to string
+		want     float64
+		wantErr  bool
+	}{
+		{from: "IDR", to: "IDR", want: 1},
+		{from: "USD", to: "IDR", want: 16000},
+		{from: "IDR", to: "USD", want: 1.0 / 16000},
+		{from: "IDR", to: "SGD", want: 1.35 / 16000},
+		{from: "IDR", to: "MYR", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.from+"_"+tt.to, func(t *testing.T) {
+			got, err := testRates.Rate(tt.from, tt.to)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, service.ErrUnsupportedCurrency)
+				return
+			}
+			require.NoError(t, err)
+			assert.InDelta(t, tt.want, got, 1e-12)
+		})
+	}
+}
+
+func TestSearchResponse_Convert(t *testing.T) {
+	flight := service.UnifiedFlight{
+		Price:             service.PriceInfo{Amount: 1250000, Currency: "IDR", Formatted: "IDR 1.250.000"},
+		FareRules:         &service.FareRulesSummary{ChangeFee: service.PriceInfo{Amount: 160000, Currency: "IDR"}},
+		AlternativeOffers: []service.AlternativeOffer{{Price: service.PriceInfo{Amount: 1300000, Currency: "IDR"}}},
+	}
+	resp := service.SearchResponse{Flights: []service.UnifiedFlight{flight}}
+
+	usd, err := resp.Convert(testRates, "USD")
+	require.NoError(t, err)
+
+	got := usd.Flights[0]
+	assert.Equal(t, service.PriceInfo{
+		Amount:           78.13,
+		Currency:         "USD",
+		Formatted:        "US$ 78,13",
+		OriginalAmount:   1250000,
+		OriginalCurrency: "IDR",
+		ExchangeRate:     1.0 / 16000,
+	}, got.Price)
+	assert.Equal(t, 10.0, got.FareRules.ChangeFee.Amount)
+	assert.Equal(t, 81.25, got.AlternativeOffers[0].Price.Amount)
+
+	// the original response, which may be shared with storage, is left untouched
+	assert.Equal(t, flight, resp.Flights[0])
+	assert.Equal(t, "IDR", resp.Flights[0].FareRules.ChangeFee.Currency)
+
+	idr, err := resp.Convert(testRates, "IDR")
+	require.NoError(t, err)
+	assert.Equal(t, flight.Price, idr.Flights[0].Price)
+
+	_, err = resp.Convert(testRates, "MYR")
+	assert.ErrorIs(t, err, service.ErrUnsupportedCurrency)
+}
@@ -0,0 +1,32 @@
+package garuda
+
+import (
+	"context"
+
+	entity "github.com/elkoshar/bookcabin/service"
+)
+
+// CreateHold reserves seats on the simulated Garuda Indonesia reservation system
+func (p *Provider) CreateHold(ctx context.Context, req entity.HoldRequest) (entity.ProviderHold, error) {
+	return p.booking.CreateHold(ctx, req)
+}
+
+// RetrieveHold returns the current state of a hold by its record locator
+func (p *Provider) RetrieveHold(ctx context.Context, locator string) (entity.ProviderHold, error) {
+	return p.booking.RetrieveHold(ctx, locator)
+}
+
+// CancelHold releases a hold and its seats
+func (p *Provider) CancelHold(ctx context.Context, locator string) error {
+	return p.booking.CancelHold(ctx, locator)
+}
+
+// ConfirmHold sells the seats of a paid hold
+func (p *Provider) ConfirmHold(ctx context.Context, locator string) error {
+	return p.booking.ConfirmHold(ctx, locator)
+}
+
+// CancelConfirmed cancels a sold booking and gives its seats back
+func (p *Provider) CancelConfirmed(ctx context.Context, locator string) error {
+	return p.booking.CancelConfirmed(ctx, locator)
+}
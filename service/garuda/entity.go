@@ -8,21 +8,37 @@ type response struct {
 }
 
 type flight struct {
-	FlightID  string   `json:"flight_id"`
-	Airline   string   `json:"airline"`
-	Departure endpoint `json:"departure"`
-	Arrival   endpoint `json:"arrival"`
-	Price     price    `json:"price"`
-	Stops     int      `json:"stops"`
-	Seats     int      `json:"available_seats"`
-	FareClass string   `json:"fare_class"`
-	Amenities []string `json:"amenities"`
+	FlightID  string    `json:"flight_id"`
+	Airline   string    `json:"airline"`
+	Departure endpoint  `json:"departure"`
+	Arrival   endpoint  `json:"arrival"`
+	Price     price     `json:"price"`
+	Stops     int       `json:"stops"`
+	Segments  []segment `json:"segments"`
+	Seats     int       `json:"available_seats"`
+	FareClass string    `json:"fare_class"`
+	Aircraft  string    `json:"aircraft"`
+	Baggage   baggage   `json:"baggage"`
+	Amenities []string  `json:"amenities"`
+}
+
+type segment struct {
+	FlightNumber    string   `json:"flight_number"`
+	Departure       endpoint `json:"departure"`
+	Arrival         endpoint `json:"arrival"`
+	DurationMinutes int      `json:"duration_minutes"`
+}
+
+type baggage struct {
+	CarryOn int `json:"carry_on"`
+	Checked int `json:"checked"`
 }
 
 type endpoint struct {
-	Airport string `json:"airport"`
-	City    string `json:"city"`
-	Time    string `json:"time"`
+	Airport  string `json:"airport"`
+	City     string `json:"city"`
+	Time     string `json:"time"`
+	Terminal string `json:"terminal"`
 }
 
 type price struct {
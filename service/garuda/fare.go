@@ -0,0 +1,18 @@
+package garuda
+
+import (
+	"strings"
+
+	entity "github.com/elkoshar/bookcabin/service"
+)
+
+// fareFamily maps a Garuda Indonesia fare_class onto a fare family, full-service economy is flexible
+func fareFamily(fareClass string) string {
+	switch strings.ToLower(fareClass) {
+	case "business":
+		return entity.FareFamilyBusiness
+	case "first":
+		return entity.FareFamilyFirst
+	}
+	return entity.FareFamilyEconomyFlex
+}
@@ -10,14 +10,20 @@ import (
 
 	"github.com/elkoshar/bookcabin/pkg/helpers"
 	entity "github.com/elkoshar/bookcabin/service"
+	"github.com/elkoshar/bookcabin/service/farerules"
+	"github.com/elkoshar/bookcabin/service/simulator"
 )
 
 type Provider struct {
 	dataPath string
+	booking  *simulator.BookingBackend
 }
 
 func New(path string) *Provider {
-	return &Provider{dataPath: path}
+	return &Provider{
+		dataPath: path,
+		booking:  simulator.NewBookingBackend(),
+	}
 }
 
 func (p *Provider) Name() string { return "Garuda Indonesia" }
@@ -55,20 +61,56 @@ func (p *Provider) Search(ctx context.Context, c entity.SearchCriteria) ([]entit
 		}
 		durationMins := int(arrTime.Sub(depTime).Minutes())
 
+		offerID := entity.NewOfferID(entity.OfferKey{Provider: p.Name(), FlightNumber: f.FlightID, Origin: f.Departure.Airport, Destination: f.Arrival.Airport, DepartureDate: c.DepartureDate, CabinClass: f.FareClass})
+
 		results = append(results, entity.UnifiedFlight{
-			ID:             fmt.Sprintf("%s_Garuda", f.FlightID),
+			ID:             offerID,
 			Provider:       p.Name(),
 			Airline:        entity.AirlineInfo{Name: f.Airline, Code: "GA"},
 			FlightNumber:   f.FlightID,
-			Departure:      entity.LocationInfo{Airport: f.Departure.Airport, City: f.Departure.City, DateTime: f.Departure.Time, Timestamp: depTime.Unix()},
-			Arrival:        entity.LocationInfo{Airport: f.Arrival.Airport, City: f.Arrival.City, DateTime: f.Arrival.Time, Timestamp: arrTime.Unix()},
+			Departure:      location(f.Departure),
+			Arrival:        location(f.Arrival),
 			Duration:       entity.DurationInfo{TotalMinutes: durationMins, Formatted: fmt.Sprintf("%dh %dm", durationMins/60, durationMins%60)},
 			Stops:          f.Stops,
+			Segments:       segments(f.Segments),
 			Price:          entity.PriceInfo{Amount: f.Price.Amount, Currency: "IDR"},
-			AvailableSeats: f.Seats,
+			AvailableSeats: f.Seats - p.booking.HeldSeats(offerID),
 			CabinClass:     f.FareClass,
 			Amenities:      f.Amenities,
+			Aircraft:       f.Aircraft,
+			Baggage:        &entity.BaggageInfo{CabinPieces: f.Baggage.CarryOn, CheckedPieces: f.Baggage.Checked},
+			MealIncluded:   helpers.StringExists(f.Amenities, "meal"),
+			FareRules:      farerules.Summary(fareFamily(f.FareClass)),
 		})
 	}
 	return results, nil
 }
+
+// location converts a departure or arrival, keeping the local time of the airport
+func location(e endpoint) entity.LocationInfo {
+	t, _ := time.ParseInLocation(time.RFC3339, e.Time, helpers.GetTimezone(e.Time))
+
+	city := e.City
+	if city == "" {
+		city = helpers.GetCityName(e.Airport)
+	}
+	return entity.LocationInfo{Airport: e.Airport, City: city, Terminal: e.Terminal, DateTime: e.Time, Timestamp: t.Unix()}
+}
+
+// segments converts the legs of a connecting flight, direct flights have none
+func segments(legs []segment) []entity.FlightSegment {
+	if len(legs) == 0 {
+		return nil
+	}
+
+	result := make([]entity.FlightSegment, len(legs))
+	for i, leg := range legs {
+		result[i] = entity.FlightSegment{
+			FlightNumber: leg.FlightNumber,
+			Departure:    location(leg.Departure),
+			Arrival:      location(leg.Arrival),
+			Duration:     entity.DurationInfo{TotalMinutes: leg.DurationMinutes, Formatted: fmt.Sprintf("%dh %dm", leg.DurationMinutes/60, leg.DurationMinutes%60)},
+		}
+	}
+	return result
+}
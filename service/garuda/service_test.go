@@ -33,14 +33,20 @@ func TestProvider_Search_Success(t *testing.T) {
 				"flight_id": "GA100",
 				"airline":   "Garuda Indonesia",
 				"departure": map[string]interface{}{
-					"airport": "CGK",
-					"city":    "Jakarta",
-					"time":    "2025-12-15T06:00:00+07:00",
+					"airport":  "CGK",
+					"city":     "Jakarta",
+					"time":     "2025-12-15T06:00:00+07:00",
+					"terminal": "3",
 				},
 				"arrival": map[string]interface{}{
-					"airport": "DPS",
-					"city":    "Denpasar",
-					"time":    "2025-12-15T09:30:00+08:00",
+					"airport":  "DPS",
+					"city":     "Denpasar",
+					"time":     "2025-12-15T09:30:00+08:00",
+					"terminal": "I",
+				},
+				"segments": []map[string]interface{}{
+					{"flight_number": "GA100", "departure": map[string]interface{}{"airport": "CGK", "time": "2025-12-15T06:00:00+07:00"}, "arrival": map[string]interface{}{"airport": "SUB", "time": "2025-12-15T07:30:00+07:00"}, "duration_minutes": 90},
+					{"flight_number": "GA101", "departure": map[string]interface{}{"airport": "SUB", "time": "2025-12-15T08:15:00+07:00"}, "arrival": map[string]interface{}{"airport": "DPS", "time": "2025-12-15T09:30:00+08:00"}, "duration_minutes": 75},
 				},
 				"price": map[string]interface{}{
 					"amount":   3500000.0,
@@ -49,6 +55,8 @@ func TestProvider_Search_Success(t *testing.T) {
 				"stops":           0,
 				"available_seats": 75,
 				"fare_class":      "economy",
+				"baggage":         map[string]interface{}{"carry_on": 1, "checked": 2},
+				"amenities":       []string{"wifi", "meal"},
 			},
 		},
 	}
@@ -79,7 +87,7 @@ func TestProvider_Search_Success(t *testing.T) {
 	assert.Len(t, flights, 1)
 
 	flight := flights[0]
-	assert.Equal(t, "GA100_Garuda", flight.ID)
+	assert.Equal(t, service.NewOfferID(service.OfferKey{Provider: "Garuda Indonesia", FlightNumber: "GA100", Origin: "CGK", Destination: "DPS", DepartureDate: "2025-12-15", CabinClass: "economy"}), flight.ID)
 	assert.Equal(t, "Garuda Indonesia", flight.Provider)
 	assert.Equal(t, "Garuda Indonesia", flight.Airline.Name)
 	assert.Equal(t, "GA", flight.Airline.Code)
@@ -91,6 +99,15 @@ func TestProvider_Search_Success(t *testing.T) {
 	assert.Equal(t, "IDR", flight.Price.Currency)
 	assert.Equal(t, 75, flight.AvailableSeats)
 	assert.Equal(t, "economy", flight.CabinClass)
+	assert.Equal(t, &service.BaggageInfo{CabinPieces: 1, CheckedPieces: 2}, flight.Baggage)
+	assert.True(t, flight.MealIncluded)
+	assert.Equal(t, "3", flight.Departure.Terminal)
+	assert.Equal(t, "I", flight.Arrival.Terminal)
+	assert.Len(t, flight.Segments, 2)
+	assert.Equal(t, "GA101", flight.Segments[1].FlightNumber)
+	assert.Equal(t, "SUB", flight.Segments[1].Departure.Airport)
+	assert.Equal(t, "Surabaya", flight.Segments[1].Departure.City)
+	assert.Equal(t, 75, flight.Segments[1].Duration.TotalMinutes)
 }
 
 func TestProvider_Search_NoMatchingFlights(t *testing.T) {
@@ -15,6 +15,19 @@ type flight struct {
 	Schedule  schedule `json:"schedule"`
 	Pricing   pricing  `json:"pricing"`
 	SeatsLeft int      `json:"seats_left"`
+	PlaneType string   `json:"plane_type"`
+	Services  services `json:"services"`
+}
+
+type services struct {
+	WifiAvailable    bool     `json:"wifi_available"`
+	MealsIncluded    bool     `json:"meals_included"`
+	BaggageAllowance baggages `json:"baggage_allowance"`
+}
+
+type baggages struct {
+	Cabin string `json:"cabin"`
+	Hold  string `json:"hold"`
 }
 
 type carrier struct {
@@ -0,0 +1,18 @@
+package lion
+
+import (
+	"strings"
+
+	entity "github.com/elkoshar/bookcabin/service"
+)
+
+// fareFamily maps a Lion Air fare_type onto a fare family and its cabin, a missing fare type is economy
+func fareFamily(fareType string) (family, cabin string) {
+	switch strings.ToUpper(fareType) {
+	case "PROMO":
+		return entity.FareFamilyLite, "economy"
+	case "BUSINESS":
+		return entity.FareFamilyBusiness, "business"
+	}
+	return entity.FareFamilyEconomy, "economy"
+}
@@ -0,0 +1,32 @@
+package lion
+
+import (
+	"context"
+
+	entity "github.com/elkoshar/bookcabin/service"
+)
+
+// SeatMap returns the seat map of an offer generated from its aircraft type
+func (p *Provider) SeatMap(ctx context.Context, offerID string) (entity.SeatMap, error) {
+	key, err := entity.ParseOfferID(offerID)
+	if err != nil {
+		return entity.SeatMap{}, err
+	}
+
+	flights, err := p.Search(ctx, key.Criteria())
+	if err != nil {
+		return entity.SeatMap{}, err
+	}
+
+	for _, f := range flights {
+		if f.ID == offerID {
+			return p.booking.SeatMap(f)
+		}
+	}
+	return entity.SeatMap{}, entity.ErrOfferNotFound
+}
+
+// ReserveSeats assigns seats to a hold on the simulated Lion Air reservation system
+func (p *Provider) ReserveSeats(ctx context.Context, locator string, seatNumbers []string) ([]entity.Seat, error) {
+	return p.booking.ReserveSeats(ctx, locator, seatNumbers)
+}
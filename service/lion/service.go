@@ -10,14 +10,20 @@ import (
 
 	"github.com/elkoshar/bookcabin/pkg/helpers"
 	entity "github.com/elkoshar/bookcabin/service"
+	"github.com/elkoshar/bookcabin/service/farerules"
+	"github.com/elkoshar/bookcabin/service/simulator"
 )
 
 type Provider struct {
 	dataPath string
+	booking  *simulator.BookingBackend
 }
 
 func New(path string) *Provider {
-	return &Provider{dataPath: path}
+	return &Provider{
+		dataPath: path,
+		booking:  simulator.NewBookingBackend(),
+	}
 }
 
 func (p *Provider) Name() string { return "Lion Air" }
@@ -41,7 +47,8 @@ func (p *Provider) Search(ctx context.Context, c entity.SearchCriteria) ([]entit
 			continue
 		}
 
-		if strings.ToLower(f.Pricing.FareType) != strings.ToLower(c.CabinClass) {
+		family, cabin := fareFamily(f.Pricing.FareType)
+		if cabin != strings.ToLower(c.CabinClass) {
 			continue
 		}
 
@@ -57,8 +64,10 @@ func (p *Provider) Search(ctx context.Context, c entity.SearchCriteria) ([]entit
 
 		dur := int(tArr.Sub(tDep).Minutes())
 
+		offerID := entity.NewOfferID(entity.OfferKey{Provider: p.Name(), FlightNumber: f.ID, Origin: f.Route.From.Code, Destination: f.Route.To.Code, DepartureDate: c.DepartureDate, CabinClass: cabin})
+
 		results = append(results, entity.UnifiedFlight{
-			ID:             fmt.Sprintf("%s_Lion", f.ID),
+			ID:             offerID,
 			Provider:       p.Name(),
 			Airline:        entity.AirlineInfo{Name: f.Carrier.Name, Code: f.Carrier.Iata},
 			FlightNumber:   f.ID,
@@ -66,8 +75,12 @@ func (p *Provider) Search(ctx context.Context, c entity.SearchCriteria) ([]entit
 			Arrival:        entity.LocationInfo{Airport: f.Route.To.Code, City: f.Route.To.City, DateTime: tArr.Format(time.RFC3339), Timestamp: tArr.Unix()},
 			Duration:       entity.DurationInfo{TotalMinutes: dur, Formatted: fmt.Sprintf("%dh %dm", dur/60, dur%60)},
 			Price:          entity.PriceInfo{Amount: f.Pricing.Total, Currency: "IDR"},
-			AvailableSeats: f.SeatsLeft,
-			CabinClass:     "economy",
+			AvailableSeats: f.SeatsLeft - p.booking.HeldSeats(offerID),
+			CabinClass:     cabin,
+			Aircraft:       f.PlaneType,
+			Baggage:        &entity.BaggageInfo{CabinKg: helpers.ParseWeightKg(f.Services.BaggageAllowance.Cabin), CheckedKg: helpers.ParseWeightKg(f.Services.BaggageAllowance.Hold)},
+			MealIncluded:   f.Services.MealsIncluded,
+			FareRules:      farerules.Summary(family),
 		})
 	}
 	return results, nil
@@ -87,7 +87,7 @@ func TestProvider_Search_Success(t *testing.T) {
 	assert.Len(t, flights, 1)
 
 	flight := flights[0]
-	assert.Equal(t, "JT610_Lion", flight.ID)
+	assert.Equal(t, service.NewOfferID(service.OfferKey{Provider: "Lion Air", FlightNumber: "JT610", Origin: "CGK", Destination: "DPS", DepartureDate: "2025-12-15", CabinClass: "economy"}), flight.ID)
 	assert.Equal(t, "Lion Air", flight.Provider)
 	assert.Equal(t, "Lion Air", flight.Airline.Name)
 	assert.Equal(t, "JT", flight.Airline.Code)
@@ -0,0 +1,194 @@
+package service
+
+import "github.com/elkoshar/bookcabin/pkg/i18n"
+
+// The Localize methods return a copy whose city names and formatted durations and prices are in
+// the given language. Slices are copied too, so results shared with storage are left untouched.
+
+func (l LocationInfo) Localize(lang string) LocationInfo {
+	l.City = i18n.City(lang, l.Airport, l.City)
+	return l
+}
+
+func (d DurationInfo) Localize(lang string) DurationInfo {
+	d.Formatted = i18n.FormatDuration(lang, d.TotalMinutes)
+	return d
+}
+
+func (p PriceInfo) Localize(lang string) PriceInfo {
+	if p.Currency == "" {
+		return p
+	}
+	p.Formatted = i18n.FormatPrice(lang, p.Amount, p.Currency)
+	return p
+}
+
+func (f UnifiedFlight) Localize(lang string) UnifiedFlight {
+	f.Departure = f.Departure.Localize(lang)
+	f.Arrival = f.Arrival.Localize(lang)
+	f.Duration = f.Duration.Localize(lang)
+	f.Price = f.Price.Localize(lang)
+
+	if f.Segments != nil {
+		segments := make([]FlightSegment, len(f.Segments))
+		for i, seg := range f.Segments {
+			seg.Departure = seg.Departure.Localize(lang)
+			seg.Arrival = seg.Arrival.Localize(lang)
+			seg.Duration = seg.Duration.Localize(lang)
+			segments[i] = seg
+		}
+		f.Segments = segments
+	}
+
+	if f.FareRules != nil {
+		rules := f.FareRules.Localize(lang)
+		f.FareRules = &rules
+	}
+
+	if f.AlternativeOffers != nil {
+		offers := make([]AlternativeOffer, len(f.AlternativeOffers))
+		for i, offer := range f.AlternativeOffers {
+			offer.Price = offer.Price.Localize(lang)
+			offers[i] = offer
+		}
+		f.AlternativeOffers = offers
+	}
+	return f
+}
+
+// LocalizeFlights localizes every flight of a result list
+func LocalizeFlights(flights []UnifiedFlight, lang string) []UnifiedFlight {
+	if flights == nil {
+		return nil
+	}
+	localized := make([]UnifiedFlight, len(flights))
+	for i, f := range flights {
+		localized[i] = f.Localize(lang)
+	}
+	return localized
+}
+
+func (r SearchResponse) Localize(lang string) SearchResponse {
+	r.Flights = LocalizeFlights(r.Flights, lang)
+	r.ReturnFlights = LocalizeFlights(r.ReturnFlights, lang)
+
+	if r.MultiCityFlights != nil {
+		legs := make([][]UnifiedFlight, len(r.MultiCityFlights))
+		for i, leg := range r.MultiCityFlights {
+			legs[i] = LocalizeFlights(leg, lang)
+		}
+		r.MultiCityFlights = legs
+	}
+	return r
+}
+
+func (e SearchEvent) Localize(lang string) SearchEvent {
+	e.Flights = LocalizeFlights(e.Flights, lang)
+	if e.Summary != nil {
+		summary := e.Summary.Localize(lang)
+		e.Summary = &summary
+	}
+	return e
+}
+
+func (j SearchJob) Localize(lang string) SearchJob {
+	j.Flights = LocalizeFlights(j.Flights, lang)
+	if j.Result != nil {
+		result := j.Result.Localize(lang)
+		j.Result = &result
+	}
+	return j
+}
+
+func (b Booking) Localize(lang string) Booking {
+	b.Flight = b.Flight.Localize(lang)
+	b.TotalPrice = b.TotalPrice.Localize(lang)
+	b.FareBreakdown = FareBreakdown{
+		BaseFare:    b.FareBreakdown.BaseFare.Localize(lang),
+		Seats:       b.FareBreakdown.Seats.Localize(lang),
+		Ancillaries: b.FareBreakdown.Ancillaries.Localize(lang),
+		Total:       b.FareBreakdown.Total.Localize(lang),
+	}
+
+	if b.Seats != nil {
+		seats := make([]SeatAssignment, len(b.Seats))
+		for i, seat := range b.Seats {
+			seat.Price = seat.Price.Localize(lang)
+			seats[i] = seat
+		}
+		b.Seats = seats
+	}
+
+	if b.Ancillaries != nil {
+		ancillaries := make([]BookedAncillary, len(b.Ancillaries))
+		for i, a := range b.Ancillaries {
+			a.Price = a.Price.Localize(lang)
+			ancillaries[i] = a
+		}
+		b.Ancillaries = ancillaries
+	}
+
+	if b.Cancellation != nil {
+		quote := b.Cancellation.Localize(lang)
+		b.Cancellation = &quote
+	}
+	return b
+}
+
+func (q CancellationQuote) Localize(lang string) CancellationQuote {
+	q.PaidAmount = q.PaidAmount.Localize(lang)
+	q.CancellationFee = q.CancellationFee.Localize(lang)
+	q.NoShowFee = q.NoShowFee.Localize(lang)
+	q.NonRefundable = q.NonRefundable.Localize(lang)
+	q.RefundAmount = q.RefundAmount.Localize(lang)
+	return q
+}
+
+func (r RepriceResult) Localize(lang string) RepriceResult {
+	if r.Flight != nil {
+		flight := r.Flight.Localize(lang)
+		r.Flight = &flight
+	}
+	return r
+}
+
+func (r FareRulesSummary) Localize(lang string) FareRulesSummary {
+	r.ChangeFee = r.ChangeFee.Localize(lang)
+	r.CancellationFee = r.CancellationFee.Localize(lang)
+	return r
+}
+
+func (r FareRules) Localize(lang string) FareRules {
+	r.FareRulesSummary = r.FareRulesSummary.Localize(lang)
+	return r
+}
+
+func (c AncillaryCatalogue) Localize(lang string) AncillaryCatalogue {
+	ancillaries := make([]Ancillary, len(c.Ancillaries))
+	for i, a := range c.Ancillaries {
+		a.Price = a.Price.Localize(lang)
+		ancillaries[i] = a
+	}
+	c.Ancillaries = ancillaries
+	return c
+}
+
+func (m SeatMap) Localize(lang string) SeatMap {
+	cabins := make([]SeatCabin, len(m.Cabins))
+	for i, cabin := range m.Cabins {
+		rows := make([]SeatRow, len(cabin.Rows))
+		for j, row := range cabin.Rows {
+			seats := make([]Seat, len(row.Seats))
+			for k, seat := range row.Seats {
+				seat.Price = seat.Price.Localize(lang)
+				seats[k] = seat
+			}
+			row.Seats = seats
+			rows[j] = row
+		}
+		cabin.Rows = rows
+		cabins[i] = cabin
+	}
+	m.Cabins = cabins
+	return m
+}
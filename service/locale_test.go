@@ -0,0 +1,49 @@
+package service_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elkoshar/bookcabin/pkg/i18n"
+	"github.com/elkoshar/bookcabin/service"
+)
+
+func TestSearchResponse_Localize(t *testing.T) {
+	flight := service.UnifiedFlight{
+		Departure: service.LocationInfo{Airport: "CGK", City: "Jakarta"},
+		Arrival:   service.LocationInfo{Airport: "SIN", City: "Singapore"},
+		Duration:  service.DurationInfo{TotalMinutes: 105, Formatted: "1h 45m"},
+		Price:     service.PriceInfo{Amount: 1250000, Currency: "IDR", Formatted: "IDR 1.250.000"},
+		Segments: []service.FlightSegment{
+			{Departure: service.LocationInfo{Airport: "CGK"}, Arrival: service.LocationInfo{Airport: "SIN"}, Duration: service.DurationInfo{TotalMinutes: 105}},
+		},
+		FareRules: &service.FareRulesSummary{ChangeFee: service.PriceInfo{Amount: 150000, Currency: "IDR"}},
+	}
+	resp := service.SearchResponse{
+		Flights:          []service.UnifiedFlight{flight},
+		MultiCityFlights: [][]service.UnifiedFlight{{flight}},
+	}
+
+	id := resp.Localize(i18n.Indonesian)
+	got := id.Flights[0]
+	assert.Equal(t, "Jakarta", got.Departure.City)
+	assert.Equal(t, "Singapura", got.Arrival.City)
+	assert.Equal(t, "1j 45m", got.Duration.Formatted)
+	assert.Equal(t, "Rp 1.250.000", got.Price.Formatted)
+	assert.Equal(t, "Singapura", got.Segments[0].Arrival.City)
+	assert.Equal(t, "1j 45m", got.Segments[0].Duration.Formatted)
+	assert.Equal(t, "Rp 150.000", got.FareRules.ChangeFee.Formatted)
+	assert.Equal(t, got, id.MultiCityFlights[0][0])
+	assert.Nil(t, id.ReturnFlights)
+
+	en := resp.Localize(i18n.English).Flights[0]
+	assert.Equal(t, "Singapore", en.Arrival.City)
+	assert.Equal(t, "1h 45m", en.Duration.Formatted)
+	assert.Equal(t, "IDR 1,250,000", en.Price.Formatted)
+
+	// the original response, which may be shared with storage, is left untouched
+	assert.Equal(t, flight, resp.Flights[0])
+	assert.Empty(t, resp.Flights[0].Segments[0].Arrival.City)
+	assert.Empty(t, resp.Flights[0].FareRules.ChangeFee.Formatted)
+}
@@ -0,0 +1,71 @@
+package service
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+)
+
+const offerIDVersion = "v1"
+
+var (
+	ErrInvalidOfferID = errors.New("invalid offer id")
+	ErrOfferNotFound  = errors.New("offer not found")
+)
+
+// OfferKey holds everything needed to find an offer again at its provider
+type OfferKey struct {
+	Provider      string
+	FlightNumber  string
+	Origin        string
+	Destination   string
+	DepartureDate string
+	CabinClass    string
+}
+
+// NewOfferID encodes the key into a stable, URL-safe and opaque offer ID
+func NewOfferID(key OfferKey) string {
+	raw := strings.Join([]string{
+		offerIDVersion,
+		key.Provider,
+		strings.ToUpper(key.FlightNumber),
+		strings.ToUpper(key.Origin),
+		strings.ToUpper(key.Destination),
+		key.DepartureDate,
+		strings.ToLower(key.CabinClass),
+	}, "|")
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// ParseOfferID decodes an ID created by NewOfferID
+func ParseOfferID(id string) (OfferKey, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(id)
+	if err != nil {
+		return OfferKey{}, ErrInvalidOfferID
+	}
+
+	parts := strings.Split(string(raw), "|")
+	if len(parts) != 7 || parts[0] != offerIDVersion {
+		return OfferKey{}, ErrInvalidOfferID
+	}
+
+	return OfferKey{
+		Provider:      parts[1],
+		FlightNumber:  parts[2],
+		Origin:        parts[3],
+		Destination:   parts[4],
+		DepartureDate: parts[5],
+		CabinClass:    parts[6],
+	}, nil
+}
+
+// Criteria returns the search criteria that re-query the offer at its provider
+func (k OfferKey) Criteria() SearchCriteria {
+	return SearchCriteria{
+		Origin:        k.Origin,
+		Destination:   k.Destination,
+		DepartureDate: k.DepartureDate,
+		Passengers:    1,
+		CabinClass:    k.CabinClass,
+	}
+}
@@ -0,0 +1,175 @@
+package payment
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/elkoshar/bookcabin/pkg/helpers"
+	"github.com/elkoshar/bookcabin/service"
+)
+
+// Test cards understood by the fake gateway, any other card number is declined
+const (
+	CardSuccess   = "4111111111111111"
+	CardDecline   = "4000000000000002"
+	CardChallenge = "4000000000003220"
+)
+
+// Fake is an in-process payment gateway for development and tests. Cards requiring a 3DS
+// challenge stay pending until ResolveChallenge, which produces the signed webhook the real
+// gateway would send.
+type Fake struct {
+	secret string
+
+	mu       sync.Mutex
+	payments map[string]*service.Payment
+	sequence int
+}
+
+func NewFake(webhookSecret string) *Fake {
+	return &Fake{
+		secret:   webhookSecret,
+		payments: make(map[string]*service.Payment),
+	}
+}
+
+func (f *Fake) Authorize(ctx context.Context, req service.AuthorizeRequest) (service.Payment, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.sequence++
+	now := time.Now()
+	p := &service.Payment{
+		ID:             fmt.Sprintf("pay_%06d", f.sequence),
+		Reference:      req.Reference,
+		Amount:         req.Amount,
+		CapturedAmount: price(req.Amount.Currency, 0),
+		RefundedAmount: price(req.Amount.Currency, 0),
+		CardLast4:      last4(req.Card.CardNumber),
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+
+	switch req.Card.CardNumber {
+	case CardSuccess:
+		p.Status = service.PaymentStatusAuthorized
+	case CardChallenge:
+		p.Status = service.PaymentStatusRequiresAction
+		p.ChallengeURL = "/bookcabin/payments/" + p.ID + "/challenge"
+	default:
+		p.Status = service.PaymentStatusDeclined
+	}
+	f.payments[p.ID] = p
+
+	if p.Status == service.PaymentStatusDeclined {
+		return *p, service.ErrPaymentDeclined
+	}
+	return *p, nil
+}
+
+func (f *Fake) Capture(ctx context.Context, id string, amount float64) (service.Payment, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	p, ok := f.payments[id]
+	if !ok {
+		return service.Payment{}, service.ErrPaymentNotFound
+	}
+	if p.Status != service.PaymentStatusAuthorized || amount <= 0 || amount > p.Amount.Amount {
+		return service.Payment{}, service.ErrInvalidPaymentState
+	}
+
+	p.Status = service.PaymentStatusCaptured
+	p.CapturedAmount = price(p.Amount.Currency, amount)
+	p.UpdatedAt = time.Now()
+	return *p, nil
+}
+
+func (f *Fake) Void(ctx context.Context, id string) (service.Payment, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	p, ok := f.payments[id]
+	if !ok {
+		return service.Payment{}, service.ErrPaymentNotFound
+	}
+	if p.Status != service.PaymentStatusAuthorized && p.Status != service.PaymentStatusRequiresAction {
+		return service.Payment{}, service.ErrInvalidPaymentState
+	}
+
+	p.Status = service.PaymentStatusVoided
+	p.UpdatedAt = time.Now()
+	return *p, nil
+}
+
+// Refund returns part or all of a captured amount, the payment is refunded once nothing is left
+func (f *Fake) Refund(ctx context.Context, id string, amount float64) (service.Payment, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	p, ok := f.payments[id]
+	if !ok {
+		return service.Payment{}, service.ErrPaymentNotFound
+	}
+	refundable := p.CapturedAmount.Amount - p.RefundedAmount.Amount
+	if p.Status != service.PaymentStatusCaptured || amount <= 0 || amount > refundable {
+		return service.Payment{}, service.ErrInvalidPaymentState
+	}
+
+	p.RefundedAmount = price(p.Amount.Currency, p.RefundedAmount.Amount+amount)
+	if p.RefundedAmount.Amount >= p.CapturedAmount.Amount {
+		p.Status = service.PaymentStatusRefunded
+	}
+	p.UpdatedAt = time.Now()
+	return *p, nil
+}
+
+// ResolveChallenge completes the 3DS challenge of a pending payment and returns the signed
+// webhook body announcing the outcome
+func (f *Fake) ResolveChallenge(ctx context.Context, id string, approved bool) ([]byte, string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	p, ok := f.payments[id]
+	if !ok {
+		return nil, "", service.ErrPaymentNotFound
+	}
+	if p.Status != service.PaymentStatusRequiresAction {
+		return nil, "", service.ErrInvalidPaymentState
+	}
+
+	event := service.PaymentEvent{
+		ID:        "evt_" + p.ID,
+		Type:      service.PaymentEventDeclined,
+		PaymentID: p.ID,
+		Reference: p.Reference,
+		CreatedAt: time.Now(),
+	}
+	p.Status = service.PaymentStatusDeclined
+	if approved {
+		p.Status = service.PaymentStatusAuthorized
+		event.Type = service.PaymentEventAuthorized
+	}
+	p.ChallengeURL = ""
+	p.UpdatedAt = event.CreatedAt
+
+	body, err := json.Marshal(event)
+	if err != nil {
+		return nil, "", err
+	}
+	return body, Sign(f.secret, body), nil
+}
+
+func price(currency string, amount float64) service.PriceInfo {
+	return service.PriceInfo{Amount: amount, Currency: currency, Formatted: helpers.FormatIDR(amount)}
+}
+
+func last4(number string) string {
+	if len(number) < 4 {
+		return number
+	}
+	return number[len(number)-4:]
+}
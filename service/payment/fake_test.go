@@ -0,0 +1,102 @@
+package payment_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/elkoshar/bookcabin/service"
+	"github.com/elkoshar/bookcabin/service/payment"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const secret = "test-secret"
+
+func authorizeRequest(card string) service.AuthorizeRequest {
+	return service.AuthorizeRequest{
+		Reference: "ABC234",
+		Amount:    service.PriceInfo{Amount: 1000000, Currency: "IDR"},
+		Card:      service.PaymentRequest{CardNumber: card, HolderName: "Budi Santoso", ExpiryMonth: 12, ExpiryYear: 2030, CVV: "123"},
+	}
+}
+
+func TestFake_CaptureAndRefund(t *testing.T) {
+	gateway := payment.NewFake(secret)
+	ctx := context.Background()
+
+	p, err := gateway.Authorize(ctx, authorizeRequest(payment.CardSuccess))
+	require.NoError(t, err)
+	assert.Equal(t, service.PaymentStatusAuthorized, p.Status)
+	assert.Equal(t, "1111", p.CardLast4)
+
+	_, err = gateway.Capture(ctx, p.ID, 2000000)
+	assert.ErrorIs(t, err, service.ErrInvalidPaymentState)
+
+	p, err = gateway.Capture(ctx, p.ID, 1000000)
+	require.NoError(t, err)
+	assert.Equal(t, service.PaymentStatusCaptured, p.Status)
+
+	_, err = gateway.Void(ctx, p.ID)
+	assert.ErrorIs(t, err, service.ErrInvalidPaymentState)
+
+	p, err = gateway.Refund(ctx, p.ID, 400000)
+	require.NoError(t, err)
+	assert.Equal(t, service.PaymentStatusCaptured, p.Status)
+	assert.Equal(t, float64(400000), p.RefundedAmount.Amount)
+
+	p, err = gateway.Refund(ctx, p.ID, 600000)
+	require.NoError(t, err)
+	assert.Equal(t, service.PaymentStatusRefunded, p.Status)
+
+	_, err = gateway.Refund(ctx, "pay_missing", 1)
+	assert.ErrorIs(t, err, service.ErrPaymentNotFound)
+}
+
+func TestFake_Decline(t *testing.T) {
+	gateway := payment.NewFake(secret)
+
+	p, err := gateway.Authorize(context.Background(), authorizeRequest(payment.CardDecline))
+	assert.ErrorIs(t, err, service.ErrPaymentDeclined)
+	assert.Equal(t, service.PaymentStatusDeclined, p.Status)
+}
+
+func TestFake_Challenge(t *testing.T) {
+	gateway := payment.NewFake(secret)
+	ctx := context.Background()
+
+	p, err := gateway.Authorize(ctx, authorizeRequest(payment.CardChallenge))
+	require.NoError(t, err)
+	assert.Equal(t, service.PaymentStatusRequiresAction, p.Status)
+	assert.NotEmpty(t, p.ChallengeURL)
+
+	_, err = gateway.Capture(ctx, p.ID, 1000000)
+	assert.ErrorIs(t, err, service.ErrInvalidPaymentState)
+
+	body, signature, err := gateway.ResolveChallenge(ctx, p.ID, true)
+	require.NoError(t, err)
+	assert.NoError(t, payment.Verify(secret, body, signature))
+
+	var event service.PaymentEvent
+	require.NoError(t, json.Unmarshal(body, &event))
+	assert.Equal(t, service.PaymentEventAuthorized, event.Type)
+	assert.Equal(t, p.ID, event.PaymentID)
+	assert.Equal(t, "ABC234", event.Reference)
+
+	_, _, err = gateway.ResolveChallenge(ctx, p.ID, true)
+	assert.ErrorIs(t, err, service.ErrInvalidPaymentState)
+
+	_, err = gateway.Capture(ctx, p.ID, 1000000)
+	assert.NoError(t, err)
+}
+
+func TestVerify(t *testing.T) {
+	body := []byte(`{"id":"evt_1"}`)
+	signature := payment.Sign(secret, body)
+
+	assert.NoError(t, payment.Verify(secret, body, signature))
+	assert.ErrorIs(t, payment.Verify("other", body, signature), service.ErrInvalidSignature)
+	assert.ErrorIs(t, payment.Verify(secret, []byte(`{"id":"evt_2"}`), signature), service.ErrInvalidSignature)
+	assert.ErrorIs(t, payment.Verify(secret, body, ""), service.ErrInvalidSignature)
+	assert.ErrorIs(t, payment.Verify("", body, payment.Sign("", body)), service.ErrInvalidSignature)
+}
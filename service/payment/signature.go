@@ -0,0 +1,33 @@
+package payment
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+
+	"github.com/elkoshar/bookcabin/service"
+)
+
+// SignatureHeader carries the HMAC of a webhook body
+const SignatureHeader = "X-Payment-Signature"
+
+const signaturePrefix = "sha256="
+
+// Sign returns the signature of a webhook body in the form sha256=<hex hmac>
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify checks a webhook signature in constant time
+func Verify(secret string, body []byte, signature string) error {
+	if secret == "" || !strings.HasPrefix(signature, signaturePrefix) {
+		return service.ErrInvalidSignature
+	}
+	if !hmac.Equal([]byte(Sign(secret, body)), []byte(signature)) {
+		return service.ErrInvalidSignature
+	}
+	return nil
+}
@@ -0,0 +1,27 @@
+package payment
+
+import (
+	"context"
+
+	"github.com/elkoshar/bookcabin/service"
+)
+
+// Unavailable stands in for the payment gateway where the fake one must not run, every operation
+// fails with service.ErrPaymentGatewayUnavailable
+type Unavailable struct{}
+
+func (Unavailable) Authorize(ctx context.Context, req service.AuthorizeRequest) (service.Payment, error) {
+	return service.Payment{}, service.ErrPaymentGatewayUnavailable
+}
+
+func (Unavailable) Capture(ctx context.Context, id string, amount float64) (service.Payment, error) {
+	return service.Payment{}, service.ErrPaymentGatewayUnavailable
+}
+
+func (Unavailable) Void(ctx context.Context, id string) (service.Payment, error) {
+	return service.Payment{}, service.ErrPaymentGatewayUnavailable
+}
+
+func (Unavailable) Refund(ctx context.Context, id string, amount float64) (service.Payment, error) {
+	return service.Payment{}, service.ErrPaymentGatewayUnavailable
+}
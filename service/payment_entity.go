@@ -0,0 +1,77 @@
+package service
+
+import (
+	"errors"
+	"time"
+)
+
+// Payment states at the gateway
+const (
+	PaymentStatusRequiresAction = "requires_action"
+	PaymentStatusAuthorized     = "authorized"
+	PaymentStatusDeclined       = "declined"
+	PaymentStatusCaptured       = "captured"
+	PaymentStatusVoided         = "voided"
+	PaymentStatusRefunded       = "refunded"
+)
+
+// Webhook notification types sent by the gateway
+const (
+	PaymentEventAuthorized = "payment.authorized"
+	PaymentEventDeclined   = "payment.declined"
+)
+
+var (
+	ErrPaymentDeclined     = errors.New("payment was declined")
+	ErrPaymentNotFound     = errors.New("payment not found")
+	ErrInvalidPaymentState = errors.New("payment is not in a state that allows this operation")
+	ErrPaymentPending      = errors.New("a payment is already awaiting authentication")
+	ErrInvalidSignature    = errors.New("invalid webhook signature")
+	ErrInvalidPaymentEvent = errors.New("invalid payment notification payload")
+
+	ErrPaymentGatewayUnavailable = errors.New("no payment gateway is configured")
+)
+
+// PaymentRequest carries the card a booking is paid with
+type PaymentRequest struct {
+	CardNumber  string `json:"card_number" validate:"required,numeric,min=12,max=19"`
+	HolderName  string `json:"holder_name" validate:"required"`
+	ExpiryMonth int    `json:"expiry_month" validate:"required,min=1,max=12"`
+	ExpiryYear  int    `json:"expiry_year" validate:"required,min=2000"`
+	CVV         string `json:"cvv" validate:"required,numeric,min=3,max=4"`
+}
+
+// PaymentChallengeRequest is the cardholder's answer to a 3DS challenge
+type PaymentChallengeRequest struct {
+	Approved bool `json:"approved"`
+}
+
+// AuthorizeRequest asks the gateway to reserve an amount on a card
+type AuthorizeRequest struct {
+	Reference string
+	Amount    PriceInfo
+	Card      PaymentRequest
+}
+
+// Payment is the gateway's record of a card payment
+type Payment struct {
+	ID             string    `json:"id"`
+	Reference      string    `json:"reference"`
+	Status         string    `json:"status"`
+	Amount         PriceInfo `json:"amount"`
+	CapturedAmount PriceInfo `json:"captured_amount"`
+	RefundedAmount PriceInfo `json:"refunded_amount"`
+	CardLast4      string    `json:"card_last4"`
+	ChallengeURL   string    `json:"challenge_url,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// PaymentEvent is an asynchronous notification from the gateway
+type PaymentEvent struct {
+	ID        string    `json:"id"`
+	Type      string    `json:"type"`
+	PaymentID string    `json:"payment_id"`
+	Reference string    `json:"reference"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,32 @@
+package service
+
+import "errors"
+
+// Outcomes of revalidating an offer against its provider
+const (
+	RepriceStatusUnchanged    = "unchanged"
+	RepriceStatusPriceChanged = "price_changed"
+	RepriceStatusSoldOut      = "sold_out"
+)
+
+var (
+	ErrOfferSoldOut = errors.New("offer is sold out")
+	ErrPriceChanged = errors.New("offer price changed beyond tolerance")
+)
+
+type RepriceRequest struct {
+	ID         string `json:"id" validate:"required"`
+	Passengers int    `json:"passengers,omitempty" validate:"omitempty,min=1,max=9"`
+
+	// ExpectedAmount is the per-passenger price the client saw, the last quoted price is used when empty
+	ExpectedAmount float64 `json:"expected_amount,omitempty" validate:"omitempty,gt=0"`
+}
+
+type RepriceResult struct {
+	ID        string         `json:"id"`
+	Status    string         `json:"status"`
+	OldAmount float64        `json:"old_amount"`
+	NewAmount float64        `json:"new_amount,omitempty"`
+	Currency  string         `json:"currency,omitempty"`
+	Flight    *UnifiedFlight `json:"flight,omitempty"`
+}
@@ -0,0 +1,26 @@
+package service
+
+// Types of the events sent by an incremental search
+const (
+	SearchEventProviderResults = "provider_results"
+	SearchEventProviderError   = "provider_error"
+	SearchEventSummary         = "summary"
+	SearchEventError           = "error"
+)
+
+// SearchEvent is one step of an incremental search: the results or failure of one provider,
+// then a final summary with the ranked response
+type SearchEvent struct {
+	Type string `json:"type"`
+
+	// Leg is the index of the itinerary leg the provider was queried for, 0 for the outbound flight
+	Leg      int             `json:"leg"`
+	Provider string          `json:"provider,omitempty"`
+	Flights  []UnifiedFlight `json:"flights,omitempty"`
+	Error    string          `json:"error,omitempty"`
+
+	// Err is the error an error event describes, kept so consumers can still match it with errors.Is
+	Err error `json:"-"`
+
+	Summary *SearchResponse `json:"summary,omitempty"`
+}
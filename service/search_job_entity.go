@@ -0,0 +1,61 @@
+package service
+
+import (
+	"errors"
+	"time"
+)
+
+// Lifecycle of an asynchronous search job
+const (
+	SearchJobQueued    = "queued"
+	SearchJobRunning   = "running"
+	SearchJobPartial   = "partial"
+	SearchJobComplete  = "complete"
+	SearchJobFailed    = "failed"
+	SearchJobCancelled = "cancelled"
+)
+
+// Outcome of one provider within a search job
+const (
+	ProviderProgressComplete = "complete"
+	ProviderProgressFailed   = "failed"
+)
+
+var (
+	ErrSearchJobNotFound = errors.New("search job not found")
+	ErrSearchJobFinished = errors.New("search job has already finished")
+	ErrSearchQueueFull   = errors.New("too many searches are waiting, try again later")
+)
+
+// SearchJob is a search running in the background. Flights holds the results gathered so far
+// until the job completes, then Result holds the final ranked response.
+type SearchJob struct {
+	ID        string             `json:"id"`
+	Status    string             `json:"status"`
+	Criteria  SearchCriteria     `json:"search_criteria"`
+	Providers []ProviderProgress `json:"providers"`
+	Flights   []UnifiedFlight    `json:"flights,omitempty"`
+	Result    *SearchResponse    `json:"result,omitempty"`
+	Error     string             `json:"error,omitempty"`
+	CreatedAt time.Time          `json:"created_at"`
+	UpdatedAt time.Time          `json:"updated_at"`
+	ExpiresAt time.Time          `json:"expires_at"`
+}
+
+// ProviderProgress reports a provider that has answered a search job
+type ProviderProgress struct {
+	Provider string `json:"provider"`
+	Leg      int    `json:"leg"`
+	Status   string `json:"status"`
+	Results  int    `json:"results"`
+	Error    string `json:"error,omitempty"`
+}
+
+// Finished reports whether the job has reached a final status
+func (j SearchJob) Finished() bool {
+	switch j.Status {
+	case SearchJobComplete, SearchJobFailed, SearchJobCancelled:
+		return true
+	}
+	return false
+}
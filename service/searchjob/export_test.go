@@ -0,0 +1,4 @@
+package searchjob
+
+// NewRunnerWithClock lets tests control the clock jobs expire against and how often they are evicted
+var NewRunnerWithClock = newRunner
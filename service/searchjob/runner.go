@@ -0,0 +1,277 @@
+package searchjob
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/elkoshar/bookcabin/api"
+	"github.com/elkoshar/bookcabin/pkg/helpers"
+	"github.com/elkoshar/bookcabin/service"
+)
+
+// evictInterval is how often expired jobs are dropped in the background, so jobs nobody reads again
+// do not stay in memory
+const evictInterval = time.Minute
+
+type job struct {
+	state  service.SearchJob
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// Runner runs searches on a bounded pool of workers and keeps their progress until they expire
+type Runner struct {
+	aggregator api.FlightAggregator
+	ttl        time.Duration
+
+	// now is the clock jobs expire against
+	now func() time.Time
+
+	mu    sync.Mutex
+	jobs  map[string]*job
+	queue chan *job
+
+	ctx  context.Context
+	stop context.CancelFunc
+	wg   sync.WaitGroup
+}
+
+// NewRunner starts workers that run the queued searches. Jobs are dropped ttl after they were
+// submitted, and a job still running by then is cancelled.
+func NewRunner(aggregator api.FlightAggregator, workers, queueSize int, ttl time.Duration) *Runner {
+	return newRunner(aggregator, workers, queueSize, ttl, time.Now, evictInterval)
+}
+
+func newRunner(aggregator api.FlightAggregator, workers, queueSize int, ttl time.Duration, now func() time.Time, evictEvery time.Duration) *Runner {
+	ctx, stop := context.WithCancel(context.Background())
+	r := &Runner{
+		aggregator: aggregator,
+		ttl:        ttl,
+		now:        now,
+		jobs:       make(map[string]*job),
+		queue:      make(chan *job, queueSize),
+		ctx:        ctx,
+		stop:       stop,
+	}
+
+	for i := 0; i < workers; i++ {
+		r.wg.Add(1)
+		go r.work()
+	}
+
+	r.wg.Add(1)
+	go r.evict(evictEvery)
+	return r
+}
+
+// Close cancels the running jobs and waits for the workers and the eviction schedule to exit
+func (r *Runner) Close() {
+	r.stop()
+	r.wg.Wait()
+}
+
+func (r *Runner) Submit(ctx context.Context, criteria service.SearchCriteria) (service.SearchJob, error) {
+	now := r.now()
+	jobCtx, cancel := context.WithTimeout(r.ctx, r.ttl)
+
+	j := &job{
+		state: service.SearchJob{
+			ID:        helpers.GenerateSearchID(),
+			Status:    service.SearchJobQueued,
+			Criteria:  criteria,
+			Providers: []service.ProviderProgress{},
+			CreatedAt: now,
+			UpdatedAt: now,
+			ExpiresAt: now.Add(r.ttl),
+		},
+		ctx:    jobCtx,
+		cancel: cancel,
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.evictExpired(now)
+
+	select {
+	case r.queue <- j:
+	default:
+		cancel()
+		return service.SearchJob{}, service.ErrSearchQueueFull
+	}
+
+	r.jobs[j.state.ID] = j
+	return snapshot(j), nil
+}
+
+func (r *Runner) Get(ctx context.Context, id string) (service.SearchJob, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.evictExpired(r.now())
+
+	j, ok := r.jobs[id]
+	if !ok {
+		return service.SearchJob{}, service.ErrSearchJobNotFound
+	}
+	return snapshot(j), nil
+}
+
+// Cancel stops a queued or running job, which keeps its partial results until it expires
+func (r *Runner) Cancel(ctx context.Context, id string) (service.SearchJob, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.now()
+	r.evictExpired(now)
+
+	j, ok := r.jobs[id]
+	if !ok {
+		return service.SearchJob{}, service.ErrSearchJobNotFound
+	}
+	if j.state.Finished() {
+		return snapshot(j), service.ErrSearchJobFinished
+	}
+
+	j.state.Status = service.SearchJobCancelled
+	j.state.UpdatedAt = now
+	j.cancel()
+
+	return snapshot(j), nil
+}
+
+func (r *Runner) work() {
+	defer r.wg.Done()
+
+	for {
+		select {
+		case <-r.ctx.Done():
+			return
+		case j := <-r.queue:
+			r.run(j)
+		}
+	}
+}
+
+func (r *Runner) run(j *job) {
+	defer j.cancel()
+
+	// the job was cancelled or expired while it waited in the queue
+	if j.ctx.Err() != nil {
+		return
+	}
+
+	r.update(j, func(state *service.SearchJob) {
+		state.Status = service.SearchJobRunning
+	})
+
+	events, err := r.aggregator.SearchStream(j.ctx, j.state.Criteria)
+	if err != nil {
+		r.update(j, func(state *service.SearchJob) {
+			state.Status = service.SearchJobFailed
+			state.Error = err.Error()
+		})
+		return
+	}
+
+	for event := range events {
+		r.update(j, func(state *service.SearchJob) {
+			apply(state, event)
+		})
+	}
+
+	// the stream ends early when the job expires or the runner is closed
+	r.update(j, func(state *service.SearchJob) {
+		if !state.Finished() {
+			state.Status = service.SearchJobFailed
+			state.Error = fmt.Sprintf("search stopped: %v", j.ctx.Err())
+		}
+	})
+}
+
+// update changes the state of a job unless it was cancelled meanwhile
+func (r *Runner) update(j *job, fn func(state *service.SearchJob)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if j.state.Status == service.SearchJobCancelled {
+		return
+	}
+	fn(&j.state)
+	j.state.UpdatedAt = r.now()
+}
+
+// apply records one search event on the job state
+func apply(state *service.SearchJob, event service.SearchEvent) {
+	switch event.Type {
+	case service.SearchEventProviderResults:
+		state.Status = service.SearchJobPartial
+		state.Flights = append(state.Flights, event.Flights...)
+		state.Providers = append(state.Providers, service.ProviderProgress{
+			Provider: event.Provider,
+			Leg:      event.Leg,
+			Status:   service.ProviderProgressComplete,
+			Results:  len(event.Flights),
+		})
+
+	case service.SearchEventProviderError:
+		state.Status = service.SearchJobPartial
+		state.Providers = append(state.Providers, service.ProviderProgress{
+			Provider: event.Provider,
+			Leg:      event.Leg,
+			Status:   service.ProviderProgressFailed,
+			Error:    event.Error,
+		})
+
+	case service.SearchEventSummary:
+		state.Status = service.SearchJobComplete
+		state.Flights = nil
+		state.Result = event.Summary
+
+	case service.SearchEventError:
+		state.Status = service.SearchJobFailed
+		state.Error = event.Error
+	}
+}
+
+// evict drops expired jobs every interval until the runner is closed
+func (r *Runner) evict(interval time.Duration) {
+	defer r.wg.Done()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.ctx.Done():
+			return
+		case <-ticker.C:
+			r.mu.Lock()
+			r.evictExpired(r.now())
+			r.mu.Unlock()
+		}
+	}
+}
+
+// evictExpired drops the jobs past their TTL and stops those still running
+func (r *Runner) evictExpired(now time.Time) {
+	for id, j := range r.jobs {
+		if now.After(j.state.ExpiresAt) {
+			j.cancel()
+			delete(r.jobs, id)
+			slog.Info(fmt.Sprintf("[SearchJob] Search job %s expired", id))
+		}
+	}
+}
+
+// snapshot copies the state of a job so callers can read it while the job keeps running
+func snapshot(j *job) service.SearchJob {
+	state := j.state
+	state.Providers = slices.Clone(state.Providers)
+	state.Flights = slices.Clone(state.Flights)
+	return state
+}
@@ -0,0 +1,228 @@
+package searchjob_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elkoshar/bookcabin/api"
+	"github.com/elkoshar/bookcabin/service"
+	"github.com/elkoshar/bookcabin/service/searchjob"
+)
+
+// streamAggregator serves SearchStream from a channel the test writes to
+type streamAggregator struct {
+	api.FlightAggregator
+	events chan service.SearchEvent
+	err    error
+	ctx    chan context.Context
+}
+
+func newStreamAggregator() *streamAggregator {
+	return &streamAggregator{events: make(chan service.SearchEvent), ctx: make(chan context.Context, 1)}
+}
+
+func (a *streamAggregator) SearchStream(ctx context.Context, criteria service.SearchCriteria) (<-chan service.SearchEvent, error) {
+	a.ctx <- ctx
+	if a.err != nil {
+		return nil, a.err
+	}
+
+	out := make(chan service.SearchEvent)
+	go func() {
+		defer close(out)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case event, ok := <-a.events:
+				if !ok {
+					return
+				}
+				out <- event
+			}
+		}
+	}()
+	return out, nil
+}
+
+var criteria = service.SearchCriteria{Origin: "CGK", Destination: "DPS", DepartureDate: "2025-12-15", Passengers: 1, CabinClass: "economy"}
+
+func waitForStatus(t *testing.T, runner *searchjob.Runner, id, status string) service.SearchJob {
+	t.Helper()
+
+	var job service.SearchJob
+	require.Eventually(t, func() bool {
+		var err error
+		job, err = runner.Get(context.Background(), id)
+		return err == nil && job.Status == status
+	}, time.Second, 5*time.Millisecond)
+	return job
+}
+
+func TestRunner_Progress(t *testing.T) {
+	agg := newStreamAggregator()
+	runner := searchjob.NewRunner(agg, 1, 10, time.Minute)
+	defer runner.Close()
+
+	job, err := runner.Submit(context.Background(), criteria)
+	require.NoError(t, err)
+	assert.Equal(t, service.SearchJobQueued, job.Status)
+	assert.NotEmpty(t, job.ID)
+
+	waitForStatus(t, runner, job.ID, service.SearchJobRunning)
+
+	agg.events <- service.SearchEvent{Type: service.SearchEventProviderResults, Provider: "Lion Air", Flights: []service.UnifiedFlight{{ID: "JT650"}, {ID: "JT652"}}}
+	agg.events <- service.SearchEvent{Type: service.SearchEventProviderError, Provider: "Batik Air", Error: "timeout"}
+
+	partial := waitForStatus(t, runner, job.ID, service.SearchJobPartial)
+	require.Eventually(t, func() bool {
+		partial, _ = runner.Get(context.Background(), job.ID)
+		return len(partial.Providers) == 2
+	}, time.Second, 5*time.Millisecond)
+	assert.Len(t, partial.Flights, 2)
+	assert.Equal(t, service.ProviderProgress{Provider: "Lion Air", Status: service.ProviderProgressComplete, Results: 2}, partial.Providers[0])
+	assert.Equal(t, service.ProviderProgressFailed, partial.Providers[1].Status)
+	assert.Equal(t, "timeout", partial.Providers[1].Error)
+
+	summary := service.SearchResponse{SearchID: "search-1", Flights: []service.UnifiedFlight{{ID: "JT650"}, {ID: "JT652"}}}
+	agg.events <- service.SearchEvent{Type: service.SearchEventSummary, Summary: &summary}
+	close(agg.events)
+
+	complete := waitForStatus(t, runner, job.ID, service.SearchJobComplete)
+	assert.Empty(t, complete.Flights)
+	assert.Equal(t, "search-1", complete.Result.SearchID)
+	assert.Len(t, complete.Providers, 2)
+}
+
+func TestRunner_SearchError(t *testing.T) {
+	agg := newStreamAggregator()
+	agg.err = errors.New("origin and destination cannot be the same")
+	runner := searchjob.NewRunner(agg, 1, 10, time.Minute)
+	defer runner.Close()
+
+	job, err := runner.Submit(context.Background(), criteria)
+	require.NoError(t, err)
+
+	failed := waitForStatus(t, runner, job.ID, service.SearchJobFailed)
+	assert.Equal(t, "origin and destination cannot be the same", failed.Error)
+}
+
+func TestRunner_Cancel(t *testing.T) {
+	agg := newStreamAggregator()
+	runner := searchjob.NewRunner(agg, 1, 10, time.Minute)
+	defer runner.Close()
+
+	job, err := runner.Submit(context.Background(), criteria)
+	require.NoError(t, err)
+	searchCtx := <-agg.ctx
+
+	cancelled, err := runner.Cancel(context.Background(), job.ID)
+	require.NoError(t, err)
+	assert.Equal(t, service.SearchJobCancelled, cancelled.Status)
+
+	select {
+	case <-searchCtx.Done():
+	case <-time.After(time.Second):
+		t.Fatal("search was not cancelled")
+	}
+
+	_, err = runner.Cancel(context.Background(), job.ID)
+	assert.ErrorIs(t, err, service.ErrSearchJobFinished)
+
+	job, err = runner.Get(context.Background(), job.ID)
+	require.NoError(t, err)
+	assert.Equal(t, service.SearchJobCancelled, job.Status)
+}
+
+func TestRunner_QueueFull(t *testing.T) {
+	// without workers nothing leaves the queue
+	runner := searchjob.NewRunner(newStreamAggregator(), 0, 1, time.Minute)
+	defer runner.Close()
+
+	_, err := runner.Submit(context.Background(), criteria)
+	require.NoError(t, err)
+
+	_, err = runner.Submit(context.Background(), criteria)
+	assert.ErrorIs(t, err, service.ErrSearchQueueFull)
+}
+
+func TestRunner_Expiry(t *testing.T) {
+	agg := newStreamAggregator()
+	runner := searchjob.NewRunner(agg, 1, 10, 50*time.Millisecond)
+	defer runner.Close()
+
+	job, err := runner.Submit(context.Background(), criteria)
+	require.NoError(t, err)
+	searchCtx := <-agg.ctx
+
+	select {
+	case <-searchCtx.Done():
+	case <-time.After(time.Second):
+		t.Fatal("expired search was not stopped")
+	}
+
+	time.Sleep(10 * time.Millisecond)
+	_, err = runner.Get(context.Background(), job.ID)
+	assert.ErrorIs(t, err, service.ErrSearchJobNotFound)
+}
+
+// clock is a time source the test moves forward by hand
+type clock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *clock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *clock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func TestRunner_EvictsInBackground(t *testing.T) {
+	agg := newStreamAggregator()
+	clk := &clock{now: time.Now()}
+	runner := searchjob.NewRunnerWithClock(agg, 1, 10, time.Hour, clk.Now, 5*time.Millisecond)
+	defer runner.Close()
+
+	job, err := runner.Submit(context.Background(), criteria)
+	require.NoError(t, err)
+	searchCtx := <-agg.ctx
+
+	// ticks before the TTL leave the job alone
+	time.Sleep(20 * time.Millisecond)
+	assert.NoError(t, searchCtx.Err())
+
+	// nothing reads the job again, the ticker alone has to drop it
+	clk.Advance(2 * time.Hour)
+	select {
+	case <-searchCtx.Done():
+	case <-time.After(time.Second):
+		t.Fatal("expired job was not evicted in the background")
+	}
+
+	_, err = runner.Get(context.Background(), job.ID)
+	assert.ErrorIs(t, err, service.ErrSearchJobNotFound)
+}
+
+func TestRunner_NotFound(t *testing.T) {
+	runner := searchjob.NewRunner(newStreamAggregator(), 1, 10, time.Minute)
+	defer runner.Close()
+
+	_, err := runner.Get(context.Background(), "missing")
+	assert.ErrorIs(t, err, service.ErrSearchJobNotFound)
+
+	_, err = runner.Cancel(context.Background(), "missing")
+	assert.ErrorIs(t, err, service.ErrSearchJobNotFound)
+}
@@ -0,0 +1,61 @@
+package service
+
+import "errors"
+
+// Seat characteristics shown on a seat map
+const (
+	SeatWindow       = "window"
+	SeatAisle        = "aisle"
+	SeatMiddle       = "middle"
+	SeatExitRow      = "exit_row"
+	SeatExtraLegroom = "extra_legroom"
+	SeatFrontOfCabin = "front_of_cabin"
+	SeatNoRecline    = "no_recline"
+)
+
+var (
+	ErrSeatMapUnavailable   = errors.New("seat map is not available for this offer")
+	ErrSeatUnavailable      = errors.New("seat is not available")
+	ErrInvalidSeatSelection = errors.New("invalid seat selection")
+)
+
+// SeatMap is the seat layout of the cabin sold by an offer
+type SeatMap struct {
+	OfferID  string      `json:"offer_id"`
+	Aircraft string      `json:"aircraft"`
+	Cabins   []SeatCabin `json:"cabins"`
+}
+
+type SeatCabin struct {
+	CabinClass string `json:"cabin_class"`
+
+	// Layout lists the seat letters per block, "ABC DEF" is a 3-3 cabin
+	Layout   string    `json:"layout"`
+	FirstRow int       `json:"first_row"`
+	LastRow  int       `json:"last_row"`
+	Rows     []SeatRow `json:"rows"`
+}
+
+type SeatRow struct {
+	Number int    `json:"number"`
+	Seats  []Seat `json:"seats"`
+}
+
+type Seat struct {
+	Number          string    `json:"number"`
+	Characteristics []string  `json:"characteristics"`
+	Price           PriceInfo `json:"price"`
+	Available       bool      `json:"available"`
+}
+
+// SeatSelectionRequest assigns seats to the passengers of a held booking
+type SeatSelectionRequest struct {
+	Seats []SeatAssignment `json:"seats" validate:"required,min=1,max=9,dive"`
+}
+
+type SeatAssignment struct {
+	// PassengerIndex is the position of the passenger in the booking, starting at 0
+	PassengerIndex int       `json:"passenger_index" validate:"min=0,max=8"`
+	SeatNumber     string    `json:"seat_number" validate:"required"`
+	Price          PriceInfo `json:"price"`
+}
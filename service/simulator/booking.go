@@ -0,0 +1,150 @@
+package simulator
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/elkoshar/bookcabin/pkg/helpers"
+	"github.com/elkoshar/bookcabin/service"
+)
+
+// BookingBackend is an in-memory stand-in for an airline reservation system so the
+// booking flow works offline. It keeps holds per record locator and tracks held seats
+// per offer so availability shrinks as seats are held.
+type BookingBackend struct {
+	mu        sync.Mutex
+	holds     map[string]*service.ProviderHold
+	heldSeats map[string]int
+
+	// holdFlights keeps the flight of every hold so its seat map can be rebuilt
+	holdFlights map[string]service.UnifiedFlight
+
+	// seatOwners maps an offer's reserved seat numbers to the locator holding them
+	seatOwners map[string]map[string]string
+}
+
+func NewBookingBackend() *BookingBackend {
+	return &BookingBackend{
+		holds:       make(map[string]*service.ProviderHold),
+		heldSeats:   make(map[string]int),
+		holdFlights: make(map[string]service.UnifiedFlight),
+		seatOwners:  make(map[string]map[string]string),
+	}
+}
+
+func (b *BookingBackend) CreateHold(ctx context.Context, req service.HoldRequest) (service.ProviderHold, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.releaseExpired()
+
+	// the flight comes from a fresh search, whose availability already excludes held seats
+	seats := len(req.Passengers)
+	if req.Flight.AvailableSeats < seats {
+		return service.ProviderHold{}, service.ErrInsufficientSeats
+	}
+
+	locator := helpers.GenerateLocator(6)
+	for b.holds[locator] != nil {
+		locator = helpers.GenerateLocator(6)
+	}
+
+	hold := &service.ProviderHold{
+		Locator:    locator,
+		OfferID:    req.Flight.ID,
+		Passengers: seats,
+		Status:     service.HoldStatusActive,
+		ExpiresAt:  req.ExpiresAt,
+	}
+	b.holds[locator] = hold
+	b.holdFlights[locator] = req.Flight
+	b.heldSeats[req.Flight.ID] += seats
+
+	return *hold, nil
+}
+
+func (b *BookingBackend) RetrieveHold(ctx context.Context, locator string) (service.ProviderHold, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.releaseExpired()
+
+	hold, ok := b.holds[locator]
+	if !ok {
+		return service.ProviderHold{}, service.ErrHoldNotFound
+	}
+	return *hold, nil
+}
+
+func (b *BookingBackend) CancelHold(ctx context.Context, locator string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	hold, ok := b.holds[locator]
+	if !ok {
+		return service.ErrHoldNotFound
+	}
+	b.release(hold)
+	return nil
+}
+
+// ConfirmHold sells the seats of an active hold, they stay taken after the hold would have expired
+func (b *BookingBackend) ConfirmHold(ctx context.Context, locator string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.releaseExpired()
+
+	hold, ok := b.holds[locator]
+	if !ok || hold.Status != service.HoldStatusActive {
+		return service.ErrHoldNotFound
+	}
+	hold.Status = service.HoldStatusConfirmed
+	return nil
+}
+
+// CancelConfirmed cancels a confirmed booking and returns its seats to the inventory
+func (b *BookingBackend) CancelConfirmed(ctx context.Context, locator string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	hold, ok := b.holds[locator]
+	if !ok || hold.Status != service.HoldStatusConfirmed {
+		return service.ErrHoldNotFound
+	}
+	b.free(hold)
+	return nil
+}
+
+// HeldSeats returns how many seats of an offer are currently held or sold
+func (b *BookingBackend) HeldSeats(offerID string) int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.releaseExpired()
+	return b.heldSeats[offerID]
+}
+
+func (b *BookingBackend) releaseExpired() {
+	now := time.Now()
+	for _, hold := range b.holds {
+		if hold.Status == service.HoldStatusActive && now.After(hold.ExpiresAt) {
+			b.release(hold)
+		}
+	}
+}
+
+// release gives back the seats of an active hold, confirmed holds are only cancelled with CancelConfirmed
+func (b *BookingBackend) release(hold *service.ProviderHold) {
+	if hold.Status != service.HoldStatusActive {
+		return
+	}
+	b.free(hold)
+}
+
+func (b *BookingBackend) free(hold *service.ProviderHold) {
+	hold.Status = service.HoldStatusReleased
+	b.heldSeats[hold.OfferID] -= hold.Passengers
+	b.releaseSeats(hold)
+}
@@ -0,0 +1,99 @@
+package simulator_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/elkoshar/bookcabin/service"
+	"github.com/elkoshar/bookcabin/service/simulator"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBookingBackend(t *testing.T) {
+	backend := simulator.NewBookingBackend()
+	ctx := context.Background()
+
+	req := service.HoldRequest{
+		Flight:     service.UnifiedFlight{ID: "offer-1", AvailableSeats: 3},
+		Passengers: make([]service.Passenger, 2),
+		ExpiresAt:  time.Now().Add(time.Minute),
+	}
+
+	hold, err := backend.CreateHold(ctx, req)
+	assert.NoError(t, err)
+	assert.Equal(t, service.HoldStatusActive, hold.Status)
+	assert.Equal(t, 2, backend.HeldSeats("offer-1"))
+
+	retrieved, err := backend.RetrieveHold(ctx, hold.Locator)
+	assert.NoError(t, err)
+	assert.Equal(t, hold, retrieved)
+
+	assert.NoError(t, backend.CancelHold(ctx, hold.Locator))
+	assert.Equal(t, 0, backend.HeldSeats("offer-1"))
+
+	retrieved, err = backend.RetrieveHold(ctx, hold.Locator)
+	assert.NoError(t, err)
+	assert.Equal(t, service.HoldStatusReleased, retrieved.Status)
+
+	_, err = backend.RetrieveHold(ctx, "MISSING")
+	assert.ErrorIs(t, err, service.ErrHoldNotFound)
+
+	req.Flight.AvailableSeats = 1
+	_, err = backend.CreateHold(ctx, req)
+	assert.ErrorIs(t, err, service.ErrInsufficientSeats)
+}
+
+func TestBookingBackend_ExpiredHoldReleasesSeats(t *testing.T) {
+	backend := simulator.NewBookingBackend()
+
+	_, err := backend.CreateHold(context.Background(), service.HoldRequest{
+		Flight:     service.UnifiedFlight{ID: "offer-1", AvailableSeats: 3},
+		Passengers: make([]service.Passenger, 1),
+		ExpiresAt:  time.Now().Add(-time.Second),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 0, backend.HeldSeats("offer-1"))
+}
+
+func TestBookingBackend_ConfirmedHoldKeepsSeats(t *testing.T) {
+	backend := simulator.NewBookingBackend()
+	ctx := context.Background()
+
+	hold, err := backend.CreateHold(ctx, service.HoldRequest{
+		Flight:     service.UnifiedFlight{ID: "offer-1", AvailableSeats: 3},
+		Passengers: make([]service.Passenger, 2),
+		ExpiresAt:  time.Now().Add(20 * time.Millisecond),
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, backend.ConfirmHold(ctx, hold.Locator))
+	assert.ErrorIs(t, backend.ConfirmHold(ctx, hold.Locator), service.ErrHoldNotFound)
+
+	// past the hold TTL the sold seats stay taken
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, 2, backend.HeldSeats("offer-1"))
+	assert.NoError(t, backend.CancelHold(ctx, hold.Locator))
+	assert.Equal(t, 2, backend.HeldSeats("offer-1"))
+
+	retrieved, err := backend.RetrieveHold(ctx, hold.Locator)
+	assert.NoError(t, err)
+	assert.Equal(t, service.HoldStatusConfirmed, retrieved.Status)
+
+	assert.NoError(t, backend.CancelConfirmed(ctx, hold.Locator))
+	assert.Equal(t, 0, backend.HeldSeats("offer-1"))
+	assert.ErrorIs(t, backend.CancelConfirmed(ctx, hold.Locator), service.ErrHoldNotFound)
+}
+
+func TestBookingBackend_ConfirmExpiredHold(t *testing.T) {
+	backend := simulator.NewBookingBackend()
+	ctx := context.Background()
+
+	hold, err := backend.CreateHold(ctx, service.HoldRequest{
+		Flight:     service.UnifiedFlight{ID: "offer-1", AvailableSeats: 3},
+		Passengers: make([]service.Passenger, 1),
+		ExpiresAt:  time.Now().Add(-time.Second),
+	})
+	assert.NoError(t, err)
+	assert.ErrorIs(t, backend.ConfirmHold(ctx, hold.Locator), service.ErrHoldNotFound)
+	assert.ErrorIs(t, backend.ConfirmHold(ctx, "MISSING"), service.ErrHoldNotFound)
+}
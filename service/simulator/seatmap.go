@@ -0,0 +1,169 @@
+package simulator
+
+import (
+	"fmt"
+	"hash/fnv"
+	"slices"
+	"strings"
+
+	"github.com/elkoshar/bookcabin/pkg/helpers"
+	"github.com/elkoshar/bookcabin/service"
+)
+
+// occupancyPercent is the share of seats already sold to other travellers on a generated seat map
+const occupancyPercent = 40
+
+// Economy seat prices in IDR, business seats are included in the fare
+const (
+	priceExtraLegroom = 200000
+	priceFrontOfCabin = 150000
+	priceWindowAisle  = 80000
+	priceMiddle       = 50000
+)
+
+type cabinLayout struct {
+	class    string
+	blocks   []string // seat letters between aisles
+	firstRow int
+	lastRow  int
+	exitRows []int
+}
+
+// aircraftLayouts holds the cabin configurations of the aircraft types flown in the mock data
+var aircraftLayouts = map[string][]cabinLayout{
+	"Boeing 737-800": {
+		{class: "business", blocks: []string{"AC", "DF"}, firstRow: 1, lastRow: 3},
+		{class: "economy", blocks: []string{"ABC", "DEF"}, firstRow: 6, lastRow: 32, exitRows: []int{15, 16}},
+	},
+	"Boeing 737-900ER": {
+		{class: "business", blocks: []string{"AC", "DF"}, firstRow: 1, lastRow: 3},
+		{class: "economy", blocks: []string{"ABC", "DEF"}, firstRow: 6, lastRow: 38, exitRows: []int{20, 21}},
+	},
+	"Airbus A320": {
+		{class: "business", blocks: []string{"AC", "DF"}, firstRow: 1, lastRow: 3},
+		{class: "economy", blocks: []string{"ABC", "DEF"}, firstRow: 6, lastRow: 31, exitRows: []int{12, 13}},
+	},
+	"Airbus A330-300": {
+		{class: "business", blocks: []string{"AC", "DG", "HK"}, firstRow: 1, lastRow: 6},
+		{class: "economy", blocks: []string{"AC", "DEFG", "HK"}, firstRow: 11, lastRow: 45, exitRows: []int{30}},
+	},
+}
+
+// aircraftType maps the aircraft names used by the providers onto a known layout
+func aircraftType(aircraft string) (string, bool) {
+	name := strings.ToLower(aircraft)
+	switch {
+	case strings.Contains(name, "a330"):
+		return "Airbus A330-300", true
+	case strings.Contains(name, "a320"):
+		return "Airbus A320", true
+	case strings.Contains(name, "737-900"):
+		return "Boeing 737-900ER", true
+	case strings.Contains(name, "737"):
+		return "Boeing 737-800", true
+	}
+	return "", false
+}
+
+// GenerateSeatMap builds the seat map of the cabin a flight is sold in. The same offer always
+// yields the same layout and occupancy; seats in taken are additionally shown as unavailable.
+func GenerateSeatMap(flight service.UnifiedFlight, taken map[string]bool) (service.SeatMap, error) {
+	aircraft, ok := aircraftType(flight.Aircraft)
+	if !ok {
+		return service.SeatMap{}, service.ErrSeatMapUnavailable
+	}
+
+	cabinClass := strings.ToLower(flight.CabinClass)
+	if cabinClass == "first" {
+		cabinClass = "business"
+	}
+
+	for _, layout := range aircraftLayouts[aircraft] {
+		if layout.class != cabinClass {
+			continue
+		}
+		return service.SeatMap{
+			OfferID:  flight.ID,
+			Aircraft: aircraft,
+			Cabins:   []service.SeatCabin{buildCabin(flight.ID, layout, taken)},
+		}, nil
+	}
+	return service.SeatMap{}, service.ErrSeatMapUnavailable
+}
+
+func buildCabin(offerID string, layout cabinLayout, taken map[string]bool) service.SeatCabin {
+	cabin := service.SeatCabin{
+		CabinClass: layout.class,
+		Layout:     strings.Join(layout.blocks, " "),
+		FirstRow:   layout.firstRow,
+		LastRow:    layout.lastRow,
+	}
+
+	for row := layout.firstRow; row <= layout.lastRow; row++ {
+		seatRow := service.SeatRow{Number: row}
+		for b, block := range layout.blocks {
+			for i, letter := range block {
+				number := fmt.Sprintf("%d%c", row, letter)
+				characteristics := seatCharacteristics(layout, row, b, i)
+				price := seatPrice(layout.class, characteristics)
+
+				seatRow.Seats = append(seatRow.Seats, service.Seat{
+					Number:          number,
+					Characteristics: characteristics,
+					Price:           service.PriceInfo{Amount: price, Currency: "IDR", Formatted: helpers.FormatIDR(price)},
+					Available:       !taken[number] && !occupied(offerID, number),
+				})
+			}
+		}
+		cabin.Rows = append(cabin.Rows, seatRow)
+	}
+	return cabin
+}
+
+// seatCharacteristics describes the seat at position i of block b in a row
+func seatCharacteristics(layout cabinLayout, row, b, i int) []string {
+	var characteristics []string
+
+	block := layout.blocks[b]
+	switch {
+	case (b == 0 && i == 0) || (b == len(layout.blocks)-1 && i == len(block)-1):
+		characteristics = append(characteristics, service.SeatWindow)
+	case i == 0 || i == len(block)-1:
+		characteristics = append(characteristics, service.SeatAisle)
+	default:
+		characteristics = append(characteristics, service.SeatMiddle)
+	}
+
+	if row == layout.firstRow {
+		characteristics = append(characteristics, service.SeatFrontOfCabin, service.SeatExtraLegroom)
+	}
+	if slices.Contains(layout.exitRows, row) {
+		characteristics = append(characteristics, service.SeatExitRow, service.SeatExtraLegroom)
+	}
+	if slices.Contains(layout.exitRows, row+1) && !slices.Contains(layout.exitRows, row) {
+		characteristics = append(characteristics, service.SeatNoRecline)
+	}
+	return characteristics
+}
+
+func seatPrice(cabinClass string, characteristics []string) float64 {
+	if cabinClass != "economy" {
+		return 0
+	}
+	switch {
+	case slices.Contains(characteristics, service.SeatExitRow):
+		return priceExtraLegroom
+	case slices.Contains(characteristics, service.SeatFrontOfCabin):
+		return priceFrontOfCabin
+	case slices.Contains(characteristics, service.SeatMiddle):
+		return priceMiddle
+	}
+	return priceWindowAisle
+}
+
+// occupied deterministically marks a share of the seats of an offer as sold
+func occupied(offerID, seat string) bool {
+	h := fnv.New32a()
+	h.Write([]byte(offerID + "|" + seat))
+	return h.Sum32()%100 < occupancyPercent
+}
@@ -0,0 +1,143 @@
+package simulator_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/elkoshar/bookcabin/service"
+	"github.com/elkoshar/bookcabin/service/simulator"
+	"github.com/stretchr/testify/assert"
+)
+
+func findSeat(m service.SeatMap, number string) (service.Seat, bool) {
+	for _, cabin := range m.Cabins {
+		for _, row := range cabin.Rows {
+			for _, seat := range row.Seats {
+				if seat.Number == number {
+					return seat, true
+				}
+			}
+		}
+	}
+	return service.Seat{}, false
+}
+
+func TestGenerateSeatMap(t *testing.T) {
+	tests := []struct {
+		aircraft     string
+		wantAircraft string
+		layout       string
+		firstRow     int
+		lastRow      int
+	}{
+		{aircraft: "Boeing 737-800NG", wantAircraft: "Boeing 737-800", layout: "ABC DEF", firstRow: 6, lastRow: 32},
+		{aircraft: "Boeing 737-900ER", wantAircraft: "Boeing 737-900ER", layout: "ABC DEF", firstRow: 6, lastRow: 38},
+		{aircraft: "Airbus A320", wantAircraft: "Airbus A320", layout: "ABC DEF", firstRow: 6, lastRow: 31},
+		{aircraft: "Airbus A330", wantAircraft: "Airbus A330-300", layout: "AC DEFG HK", firstRow: 11, lastRow: 45},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.aircraft, func(t *testing.T) {
+			flight := service.UnifiedFlight{ID: "offer-1", Aircraft: tt.aircraft, CabinClass: "economy"}
+
+			seatMap, err := simulator.GenerateSeatMap(flight, nil)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantAircraft, seatMap.Aircraft)
+			assert.Len(t, seatMap.Cabins, 1)
+
+			cabin := seatMap.Cabins[0]
+			assert.Equal(t, "economy", cabin.CabinClass)
+			assert.Equal(t, tt.layout, cabin.Layout)
+			assert.Equal(t, tt.firstRow, cabin.FirstRow)
+			assert.Equal(t, tt.lastRow, cabin.LastRow)
+			assert.Len(t, cabin.Rows, tt.lastRow-tt.firstRow+1)
+
+			again, _ := simulator.GenerateSeatMap(flight, nil)
+			assert.Equal(t, seatMap, again)
+		})
+	}
+}
+
+func TestGenerateSeatMap_Characteristics(t *testing.T) {
+	flight := service.UnifiedFlight{ID: "offer-1", Aircraft: "Airbus A320", CabinClass: "economy"}
+	seatMap, err := simulator.GenerateSeatMap(flight, map[string]bool{"20A": true})
+	assert.NoError(t, err)
+
+	window, _ := findSeat(seatMap, "20A")
+	assert.Contains(t, window.Characteristics, service.SeatWindow)
+	assert.False(t, window.Available)
+	assert.Equal(t, float64(80000), window.Price.Amount)
+
+	middle, _ := findSeat(seatMap, "20B")
+	assert.Contains(t, middle.Characteristics, service.SeatMiddle)
+	assert.Equal(t, float64(50000), middle.Price.Amount)
+
+	aisle, _ := findSeat(seatMap, "20C")
+	assert.Contains(t, aisle.Characteristics, service.SeatAisle)
+
+	exit, _ := findSeat(seatMap, "12F")
+	assert.Contains(t, exit.Characteristics, service.SeatExitRow)
+	assert.Equal(t, float64(200000), exit.Price.Amount)
+
+	beforeExit, _ := findSeat(seatMap, "11A")
+	assert.Contains(t, beforeExit.Characteristics, service.SeatNoRecline)
+}
+
+func TestGenerateSeatMap_Unavailable(t *testing.T) {
+	_, err := simulator.GenerateSeatMap(service.UnifiedFlight{ID: "offer-1", CabinClass: "economy"}, nil)
+	assert.ErrorIs(t, err, service.ErrSeatMapUnavailable)
+
+	_, err = simulator.GenerateSeatMap(service.UnifiedFlight{ID: "offer-1", Aircraft: "Airbus A320", CabinClass: "premium_economy"}, nil)
+	assert.ErrorIs(t, err, service.ErrSeatMapUnavailable)
+}
+
+func TestBookingBackend_ReserveSeats(t *testing.T) {
+	backend := simulator.NewBookingBackend()
+	ctx := context.Background()
+
+	flight := service.UnifiedFlight{ID: "offer-1", Aircraft: "Boeing 737-900ER", CabinClass: "economy", AvailableSeats: 10}
+	seatMap, err := backend.SeatMap(flight)
+	assert.NoError(t, err)
+
+	var free []string
+	for _, row := range seatMap.Cabins[0].Rows {
+		for _, seat := range row.Seats {
+			if seat.Available {
+				free = append(free, seat.Number)
+			}
+		}
+	}
+
+	req := service.HoldRequest{Flight: flight, Passengers: make([]service.Passenger, 1), ExpiresAt: time.Now().Add(time.Minute)}
+	first, _ := backend.CreateHold(ctx, req)
+	second, _ := backend.CreateHold(ctx, req)
+
+	reserved, err := backend.ReserveSeats(ctx, first.Locator, []string{free[0]})
+	assert.NoError(t, err)
+	assert.Equal(t, free[0], reserved[0].Number)
+
+	// the hold may pick its own seat again
+	_, err = backend.ReserveSeats(ctx, first.Locator, []string{free[0]})
+	assert.NoError(t, err)
+
+	_, err = backend.ReserveSeats(ctx, second.Locator, []string{free[0]})
+	assert.ErrorIs(t, err, service.ErrSeatUnavailable)
+
+	_, err = backend.ReserveSeats(ctx, second.Locator, []string{free[1], free[2]})
+	assert.ErrorIs(t, err, service.ErrInvalidSeatSelection)
+
+	_, err = backend.ReserveSeats(ctx, second.Locator, []string{"99Z"})
+	assert.ErrorIs(t, err, service.ErrInvalidSeatSelection)
+
+	seatMap, _ = backend.SeatMap(flight)
+	seat, _ := findSeat(seatMap, free[0])
+	assert.False(t, seat.Available)
+
+	assert.NoError(t, backend.CancelHold(ctx, first.Locator))
+	_, err = backend.ReserveSeats(ctx, second.Locator, []string{free[0]})
+	assert.NoError(t, err)
+
+	_, err = backend.ReserveSeats(ctx, first.Locator, []string{free[1]})
+	assert.ErrorIs(t, err, service.ErrHoldNotFound)
+}
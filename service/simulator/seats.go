@@ -0,0 +1,96 @@
+package simulator
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/elkoshar/bookcabin/service"
+)
+
+// SeatMap returns the seat map of a flight with the seats reserved on active holds taken
+func (b *BookingBackend) SeatMap(flight service.UnifiedFlight) (service.SeatMap, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.releaseExpired()
+
+	taken := make(map[string]bool)
+	for seat := range b.seatOwners[flight.ID] {
+		taken[seat] = true
+	}
+	return GenerateSeatMap(flight, taken)
+}
+
+// ReserveSeats replaces the seats reserved on an active hold and returns them with their prices
+func (b *BookingBackend) ReserveSeats(ctx context.Context, locator string, seatNumbers []string) ([]service.Seat, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.releaseExpired()
+
+	hold, ok := b.holds[locator]
+	if !ok || hold.Status != service.HoldStatusActive {
+		return nil, service.ErrHoldNotFound
+	}
+	if len(seatNumbers) > hold.Passengers {
+		return nil, fmt.Errorf("%w: %d seats for %d passengers", service.ErrInvalidSeatSelection, len(seatNumbers), hold.Passengers)
+	}
+
+	// seats reserved by other holds are taken, the hold's own seats can be chosen again
+	owners := b.seatOwners[hold.OfferID]
+	taken := make(map[string]bool)
+	for seat, owner := range owners {
+		if owner != locator {
+			taken[seat] = true
+		}
+	}
+
+	seatMap, err := GenerateSeatMap(b.holdFlights[locator], taken)
+	if err != nil {
+		return nil, err
+	}
+
+	seats := make(map[string]service.Seat)
+	for _, cabin := range seatMap.Cabins {
+		for _, row := range cabin.Rows {
+			for _, seat := range row.Seats {
+				seats[seat.Number] = seat
+			}
+		}
+	}
+
+	reserved := make([]service.Seat, 0, len(seatNumbers))
+	for _, number := range seatNumbers {
+		seat, ok := seats[number]
+		if !ok {
+			return nil, fmt.Errorf("%w: seat %s does not exist", service.ErrInvalidSeatSelection, number)
+		}
+		if !seat.Available && owners[number] != locator {
+			return nil, fmt.Errorf("%w: %s", service.ErrSeatUnavailable, number)
+		}
+		reserved = append(reserved, seat)
+	}
+
+	b.releaseSeats(hold)
+	if owners == nil {
+		owners = make(map[string]string)
+		b.seatOwners[hold.OfferID] = owners
+	}
+	for _, seat := range reserved {
+		owners[seat.Number] = locator
+	}
+	hold.Seats = append([]string(nil), seatNumbers...)
+
+	return reserved, nil
+}
+
+// releaseSeats frees the seats reserved on a hold
+func (b *BookingBackend) releaseSeats(hold *service.ProviderHold) {
+	owners := b.seatOwners[hold.OfferID]
+	for _, seat := range hold.Seats {
+		if owners[seat] == hold.Locator {
+			delete(owners, seat)
+		}
+	}
+	hold.Seats = nil
+}
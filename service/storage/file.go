@@ -0,0 +1,214 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/elkoshar/bookcabin/service"
+)
+
+const (
+	sessionsDir  = "searches"
+	snapshotsDir = "snapshots"
+	bookingsDir  = "bookings"
+	pricesDir    = "prices"
+)
+
+// File stores every record as a JSON document below a data directory, so the service keeps its
+// state across restarts without an external database. It is meant for a single instance.
+type File struct {
+	dir string
+	mu  sync.RWMutex
+
+	// pricesMu serialises the read-modify-write of price history documents
+	pricesMu sync.Mutex
+}
+
+// NewFile creates the data directory layout below dir when it does not exist yet
+func NewFile(dir string) (*File, error) {
+	if dir == "" {
+		return nil, fmt.Errorf("file storage requires a data directory")
+	}
+
+	for _, sub := range []string{sessionsDir, snapshotsDir, bookingsDir, pricesDir} {
+		if err := os.MkdirAll(filepath.Join(dir, sub), 0o755); err != nil {
+			return nil, fmt.Errorf("create storage directory: %w", err)
+		}
+	}
+
+	return &File{dir: dir}, nil
+}
+
+func (f *File) SaveSearchSession(ctx context.Context, session service.SearchSession) error {
+	return f.write(sessionsDir, session.ID, session)
+}
+
+func (f *File) GetSearchSession(ctx context.Context, id string) (service.SearchSession, error) {
+	var session service.SearchSession
+	err := f.read(sessionsDir, id, &session, service.ErrSearchNotFound)
+	return session, err
+}
+
+func (f *File) SaveSnapshot(ctx context.Context, snapshot service.SearchSnapshot) error {
+	return f.write(snapshotsDir, snapshot.SearchID, snapshot)
+}
+
+func (f *File) GetSnapshot(ctx context.Context, searchID string) (service.SearchSnapshot, error) {
+	var snapshot service.SearchSnapshot
+	err := f.read(snapshotsDir, searchID, &snapshot, service.ErrSearchNotFound)
+	return snapshot, err
+}
+
+func (f *File) PruneSearches(ctx context.Context, before time.Time) (int, error) {
+	pruned, err := f.prune(sessionsDir, before)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := f.prune(snapshotsDir, before); err != nil {
+		return pruned, err
+	}
+	return pruned, nil
+}
+
+func (f *File) SaveBooking(ctx context.Context, booking service.Booking) error {
+	return f.write(bookingsDir, booking.Reference, booking)
+}
+
+func (f *File) GetBooking(ctx context.Context, reference string) (service.Booking, error) {
+	var booking service.Booking
+	err := f.read(bookingsDir, reference, &booking, service.ErrBookingNotFound)
+	return booking, err
+}
+
+func (f *File) RecordPrices(ctx context.Context, points ...service.PricePoint) error {
+	byOffer := make(map[string][]service.PricePoint)
+	for _, p := range points {
+		byOffer[p.OfferID] = append(byOffer[p.OfferID], p)
+	}
+
+	f.pricesMu.Lock()
+	defer f.pricesMu.Unlock()
+
+	for offerID, added := range byOffer {
+		history, err := f.PriceHistory(ctx, offerID)
+		if err != nil {
+			return err
+		}
+		if err := f.write(pricesDir, offerID, appendPrices(history, added...)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (f *File) PriceHistory(ctx context.Context, offerID string) ([]service.PricePoint, error) {
+	var history []service.PricePoint
+	err := f.read(pricesDir, offerID, &history, nil)
+	return history, err
+}
+
+// path returns the document path of key, rejecting keys that would escape the collection directory
+func (f *File) path(collection, key string) (string, error) {
+	if key == "" || key != filepath.Base(key) || key == "." || key == ".." {
+		return "", fmt.Errorf("invalid storage key %q", key)
+	}
+	return filepath.Join(f.dir, collection, key+".json"), nil
+}
+
+// write replaces a document atomically by writing a temporary file and renaming it
+func (f *File) write(collection, key string, v any) error {
+	path, err := f.path(collection, key)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// prune removes the documents of collection whose created_at is before before
+func (f *File) prune(collection string, before time.Time) (int, error) {
+	f.mu.RLock()
+	entries, err := os.ReadDir(filepath.Join(f.dir, collection))
+	f.mu.RUnlock()
+	if err != nil {
+		return 0, err
+	}
+
+	pruned := 0
+	for _, entry := range entries {
+		key, ok := strings.CutSuffix(entry.Name(), ".json")
+		if !ok || entry.IsDir() {
+			continue
+		}
+
+		var doc struct {
+			CreatedAt time.Time `json:"created_at"`
+		}
+		err := f.read(collection, key, &doc, service.ErrSearchNotFound)
+		if errors.Is(err, service.ErrSearchNotFound) {
+			continue
+		}
+		if err != nil {
+			return pruned, err
+		}
+		if !doc.CreatedAt.Before(before) {
+			continue
+		}
+
+		path, err := f.path(collection, key)
+		if err != nil {
+			return pruned, err
+		}
+		f.mu.Lock()
+		err = os.Remove(path)
+		f.mu.Unlock()
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return pruned, err
+		}
+		pruned++
+	}
+	return pruned, nil
+}
+
+// read decodes a document into v, returning notFound when it does not exist
+func (f *File) read(collection, key string, v any, notFound error) error {
+	path, err := f.path(collection, key)
+	if err != nil {
+		if notFound != nil {
+			return notFound
+		}
+		return err
+	}
+
+	f.mu.RLock()
+	data, err := os.ReadFile(path)
+	f.mu.RUnlock()
+
+	if errors.Is(err, fs.ErrNotExist) {
+		return notFound
+	}
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
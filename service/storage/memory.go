@@ -0,0 +1,121 @@
+package storage
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/elkoshar/bookcabin/service"
+)
+
+// Memory keeps everything in process memory and loses it on restart, mainly useful for tests
+type Memory struct {
+	mu        sync.RWMutex
+	sessions  map[string]service.SearchSession
+	snapshots map[string]service.SearchSnapshot
+	bookings  map[string]service.Booking
+	prices    map[string][]service.PricePoint
+}
+
+func NewMemory() *Memory {
+	return &Memory{
+		sessions:  make(map[string]service.SearchSession),
+		snapshots: make(map[string]service.SearchSnapshot),
+		bookings:  make(map[string]service.Booking),
+		prices:    make(map[string][]service.PricePoint),
+	}
+}
+
+func (m *Memory) SaveSearchSession(ctx context.Context, session service.SearchSession) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.sessions[session.ID] = session
+	return nil
+}
+
+func (m *Memory) GetSearchSession(ctx context.Context, id string) (service.SearchSession, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	session, ok := m.sessions[id]
+	if !ok {
+		return service.SearchSession{}, service.ErrSearchNotFound
+	}
+	return session, nil
+}
+
+func (m *Memory) SaveSnapshot(ctx context.Context, snapshot service.SearchSnapshot) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.snapshots[snapshot.SearchID] = snapshot
+	return nil
+}
+
+func (m *Memory) GetSnapshot(ctx context.Context, searchID string) (service.SearchSnapshot, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	snapshot, ok := m.snapshots[searchID]
+	if !ok {
+		return service.SearchSnapshot{}, service.ErrSearchNotFound
+	}
+	return snapshot, nil
+}
+
+func (m *Memory) PruneSearches(ctx context.Context, before time.Time) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	pruned := 0
+	for id, session := range m.sessions {
+		if session.CreatedAt.Before(before) {
+			delete(m.sessions, id)
+			pruned++
+		}
+	}
+	for id, snapshot := range m.snapshots {
+		if snapshot.CreatedAt.Before(before) {
+			delete(m.snapshots, id)
+		}
+	}
+	return pruned, nil
+}
+
+func (m *Memory) SaveBooking(ctx context.Context, booking service.Booking) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.bookings[booking.Reference] = booking
+	return nil
+}
+
+func (m *Memory) GetBooking(ctx context.Context, reference string) (service.Booking, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	booking, ok := m.bookings[reference]
+	if !ok {
+		return service.Booking{}, service.ErrBookingNotFound
+	}
+	return booking, nil
+}
+
+func (m *Memory) RecordPrices(ctx context.Context, points ...service.PricePoint) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, p := range points {
+		m.prices[p.OfferID] = appendPrices(m.prices[p.OfferID], p)
+	}
+	return nil
+}
+
+func (m *Memory) PriceHistory(ctx context.Context, offerID string) ([]service.PricePoint, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return slices.Clone(m.prices[offerID]), nil
+}
@@ -0,0 +1,67 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/elkoshar/bookcabin/api"
+)
+
+// SearchPruner removes stored searches once they are older than the retention, so search sessions and
+// snapshots do not pile up for as long as the service runs
+type SearchPruner struct {
+	store     api.Storage
+	retention time.Duration
+
+	stop context.CancelFunc
+	wg   sync.WaitGroup
+}
+
+// NewSearchPruner prunes store every interval. Nothing is pruned when retention or interval is not positive.
+func NewSearchPruner(store api.Storage, retention, interval time.Duration) *SearchPruner {
+	ctx, stop := context.WithCancel(context.Background())
+	p := &SearchPruner{store: store, retention: retention, stop: stop}
+
+	if retention > 0 && interval > 0 {
+		p.wg.Add(1)
+		go p.run(ctx, interval)
+	}
+	return p
+}
+
+// Close stops the prune schedule
+func (p *SearchPruner) Close() {
+	p.stop()
+	p.wg.Wait()
+}
+
+func (p *SearchPruner) run(ctx context.Context, interval time.Duration) {
+	defer p.wg.Done()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			p.prune(ctx)
+		}
+	}
+}
+
+// prune removes the searches made more than the retention ago
+func (p *SearchPruner) prune(ctx context.Context) {
+	pruned, err := p.store.PruneSearches(ctx, time.Now().Add(-p.retention))
+	if err != nil {
+		slog.Error(fmt.Sprintf("[Storage] Failed to prune searches: %v", err))
+		return
+	}
+	if pruned > 0 {
+		slog.Info(fmt.Sprintf("[Storage] Pruned %d searches older than %s", pruned, p.retention))
+	}
+}
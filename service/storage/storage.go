@@ -0,0 +1,38 @@
+package storage
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/elkoshar/bookcabin/api"
+	"github.com/elkoshar/bookcabin/service"
+)
+
+const (
+	DriverMemory = "memory"
+	DriverFile   = "file"
+)
+
+// MaxPricePoints is how many observations the price history of an offer keeps. Older ones are dropped,
+// so the history of an offer that is searched often does not grow without bound.
+const MaxPricePoints = 100
+
+// appendPrices appends added to history and keeps the newest MaxPricePoints observations
+func appendPrices(history []service.PricePoint, added ...service.PricePoint) []service.PricePoint {
+	history = append(history, added...)
+	if len(history) > MaxPricePoints {
+		history = history[len(history)-MaxPricePoints:]
+	}
+	return history
+}
+
+// New returns the storage implementation selected by driver. path is the data directory of the file driver.
+func New(driver, path string) (api.Storage, error) {
+	switch strings.ToLower(driver) {
+	case DriverMemory, "":
+		return NewMemory(), nil
+	case DriverFile:
+		return NewFile(path)
+	}
+	return nil, fmt.Errorf("unsupported storage driver %q", driver)
+}
@@ -0,0 +1,183 @@
+package storage_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/elkoshar/bookcabin/api"
+	"github.com/elkoshar/bookcabin/service"
+	"github.com/elkoshar/bookcabin/service/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStorage(t *testing.T) {
+	drivers := map[string]func(t *testing.T) api.Storage{
+		"memory": func(t *testing.T) api.Storage {
+			return storage.NewMemory()
+		},
+		"file": func(t *testing.T) api.Storage {
+			store, err := storage.NewFile(t.TempDir())
+			require.NoError(t, err)
+			return store
+		},
+	}
+
+	for name, newStore := range drivers {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			store := newStore(t)
+			now := time.Now().UTC().Truncate(time.Second)
+
+			_, err := store.GetSearchSession(ctx, "missing")
+			assert.ErrorIs(t, err, service.ErrSearchNotFound)
+			_, err = store.GetSnapshot(ctx, "missing")
+			assert.ErrorIs(t, err, service.ErrSearchNotFound)
+			_, err = store.GetBooking(ctx, "NOPE12")
+			assert.ErrorIs(t, err, service.ErrBookingNotFound)
+
+			session := service.SearchSession{ID: "abc", TripType: service.TripTypeOneWay, TotalResults: 2, CreatedAt: now}
+			assert.NoError(t, store.SaveSearchSession(ctx, session))
+			got, err := store.GetSearchSession(ctx, "abc")
+			assert.NoError(t, err)
+			assert.Equal(t, session, got)
+
+			snapshot := service.SearchSnapshot{SearchID: "abc", Response: service.SearchResponse{SearchID: "abc", Flights: []service.UnifiedFlight{{ID: "offer-1"}}}, CreatedAt: now}
+			assert.NoError(t, store.SaveSnapshot(ctx, snapshot))
+			gotSnapshot, err := store.GetSnapshot(ctx, "abc")
+			assert.NoError(t, err)
+			assert.Equal(t, "offer-1", gotSnapshot.Response.Flights[0].ID)
+
+			booking := service.Booking{Reference: "ABC123", Status: service.BookingStatusHeld}
+			assert.NoError(t, store.SaveBooking(ctx, booking))
+			booking.Status = service.BookingStatusCancelled
+			assert.NoError(t, store.SaveBooking(ctx, booking))
+			gotBooking, err := store.GetBooking(ctx, "ABC123")
+			assert.NoError(t, err)
+			assert.Equal(t, service.BookingStatusCancelled, gotBooking.Status)
+
+			history, err := store.PriceHistory(ctx, "offer-1")
+			assert.NoError(t, err)
+			assert.Empty(t, history)
+
+			assert.NoError(t, store.RecordPrices(ctx,
+				service.PricePoint{OfferID: "offer-1", Amount: 100, Currency: "IDR", ObservedAt: now},
+				service.PricePoint{OfferID: "offer-2", Amount: 300, Currency: "IDR", ObservedAt: now},
+			))
+			assert.NoError(t, store.RecordPrices(ctx, service.PricePoint{OfferID: "offer-1", Amount: 120, Currency: "IDR", ObservedAt: now}))
+
+			history, err = store.PriceHistory(ctx, "offer-1")
+			assert.NoError(t, err)
+			assert.Len(t, history, 2)
+			assert.Equal(t, float64(120), history[1].Amount)
+
+			for i := 0; i < storage.MaxPricePoints; i++ {
+				assert.NoError(t, store.RecordPrices(ctx, service.PricePoint{OfferID: "offer-1", Amount: float64(200 + i), Currency: "IDR", ObservedAt: now}))
+			}
+			history, err = store.PriceHistory(ctx, "offer-1")
+			assert.NoError(t, err)
+			assert.Len(t, history, storage.MaxPricePoints)
+			assert.Equal(t, float64(200), history[0].Amount)
+			assert.Equal(t, float64(200+storage.MaxPricePoints-1), history[len(history)-1].Amount)
+		})
+	}
+}
+
+func TestFile_PersistsAcrossInstances(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	first, err := storage.NewFile(dir)
+	require.NoError(t, err)
+	assert.NoError(t, first.SaveBooking(ctx, service.Booking{Reference: "ABC123", Status: service.BookingStatusHeld}))
+
+	second, err := storage.NewFile(dir)
+	require.NoError(t, err)
+	booking, err := second.GetBooking(ctx, "ABC123")
+	assert.NoError(t, err)
+	assert.Equal(t, service.BookingStatusHeld, booking.Status)
+
+	_, err = second.GetBooking(ctx, "../ABC123")
+	assert.ErrorIs(t, err, service.ErrBookingNotFound)
+}
+
+func TestNew(t *testing.T) {
+	store, err := storage.New("memory", "")
+	assert.NoError(t, err)
+	assert.IsType(t, &storage.Memory{}, store)
+
+	store, err = storage.New("file", t.TempDir())
+	assert.NoError(t, err)
+	assert.IsType(t, &storage.File{}, store)
+
+	_, err = storage.New("postgres", "")
+	assert.Error(t, err)
+}
+
+func TestPruneSearches(t *testing.T) {
+	drivers := map[string]func(t *testing.T) api.Storage{
+		"memory": func(t *testing.T) api.Storage {
+			return storage.NewMemory()
+		},
+		"file": func(t *testing.T) api.Storage {
+			store, err := storage.NewFile(t.TempDir())
+			require.NoError(t, err)
+			return store
+		},
+	}
+
+	for name, newStore := range drivers {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			store := newStore(t)
+			now := time.Now().UTC().Truncate(time.Second)
+
+			for id, createdAt := range map[string]time.Time{"old": now.Add(-2 * time.Hour), "new": now} {
+				require.NoError(t, store.SaveSearchSession(ctx, service.SearchSession{ID: id, CreatedAt: createdAt}))
+				require.NoError(t, store.SaveSnapshot(ctx, service.SearchSnapshot{SearchID: id, CreatedAt: createdAt}))
+			}
+
+			pruned, err := store.PruneSearches(ctx, now.Add(-time.Hour))
+			assert.NoError(t, err)
+			assert.Equal(t, 1, pruned)
+
+			_, err = store.GetSearchSession(ctx, "old")
+			assert.ErrorIs(t, err, service.ErrSearchNotFound)
+			_, err = store.GetSnapshot(ctx, "old")
+			assert.ErrorIs(t, err, service.ErrSearchNotFound)
+
+			_, err = store.GetSearchSession(ctx, "new")
+			assert.NoError(t, err)
+			_, err = store.GetSnapshot(ctx, "new")
+			assert.NoError(t, err)
+
+			pruned, err = store.PruneSearches(ctx, now.Add(-time.Hour))
+			assert.NoError(t, err)
+			assert.Zero(t, pruned)
+		})
+	}
+}
+
+func TestSearchPruner(t *testing.T) {
+	ctx := context.Background()
+	store := storage.NewMemory()
+	require.NoError(t, store.SaveSearchSession(ctx, service.SearchSession{ID: "old", CreatedAt: time.Now().Add(-2 * time.Hour)}))
+	require.NoError(t, store.SaveSearchSession(ctx, service.SearchSession{ID: "new", CreatedAt: time.Now()}))
+
+	pruner := storage.NewSearchPruner(store, time.Hour, 10*time.Millisecond)
+	assert.Eventually(t, func() bool {
+		_, err := store.GetSearchSession(ctx, "old")
+		return err != nil
+	}, time.Second, 10*time.Millisecond)
+	pruner.Close()
+
+	_, err := store.GetSearchSession(ctx, "new")
+	assert.NoError(t, err)
+
+	// a closed pruner leaves searches alone
+	require.NoError(t, store.SaveSearchSession(ctx, service.SearchSession{ID: "late", CreatedAt: time.Now().Add(-2 * time.Hour)}))
+	time.Sleep(30 * time.Millisecond)
+	_, err = store.GetSearchSession(ctx, "late")
+	assert.NoError(t, err)
+}
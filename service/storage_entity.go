@@ -0,0 +1,32 @@
+package service
+
+import (
+	"errors"
+	"time"
+)
+
+var ErrSearchNotFound = errors.New("search not found")
+
+// SearchSession is a search request that was executed, kept so its results can be paged and analysed later
+type SearchSession struct {
+	ID           string         `json:"id"`
+	Criteria     SearchCriteria `json:"criteria"`
+	TripType     string         `json:"trip_type"`
+	TotalResults int            `json:"total_results"`
+	CreatedAt    time.Time      `json:"created_at"`
+}
+
+// SearchSnapshot is the full response returned for a search session
+type SearchSnapshot struct {
+	SearchID  string         `json:"search_id"`
+	Response  SearchResponse `json:"response"`
+	CreatedAt time.Time      `json:"created_at"`
+}
+
+// PricePoint is a price observed for an offer at a point in time
+type PricePoint struct {
+	OfferID    string    `json:"offer_id"`
+	Amount     float64   `json:"amount"`
+	Currency   string    `json:"currency"`
+	ObservedAt time.Time `json:"observed_at"`
+}
@@ -0,0 +1,67 @@
+package ticket
+
+import (
+	"bytes"
+	"html/template"
+)
+
+var htmlTemplate = template.Must(template.New("receipt").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>E-ticket {{.Reference}}</title>
+<style>
+body { font-family: Helvetica, Arial, sans-serif; color: #222; max-width: 760px; margin: 24px auto; }
+h1 { font-size: 22px; margin-bottom: 4px; }
+h2 { font-size: 16px; border-bottom: 1px solid #ccc; padding-bottom: 4px; margin-top: 28px; }
+table { width: 100%; border-collapse: collapse; }
+td, th { text-align: left; padding: 6px 4px; vertical-align: top; }
+.muted { color: #666; font-size: 13px; }
+.amount { text-align: right; }
+</style>
+</head>
+<body>
+<h1>Itinerary Receipt</h1>
+<div class="muted">Booking reference <strong>{{.Reference}}</strong> &middot; issued {{.IssuedAt}}</div>
+
+<h2>Flight</h2>
+<div>{{.Airline}} &middot; {{.CabinClass}}{{if .FareFamily}} ({{.FareFamily}}){{end}}</div>
+<table>
+<tr><th>Flight</th><th>Departure</th><th>Arrival</th><th>Duration</th></tr>
+{{range .Segments}}<tr>
+<td>{{.FlightNumber}}{{if .Aircraft}}<div class="muted">{{.Aircraft}}</div>{{end}}</td>
+<td>{{.Departure.City}} ({{.Departure.Airport}}){{if .Departure.Terminal}}<div class="muted">Terminal {{.Departure.Terminal}}</div>{{end}}<div>{{.Departure.LocalTime}}</div></td>
+<td>{{.Arrival.City}} ({{.Arrival.Airport}}){{if .Arrival.Terminal}}<div class="muted">Terminal {{.Arrival.Terminal}}</div>{{end}}<div>{{.Arrival.LocalTime}}</div></td>
+<td>{{.Duration}}</td>
+</tr>
+{{end}}</table>
+<p>Baggage: {{.Baggage}}</p>
+
+<h2>Passengers</h2>
+<table>
+<tr><th>Passenger</th><th>Seat</th><th>E-ticket</th></tr>
+{{range .Passengers}}<tr>
+<td>{{.Name}}</td>
+<td>{{if .Seat}}{{.Seat}}{{else}}-{{end}}</td>
+<td>{{range .Tickets}}<div>{{.}}</div>{{end}}</td>
+</tr>
+{{end}}</table>
+
+<h2>Payment</h2>
+<table>
+{{range .Charges}}<tr><td>{{.Label}}</td><td class="amount">{{.Amount}}</td></tr>
+{{end}}<tr><th>Total</th><th class="amount">{{.Total}}</th></tr>
+</table>
+{{if .Payment}}<p class="muted">{{.Payment}}</p>{{end}}
+<p class="muted">Contact: {{.Contact}}</p>
+</body>
+</html>
+`))
+
+func renderHTML(r receipt) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := htmlTemplate.Execute(&buf, r); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,65 @@
+package ticket
+
+import (
+	"crypto/rand"
+	"math/big"
+
+	"github.com/elkoshar/bookcabin/service"
+)
+
+// prefixes are the ticketing codes the simulated reservation systems issue numbers under
+var prefixes = map[string]string{
+	"GA": "126",
+	"JT": "990",
+	"ID": "938",
+	"QZ": "975",
+}
+
+// Segments returns the legs a booking is ticketed on, a flight without connections is one leg
+func Segments(flight service.UnifiedFlight) []service.FlightSegment {
+	if len(flight.Segments) > 0 {
+		return flight.Segments
+	}
+	return []service.FlightSegment{{
+		FlightNumber: flight.FlightNumber,
+		Departure:    flight.Departure,
+		Arrival:      flight.Arrival,
+		Duration:     flight.Duration,
+	}}
+}
+
+// Issue generates a 13-digit e-ticket number for every passenger on every segment
+func Issue(booking service.Booking) ([]service.ETicket, error) {
+	prefix, ok := prefixes[booking.Flight.Airline.Code]
+	if !ok {
+		prefix = "000"
+	}
+
+	segments := Segments(booking.Flight)
+	tickets := make([]service.ETicket, 0, len(booking.Passengers)*len(segments))
+	for p := range booking.Passengers {
+		for s, segment := range segments {
+			serial, err := rand.Int(rand.Reader, big.NewInt(1e10))
+			if err != nil {
+				return nil, err
+			}
+
+			tickets = append(tickets, service.ETicket{
+				Number:         prefix + leftPad(serial.String(), 10),
+				PassengerIndex: p,
+				SegmentIndex:   s,
+				FlightNumber:   segment.FlightNumber,
+				Origin:         segment.Departure.Airport,
+				Destination:    segment.Arrival.Airport,
+			})
+		}
+	}
+	return tickets, nil
+}
+
+func leftPad(s string, width int) string {
+	for len(s) < width {
+		s = "0" + s
+	}
+	return s
+}
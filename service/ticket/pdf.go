@@ -0,0 +1,161 @@
+package ticket
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// A4 page size and layout in PDF points
+const (
+	pageWidth  = 595
+	pageHeight = 842
+	margin     = 50
+)
+
+// pdfLine is one line of text on the receipt, an empty line leaves a gap
+type pdfLine struct {
+	Text string
+	Size float64
+	Bold bool
+}
+
+func receiptLines(r receipt) []pdfLine {
+	heading := func(s string) pdfLine { return pdfLine{Text: s, Size: 13, Bold: true} }
+	text := func(format string, args ...interface{}) pdfLine {
+		return pdfLine{Text: fmt.Sprintf(format, args...), Size: 10}
+	}
+	gap := pdfLine{Size: 8}
+
+	lines := []pdfLine{
+		{Text: "Itinerary Receipt", Size: 18, Bold: true},
+		text("Booking reference %s, issued %s", r.Reference, r.IssuedAt),
+		gap,
+		heading("Flight"),
+		text("%s - %s", r.Airline, r.CabinClass),
+	}
+	for _, s := range r.Segments {
+		lines = append(lines, gap, pdfLine{Text: fmt.Sprintf("%s  %s -> %s  (%s)", s.FlightNumber, s.Departure.Airport, s.Arrival.Airport, s.Duration), Size: 11, Bold: true})
+		if s.Aircraft != "" {
+			lines = append(lines, text("Aircraft: %s", s.Aircraft))
+		}
+		lines = append(lines,
+			text("Depart: %s, %s%s", s.Departure.LocalTime, s.Departure.City, terminal(s.Departure.Terminal)),
+			text("Arrive: %s, %s%s", s.Arrival.LocalTime, s.Arrival.City, terminal(s.Arrival.Terminal)),
+		)
+	}
+	lines = append(lines, text("Baggage: %s", r.Baggage), gap, heading("Passengers"))
+
+	for _, p := range r.Passengers {
+		seat := ""
+		if p.Seat != "" {
+			seat = ", seat " + p.Seat
+		}
+		lines = append(lines, pdfLine{Text: p.Name + seat, Size: 10, Bold: true})
+		for _, t := range p.Tickets {
+			lines = append(lines, text("E-ticket %s", t))
+		}
+	}
+
+	lines = append(lines, gap, heading("Payment"))
+	for _, c := range r.Charges {
+		lines = append(lines, text("%s: %s", c.Label, c.Amount))
+	}
+	lines = append(lines, pdfLine{Text: "Total: " + r.Total, Size: 11, Bold: true})
+	if r.Payment != "" {
+		lines = append(lines, text("%s", r.Payment))
+	}
+	lines = append(lines, gap, text("Contact: %s", r.Contact))
+
+	return lines
+}
+
+func terminal(t string) string {
+	if t == "" {
+		return ""
+	}
+	return ", Terminal " + t
+}
+
+// renderPDF lays the lines out on A4 pages with the standard Helvetica fonts
+func renderPDF(r receipt) []byte {
+	var pages []string
+	var page strings.Builder
+	y := float64(pageHeight - margin)
+
+	for _, line := range receiptLines(r) {
+		height := line.Size * 1.4
+		if y-height < margin {
+			pages = append(pages, page.String())
+			page.Reset()
+			y = pageHeight - margin
+		}
+		y -= height
+		if line.Text == "" {
+			continue
+		}
+
+		font := "F1"
+		if line.Bold {
+			font = "F2"
+		}
+		fmt.Fprintf(&page, "BT /%s %.1f Tf %d %.1f Td (%s) Tj ET\n", font, line.Size, margin, y, pdfEscape(line.Text))
+	}
+	pages = append(pages, page.String())
+
+	return writePDF(pages)
+}
+
+// writePDF assembles the catalog, page tree, fonts and one content stream per page
+func writePDF(pages []string) []byte {
+	var buf bytes.Buffer
+	var offsets []int
+
+	object := func(body string) {
+		offsets = append(offsets, buf.Len())
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	// objects 1-4 are the catalog, page tree and fonts, each page adds a page and a content object
+	kids := make([]string, len(pages))
+	for i := range pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 5+i*2)
+	}
+
+	buf.WriteString("%PDF-1.4\n")
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(pages)))
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+	for i, content := range pages {
+		content = strings.TrimSuffix(content, "\n")
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %d %d] /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>", pageWidth, pageHeight, 6+i*2))
+		object(fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(content), content))
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+
+	return buf.Bytes()
+}
+
+// pdfEscape escapes string delimiters and replaces characters the standard fonts cannot show
+func pdfEscape(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			b.WriteRune('\\')
+			b.WriteRune(r)
+		case r < 32 || r > 126:
+			b.WriteRune('?')
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,170 @@
+package ticket
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/elkoshar/bookcabin/pkg/helpers"
+	"github.com/elkoshar/bookcabin/service"
+)
+
+// receipt is the itinerary receipt of a ticketed booking with every value ready for display
+type receipt struct {
+	Reference  string
+	IssuedAt   string
+	Airline    string
+	CabinClass string
+	FareFamily string
+	Baggage    string
+	Contact    string
+	Payment    string
+	Segments   []receiptSegment
+	Passengers []receiptPassenger
+	Charges    []receiptCharge
+	Total      string
+}
+
+type receiptSegment struct {
+	FlightNumber string
+	Aircraft     string
+	Duration     string
+	Departure    receiptPoint
+	Arrival      receiptPoint
+}
+
+type receiptPoint struct {
+	Airport   string
+	City      string
+	Terminal  string
+	LocalTime string
+}
+
+type receiptPassenger struct {
+	Name    string
+	Seat    string
+	Tickets []string
+}
+
+type receiptCharge struct {
+	Label  string
+	Amount string
+}
+
+func newReceipt(booking service.Booking) receipt {
+	flight := booking.Flight
+
+	r := receipt{
+		Reference:  booking.Reference,
+		IssuedAt:   booking.UpdatedAt.Format("02 Jan 2006 15:04 MST"),
+		Airline:    fmt.Sprintf("%s (%s)", flight.Airline.Name, flight.Airline.Code),
+		CabinClass: capitalize(flight.CabinClass),
+		Baggage:    baggageText(flight.Baggage),
+		Contact:    fmt.Sprintf("%s, %s", booking.Contact.Email, booking.Contact.Phone),
+		Total:      formatPrice(booking.TotalPrice),
+	}
+	if flight.FareRules != nil {
+		r.FareFamily = flight.FareRules.Family
+	}
+	if booking.Payment != nil {
+		r.Payment = fmt.Sprintf("Card ending %s, %s captured", booking.Payment.CardLast4, formatPrice(booking.Payment.CapturedAmount))
+	}
+
+	for _, segment := range Segments(flight) {
+		r.Segments = append(r.Segments, receiptSegment{
+			FlightNumber: segment.FlightNumber,
+			Aircraft:     flight.Aircraft,
+			Duration:     segment.Duration.Formatted,
+			Departure:    point(segment.Departure),
+			Arrival:      point(segment.Arrival),
+		})
+	}
+
+	for i, p := range booking.Passengers {
+		passenger := receiptPassenger{Name: fmt.Sprintf("%s %s %s", p.Title, p.FirstName, p.LastName)}
+		for _, seat := range booking.Seats {
+			if seat.PassengerIndex == i {
+				passenger.Seat = seat.SeatNumber
+			}
+		}
+		for _, t := range booking.Tickets {
+			if t.PassengerIndex == i {
+				passenger.Tickets = append(passenger.Tickets, fmt.Sprintf("%s  %s %s-%s", t.Number, t.FlightNumber, t.Origin, t.Destination))
+			}
+		}
+		r.Passengers = append(r.Passengers, passenger)
+	}
+
+	r.Charges = append(r.Charges, receiptCharge{Label: fmt.Sprintf("Fare (%d passenger(s))", len(booking.Passengers)), Amount: formatPrice(booking.FareBreakdown.BaseFare)})
+	if booking.FareBreakdown.Seats.Amount > 0 {
+		r.Charges = append(r.Charges, receiptCharge{Label: "Seats", Amount: formatPrice(booking.FareBreakdown.Seats)})
+	}
+	for _, a := range booking.Ancillaries {
+		r.Charges = append(r.Charges, receiptCharge{Label: fmt.Sprintf("%s (passenger %d)", a.Name, a.PassengerIndex+1), Amount: formatPrice(a.Price)})
+	}
+
+	return r
+}
+
+// point shows a departure or arrival in the local time of its airport
+func point(l service.LocationInfo) receiptPoint {
+	p := receiptPoint{Airport: l.Airport, City: l.City, Terminal: l.Terminal, LocalTime: l.DateTime}
+	if t, err := time.Parse(time.RFC3339, l.DateTime); err == nil {
+		p.LocalTime = t.Format("Mon, 02 Jan 2006 15:04") + " " + zoneName(t)
+	}
+	return p
+}
+
+// zoneName names the Indonesian time zones and falls back to the UTC offset elsewhere
+func zoneName(t time.Time) string {
+	_, offset := t.Zone()
+	switch offset {
+	case 7 * 3600:
+		return "WIB"
+	case 8 * 3600:
+		return "WITA"
+	case 9 * 3600:
+		return "WIT"
+	}
+	return "UTC" + t.Format("-07:00")
+}
+
+func baggageText(b *service.BaggageInfo) string {
+	if b == nil {
+		return "Not included"
+	}
+
+	var parts []string
+	switch {
+	case b.CabinKg > 0:
+		parts = append(parts, fmt.Sprintf("Cabin %d kg", b.CabinKg))
+	case b.CabinPieces > 0:
+		parts = append(parts, fmt.Sprintf("Cabin %d piece(s)", b.CabinPieces))
+	}
+	switch {
+	case b.CheckedKg > 0:
+		parts = append(parts, fmt.Sprintf("Checked %d kg", b.CheckedKg))
+	case b.CheckedPieces > 0:
+		parts = append(parts, fmt.Sprintf("Checked %d piece(s)", b.CheckedPieces))
+	default:
+		parts = append(parts, "No checked baggage")
+	}
+	if b.Note != "" {
+		parts = append(parts, b.Note)
+	}
+	return strings.Join(parts, ", ")
+}
+
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
+
+func formatPrice(p service.PriceInfo) string {
+	if p.Formatted != "" {
+		return p.Formatted
+	}
+	return helpers.FormatIDR(p.Amount)
+}
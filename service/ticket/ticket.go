@@ -0,0 +1,26 @@
+package ticket
+
+import (
+	"fmt"
+
+	"github.com/elkoshar/bookcabin/service"
+)
+
+// Render produces the itinerary receipt of a ticketed booking as a PDF or HTML document
+func Render(booking service.Booking, format string) (service.Document, error) {
+	r := newReceipt(booking)
+	filename := fmt.Sprintf("eticket-%s.%s", booking.Reference, format)
+
+	switch format {
+	case service.TicketFormatPDF:
+		return service.Document{ContentType: "application/pdf", Filename: filename, Body: renderPDF(r)}, nil
+	case service.TicketFormatHTML:
+		body, err := renderHTML(r)
+		if err != nil {
+			return service.Document{}, err
+		}
+		return service.Document{ContentType: "text/html; charset=utf-8", Filename: filename, Body: body}, nil
+	default:
+		return service.Document{}, fmt.Errorf("%w: %s", service.ErrUnsupportedFormat, format)
+	}
+}
@@ -0,0 +1,93 @@
+package ticket_test
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/elkoshar/bookcabin/service"
+	"github.com/elkoshar/bookcabin/service/ticket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func ticketedBooking() service.Booking {
+	return service.Booking{
+		Reference: "ABC234",
+		Status:    service.BookingStatusTicketed,
+		Flight: service.UnifiedFlight{
+			Airline:      service.AirlineInfo{Name: "Garuda Indonesia", Code: "GA"},
+			FlightNumber: "GA410",
+			Departure:    service.LocationInfo{Airport: "CGK", City: "Jakarta", Terminal: "3", DateTime: "2025-12-15T14:00:00+07:00"},
+			Arrival:      service.LocationInfo{Airport: "DPS", City: "Denpasar", Terminal: "I", DateTime: "2025-12-15T18:45:00+08:00"},
+			Segments: []service.FlightSegment{
+				{FlightNumber: "GA315", Departure: service.LocationInfo{Airport: "CGK", City: "Jakarta", Terminal: "3", DateTime: "2025-12-15T14:00:00+07:00"}, Arrival: service.LocationInfo{Airport: "SUB", City: "Surabaya", DateTime: "2025-12-15T15:30:00+07:00"}, Duration: service.DurationInfo{Formatted: "1h 30m"}},
+				{FlightNumber: "GA332", Departure: service.LocationInfo{Airport: "SUB", City: "Surabaya", DateTime: "2025-12-15T17:15:00+07:00"}, Arrival: service.LocationInfo{Airport: "DPS", City: "Denpasar", Terminal: "I", DateTime: "2025-12-15T18:45:00+08:00"}, Duration: service.DurationInfo{Formatted: "1h 30m"}},
+			},
+			CabinClass: "economy",
+			Aircraft:   "Boeing 737",
+			Baggage:    &service.BaggageInfo{CabinPieces: 1, CheckedPieces: 2},
+		},
+		Passengers: []service.Passenger{
+			{Title: "MR", FirstName: "Budi", LastName: "Santoso"},
+			{Title: "MRS", FirstName: "Siti", LastName: "Santoso"},
+		},
+		Contact:    service.ContactInfo{Email: "budi@example.com", Phone: "+628123456789"},
+		Seats:      []service.SeatAssignment{{PassengerIndex: 1, SeatNumber: "12A"}},
+		TotalPrice: service.PriceInfo{Amount: 3700000, Currency: "IDR"},
+		UpdatedAt:  time.Date(2025, 12, 1, 9, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestIssue(t *testing.T) {
+	booking := ticketedBooking()
+
+	tickets, err := ticket.Issue(booking)
+	require.NoError(t, err)
+	require.Len(t, tickets, 4)
+
+	seen := make(map[string]bool)
+	for _, tk := range tickets {
+		assert.Regexp(t, regexp.MustCompile(`^126\d{10}$`), tk.Number)
+		assert.False(t, seen[tk.Number])
+		seen[tk.Number] = true
+	}
+	assert.Equal(t, service.ETicket{Number: tickets[3].Number, PassengerIndex: 1, SegmentIndex: 1, FlightNumber: "GA332", Origin: "SUB", Destination: "DPS"}, tickets[3])
+
+	booking.Flight.Segments = nil
+	booking.Flight.Airline.Code = "ZZ"
+	tickets, err = ticket.Issue(booking)
+	require.NoError(t, err)
+	assert.Len(t, tickets, 2)
+	assert.True(t, strings.HasPrefix(tickets[0].Number, "000"))
+	assert.Equal(t, "GA410", tickets[0].FlightNumber)
+}
+
+func TestRender(t *testing.T) {
+	booking := ticketedBooking()
+	var err error
+	booking.Tickets, err = ticket.Issue(booking)
+	require.NoError(t, err)
+
+	doc, err := ticket.Render(booking, service.TicketFormatHTML)
+	require.NoError(t, err)
+	assert.Equal(t, "eticket-ABC234.html", doc.Filename)
+	html := string(doc.Body)
+	for _, want := range []string{"Garuda Indonesia (GA)", "GA315", "GA332", "Terminal 3", "Terminal I", "Mon, 15 Dec 2025 14:00 WIB", "Mon, 15 Dec 2025 18:45 WITA", "Checked 2 piece(s)", "MRS Siti Santoso", "12A", booking.Tickets[0].Number} {
+		assert.Contains(t, html, want)
+	}
+
+	doc, err = ticket.Render(booking, service.TicketFormatPDF)
+	require.NoError(t, err)
+	assert.Equal(t, "application/pdf", doc.ContentType)
+	assert.True(t, bytes.HasPrefix(doc.Body, []byte("%PDF-1.4")))
+	assert.True(t, bytes.HasSuffix(doc.Body, []byte("%%EOF\n")))
+	for _, want := range []string{"GA315  CGK -> SUB", "Terminal 3", "Mon, 15 Dec 2025 18:45 WITA", "Baggage: Cabin 1 piece\\(s\\)", booking.Tickets[3].Number} {
+		assert.Contains(t, string(doc.Body), want)
+	}
+
+	_, err = ticket.Render(booking, "docx")
+	assert.ErrorIs(t, err, service.ErrUnsupportedFormat)
+}
@@ -0,0 +1,31 @@
+package service
+
+import "errors"
+
+// E-ticket document formats
+const (
+	TicketFormatPDF  = "pdf"
+	TicketFormatHTML = "html"
+)
+
+var (
+	ErrBookingNotTicketed = errors.New("booking has not been ticketed")
+	ErrUnsupportedFormat  = errors.New("unsupported ticket format")
+)
+
+// ETicket is the ticket number of one passenger on one segment of the booked flight
+type ETicket struct {
+	Number         string `json:"number"`
+	PassengerIndex int    `json:"passenger_index"`
+	SegmentIndex   int    `json:"segment_index"`
+	FlightNumber   string `json:"flight_number"`
+	Origin         string `json:"origin"`
+	Destination    string `json:"destination"`
+}
+
+// Document is a rendered file such as an itinerary receipt
+type Document struct {
+	ContentType string
+	Filename    string
+	Body        []byte
+}
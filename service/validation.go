@@ -0,0 +1,23 @@
+package service
+
+import (
+	playground "github.com/go-playground/validator/v10"
+
+	"github.com/elkoshar/bookcabin/pkg/helpers"
+	"github.com/elkoshar/bookcabin/pkg/validator"
+)
+
+func init() {
+	if err := validator.RegisterValidation("airport", isKnownAirport, "is not a known airport or city code"); err != nil {
+		panic(err)
+	}
+}
+
+// isKnownAirport accepts the airports and metro area codes the providers can be searched for
+func isKnownAirport(fl playground.FieldLevel) bool {
+	code := fl.Field().String()
+	if _, ok := helpers.AirportMap[code]; ok {
+		return true
+	}
+	return helpers.IsMetroCode(code)
+}
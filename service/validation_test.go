@@ -0,0 +1,126 @@
+package service_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elkoshar/bookcabin/pkg/validator"
+	"github.com/elkoshar/bookcabin/service"
+)
+
+func TestSearchCriteria_Validation(t *testing.T) {
+	validator.Now = func() time.Time { return time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC) }
+	defer func() { validator.Now = time.Now }()
+
+	oneWay := func() service.SearchCriteria {
+		return service.SearchCriteria{Origin: "CGK", Destination: "DPS", DepartureDate: "2025-12-15", Passengers: 1, CabinClass: "economy"}
+	}
+	multiCity := func() service.SearchCriteria {
+		return service.SearchCriteria{Passengers: 2, CabinClass: "business", Segments: []service.RouteSegment{
+			{Origin: "JKT", Destination: "DPS", DepartureDate: "2025-12-15"},
+			{Origin: "DPS", Destination: "SIN", DepartureDate: "2025-12-17"},
+		}}
+	}
+
+	tests := []struct {
+		name       string
+		criteria   func() service.SearchCriteria
+		wantFields map[string]string
+	}{
+		{name: "one way", criteria: oneWay},
+		{name: "round trip", criteria: func() service.SearchCriteria { c := oneWay(); c.ReturnDate = "2025-12-20"; return c }},
+		{name: "multi city", criteria: multiCity},
+		{
+			name:       "missing route",
+			criteria:   func() service.SearchCriteria { return service.SearchCriteria{Passengers: 1, CabinClass: "economy"} },
+			wantFields: map[string]string{"Origin": "required_without", "Destination": "required_without", "DepartureDate": "required_without"},
+		},
+		{
+			name:       "bad codes",
+			criteria:   func() service.SearchCriteria { c := oneWay(); c.Origin, c.Destination = "Jakarta", "XXX"; return c },
+			wantFields: map[string]string{"Origin": "iata", "Destination": "airport"},
+		},
+		{
+			name:       "same origin and destination",
+			criteria:   func() service.SearchCriteria { c := oneWay(); c.Destination = "CGK"; return c },
+			wantFields: map[string]string{"Destination": "nefield"},
+		},
+		{
+			name:       "past departure",
+			criteria:   func() service.SearchCriteria { c := oneWay(); c.DepartureDate = "2025-11-30"; return c },
+			wantFields: map[string]string{"DepartureDate": "not_past"},
+		},
+		{
+			name:       "return before departure",
+			criteria:   func() service.SearchCriteria { c := oneWay(); c.ReturnDate = "2025-12-14"; return c },
+			wantFields: map[string]string{"ReturnDate": "date_gtefield"},
+		},
+		{
+			name:       "passengers out of range",
+			criteria:   func() service.SearchCriteria { c := oneWay(); c.Passengers = 10; return c },
+			wantFields: map[string]string{"Passengers": "max"},
+		},
+		{
+			name:       "no passengers and unknown cabin",
+			criteria:   func() service.SearchCriteria { c := oneWay(); c.Passengers, c.CabinClass = 0, "coach"; return c },
+			wantFields: map[string]string{"Passengers": "min", "CabinClass": "oneof"},
+		},
+		{
+			name: "too many segments",
+			criteria: func() service.SearchCriteria {
+				c := multiCity()
+				for len(c.Segments) < 7 {
+					c.Segments = append(c.Segments, service.RouteSegment{Origin: "SIN", Destination: "KUL", DepartureDate: "2025-12-20"})
+				}
+				return c
+			},
+			wantFields: map[string]string{"Segments": "max"},
+		},
+		{
+			name:       "segments out of order",
+			criteria:   func() service.SearchCriteria { c := multiCity(); c.Segments[1].DepartureDate = "2025-12-14"; return c },
+			wantFields: map[string]string{"Segments": "chronological"},
+		},
+		{
+			name:       "invalid segment",
+			criteria:   func() service.SearchCriteria { c := multiCity(); c.Segments[1].Destination = "DPS"; return c },
+			wantFields: map[string]string{"Segments[1].Destination": "nefield"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := validator.ValidateStruct(tt.criteria())
+			if len(tt.wantFields) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+
+			var fields validator.Errors
+			require.True(t, errors.As(err, &fields), "error %v is not a validator.Errors", err)
+
+			got := map[string]string{}
+			for _, f := range fields {
+				got[f.Field] = f.Rule
+				assert.NotEmpty(t, f.Message)
+			}
+			assert.Equal(t, tt.wantFields, got)
+		})
+	}
+}
+
+func TestSearchCriteria_Normalize(t *testing.T) {
+	criteria := service.SearchCriteria{Origin: "cgk", Destination: " Dps ", Currency: "usd", Segments: []service.RouteSegment{
+		{Origin: "dps", Destination: "sub", DepartureDate: "2030-12-17"},
+	}}
+	criteria.Normalize()
+
+	assert.Equal(t, "CGK", criteria.Origin)
+	assert.Equal(t, "DPS", criteria.Destination)
+	assert.Equal(t, "USD", criteria.Currency)
+	assert.Equal(t, service.RouteSegment{Origin: "DPS", Destination: "SUB", DepartureDate: "2030-12-17"}, criteria.Segments[0])
+}